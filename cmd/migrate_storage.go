@@ -96,10 +96,12 @@ func migrateLFS(ctx context.Context, dstStorage storage.ObjectStorage) error {
 }
 
 func migrateAvatars(ctx context.Context, dstStorage storage.ObjectStorage) error {
-	return db.Iterate(ctx, nil, func(ctx context.Context, user *user_model.User) error {
-		_, err := storage.Copy(dstStorage, user.CustomAvatarRelativePath(), storage.Avatars, user.CustomAvatarRelativePath())
+	copied, err := user_model.MigrateAvatars(ctx, storage.Avatars, dstStorage)
+	if err != nil {
 		return err
-	})
+	}
+	log.Info("%d avatars have been copied, avatars which already existed in the new storage have been skipped.", copied)
+	return nil
 }
 
 func migrateRepoAvatars(ctx context.Context, dstStorage storage.ObjectStorage) error {
@@ -64,6 +64,8 @@ const (
 	ActionPullReviewDismissed                             // 25
 	ActionPullRequestReadyForReview                       // 26
 	ActionAutoMergePullRequest                            // 27
+	ActionCloseMilestone                                  // 28
+	ActionReopenMilestone                                 // 29
 )
 
 // Action represents user operation type and other information to
@@ -301,6 +303,12 @@ func (a *Action) GetIssueInfos() []string {
 	return strings.SplitN(a.Content, "|", 3)
 }
 
+// GetMilestoneInfos returns the ID and the name of the milestone
+// associated with the action.
+func (a *Action) GetMilestoneInfos() []string {
+	return strings.SplitN(a.Content, "|", 2)
+}
+
 // GetIssueTitle returns the title of first issue associated
 // with the action.
 func (a *Action) GetIssueTitle() string {
@@ -547,7 +555,7 @@ func NotifyWatchers(ctx context.Context, actions ...*Action) error {
 				if !permCode[i] {
 					continue
 				}
-			case ActionCreateIssue, ActionCommentIssue, ActionCloseIssue, ActionReopenIssue:
+			case ActionCreateIssue, ActionCommentIssue, ActionCloseIssue, ActionReopenIssue, ActionCloseMilestone, ActionReopenMilestone:
 				if !permIssue[i] {
 					continue
 				}
@@ -21,6 +21,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -251,6 +252,47 @@ func GetLatestCommitStatus(ctx context.Context, repoID int64, sha string, listOp
 	return statuses, count, db.GetEngine(ctx).In("id", ids).Find(&statuses)
 }
 
+// RepoCommit identifies a commit of a repository
+type RepoCommit struct {
+	RepoID int64
+	SHA    string
+}
+
+// GetLatestCommitStatusesByCommits returns the latest status of each context for each of the given commits,
+// ordered by id desc like GetLatestCommitStatus. Commits without a status are not contained in the map.
+func GetLatestCommitStatusesByCommits(ctx context.Context, commits []RepoCommit) (map[RepoCommit][]*CommitStatus, error) {
+	statusesMap := make(map[RepoCommit][]*CommitStatus, len(commits))
+	if len(commits) == 0 {
+		return statusesMap, nil
+	}
+
+	cond := builder.NewCond()
+	for _, commit := range commits {
+		cond = cond.Or(builder.Eq{"repo_id": commit.RepoID, "sha": commit.SHA})
+	}
+	ids := make([]int64, 0, len(commits))
+	if err := db.GetEngine(ctx).Table(&CommitStatus{}).
+		Where(cond).
+		Select("max( id ) as id").
+		GroupBy("repo_id, sha, context_hash").
+		Find(&ids); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return statusesMap, nil
+	}
+
+	statuses := make([]*CommitStatus, 0, len(ids))
+	if err := db.GetEngine(ctx).In("id", ids).Desc("id").Find(&statuses); err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		commit := RepoCommit{RepoID: status.RepoID, SHA: status.SHA}
+		statusesMap[commit] = append(statusesMap[commit], status)
+	}
+	return statusesMap, nil
+}
+
 // FindRepoRecentCommitStatusContexts returns repository's recent commit status contexts
 func FindRepoRecentCommitStatusContexts(repoID int64, before time.Duration) ([]string, error) {
 	start := timeutil.TimeStampNow().AddDuration(-before)
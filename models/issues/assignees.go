@@ -9,6 +9,7 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 )
 
@@ -62,6 +63,13 @@ func IsUserAssignedToIssue(ctx context.Context, issue *Issue, user *user_model.U
 	return db.GetByBean(ctx, &IssueAssignees{IssueID: issue.ID, AssigneeID: user.ID})
 }
 
+// HasAssigneesChangedSince returns true when a user has been assigned to or unassigned from the issue after the given time
+func HasAssigneesChangedSince(ctx context.Context, issueID int64, since timeutil.TimeStamp) (bool, error) {
+	return db.GetEngine(ctx).
+		Where("issue_id = ? AND type = ? AND created_unix > ?", issueID, CommentTypeAssignees, since).
+		Exist(new(Comment))
+}
+
 // ToggleIssueAssignee changes a user between assigned and not assigned for this issue, and make issue comment for it.
 func ToggleIssueAssignee(issue *Issue, doer *user_model.User, assigneeID int64) (removed bool, comment *Comment, err error) {
 	ctx, committer, err := db.TxContext(db.DefaultContext)
@@ -235,9 +235,9 @@ type Comment struct {
 	Project          *project_model.Project `xorm:"-"`
 	OldMilestoneID   int64
 	MilestoneID      int64
-	OldMilestone     *Milestone `xorm:"-"`
-	Milestone        *Milestone `xorm:"-"`
-	TimeID           int64
+	OldMilestone     *Milestone   `xorm:"-"`
+	Milestone        *Milestone   `xorm:"-"`
+	TimeID           int64        `xorm:"INDEX"`
 	Time             *TrackedTime `xorm:"-"`
 	AssigneeID       int64
 	RemovedAssignee  bool
@@ -287,6 +287,9 @@ type Comment struct {
 	RefAction    references.XRefAction `xorm:"SMALLINT"` // What happens if RefIssueID resolves
 	RefIsPull    bool
 
+	// ID of the saved reply the content was created from, 0 if it was written by hand
+	SavedReplyID int64 `xorm:"NOT NULL DEFAULT 0"`
+
 	RefRepo    *repo_model.Repository `xorm:"-"`
 	RefIssue   *Issue                 `xorm:"-"`
 	RefComment *Comment               `xorm:"-"`
@@ -820,6 +823,7 @@ func CreateCommentCtx(ctx context.Context, opts *CreateCommentOptions) (_ *Comme
 		RefIsPull:        opts.RefIsPull,
 		IsForcePush:      opts.IsForcePush,
 		Invalidated:      opts.Invalidated,
+		SavedReplyID:     opts.SavedReplyID,
 	}
 	if _, err = e.Insert(comment); err != nil {
 		return nil, err
@@ -989,6 +993,7 @@ type CreateCommentOptions struct {
 	RefIsPull        bool
 	IsForcePush      bool
 	Invalidated      bool
+	SavedReplyID     int64
 }
 
 // CreateComment creates comment of issue or commit.
@@ -1114,6 +1119,28 @@ func FindComments(ctx context.Context, opts *FindCommentsOptions) ([]*Comment, e
 		Find(&comments)
 }
 
+// GetLatestCommentsByType returns the latest comment of one of the given types of each of the issues by issue ID,
+// issues without such a comment are not contained
+func GetLatestCommentsByType(ctx context.Context, issueIDs []int64, types ...CommentType) (map[int64]*Comment, error) {
+	latest := make(map[int64]*Comment, len(issueIDs))
+	if len(issueIDs) == 0 {
+		return latest, nil
+	}
+
+	comments := make([]*Comment, 0, len(issueIDs))
+	if err := db.GetEngine(ctx).
+		In("id", builder.Select("MAX(id)").From("comment").
+			Where(builder.In("issue_id", issueIDs).And(builder.In("type", types))).
+			GroupBy("issue_id")).
+		Find(&comments); err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		latest[comment.IssueID] = comment
+	}
+	return latest, nil
+}
+
 // CountComments count all comments according options by ignoring pagination
 func CountComments(opts *FindCommentsOptions) (int64, error) {
 	sess := db.GetEngine(db.DefaultContext).Where(opts.toConds())
@@ -1162,7 +1189,8 @@ func DeleteComment(ctx context.Context, comment *Comment) error {
 	}
 
 	if comment.Type == CommentTypeComment {
-		if _, err := e.ID(comment.IssueID).Decr("num_comments").Update(new(Issue)); err != nil {
+		// recount instead of decrementing, so that the counter cannot drift
+		if err := UpdateIssueNumComments(ctx, comment.IssueID); err != nil {
 			return err
 		}
 	}
@@ -1181,6 +1209,13 @@ func DeleteComment(ctx context.Context, comment *Comment) error {
 	return DeleteReaction(ctx, &ReactionOptions{CommentID: comment.ID})
 }
 
+// UpdateIssueNumComments recalculates the number of comments of the issue from its comments.
+// It is also used by CheckRepoStats to repair counters which have drifted.
+func UpdateIssueNumComments(ctx context.Context, issueID int64) error {
+	_, err := db.GetEngine(ctx).Exec("UPDATE `issue` SET num_comments=(SELECT COUNT(*) FROM `comment` WHERE issue_id=? AND type=?) WHERE id=?", issueID, CommentTypeComment, issueID)
+	return err
+}
+
 // CodeComments represents comments on code by using this structure: FILENAME -> LINE (+ == proposed; - == previous) -> COMMENTS
 type CodeComments map[string]map[int64][]*Comment
 
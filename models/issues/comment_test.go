@@ -62,3 +62,13 @@ func TestFetchCodeComments(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, res, 1)
 }
+
+func TestUpdateIssueNumComments(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	_, err := db.GetEngine(db.DefaultContext).ID(2).Cols("num_comments").Update(&issues_model.Issue{NumComments: 3})
+	assert.NoError(t, err)
+
+	assert.NoError(t, issues_model.UpdateIssueNumComments(db.DefaultContext, 2))
+	assert.EqualValues(t, 0, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}).NumComments)
+}
@@ -11,6 +11,8 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
 )
 
 // ErrDependencyExists represents a "DependencyAlreadyExists" kind of error.
@@ -208,6 +210,18 @@ func issueDepExists(ctx context.Context, issueID, depID int64) (bool, error) {
 	return db.GetEngine(ctx).Where("(issue_id = ? AND dependency_id = ?)", issueID, depID).Exist(&IssueDependency{})
 }
 
+// GetIssueDependenciesByIssueIDs returns the dependencies in which any of the given issues is either
+// the blocked issue or the blocking one
+func GetIssueDependenciesByIssueIDs(ctx context.Context, issueIDs []int64) ([]*IssueDependency, error) {
+	deps := make([]*IssueDependency, 0, len(issueIDs))
+	if len(issueIDs) == 0 {
+		return deps, nil
+	}
+	return deps, db.GetEngine(ctx).
+		Where(builder.In("issue_id", issueIDs).Or(builder.In("dependency_id", issueIDs))).
+		Find(&deps)
+}
+
 // IssueNoDependenciesLeft checks if issue can be closed
 func IssueNoDependenciesLeft(ctx context.Context, issue *Issue) (bool, error) {
 	exists, err := db.GetEngine(ctx).
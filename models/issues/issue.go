@@ -103,6 +103,32 @@ func (err ErrIssueWasClosed) Error() string {
 	return fmt.Sprintf("Issue [%d] %d was already closed", err.ID, err.Index)
 }
 
+// ErrIssueInvalidDuplicate is used when an issue is closed as a duplicate of itself or of an issue of another repository
+type ErrIssueInvalidDuplicate struct {
+	ID            int64
+	DuplicateOfID int64
+}
+
+// IsErrIssueInvalidDuplicate checks if an error is a ErrIssueInvalidDuplicate.
+func IsErrIssueInvalidDuplicate(err error) bool {
+	_, ok := err.(ErrIssueInvalidDuplicate)
+	return ok
+}
+
+func (err ErrIssueInvalidDuplicate) Error() string {
+	return fmt.Sprintf("issue [%d] cannot be a duplicate of issue [%d]", err.ID, err.DuplicateOfID)
+}
+
+// IssueClosedReason is the reason an issue has been closed for
+type IssueClosedReason string
+
+const (
+	// IssueClosedReasonCompleted marks an issue which has been resolved
+	IssueClosedReasonCompleted IssueClosedReason = "completed"
+	// IssueClosedReasonNotPlanned marks an issue which will not be worked on
+	IssueClosedReasonNotPlanned IssueClosedReason = "not_planned"
+)
+
 // Issue represents an issue or pull request of repository.
 type Issue struct {
 	ID               int64                  `xorm:"pk autoincr"`
@@ -120,6 +146,7 @@ type Issue struct {
 	MilestoneID      int64                  `xorm:"INDEX"`
 	Milestone        *Milestone             `xorm:"-"`
 	Project          *project_model.Project `xorm:"-"`
+	ProjectBoard     *project_model.Board   `xorm:"-"`
 	Priority         int
 	AssigneeID       int64            `xorm:"-"`
 	Assignee         *user_model.User `xorm:"-"`
@@ -131,6 +158,12 @@ type Issue struct {
 	Ref              string
 
 	DeadlineUnix timeutil.TimeStamp `xorm:"INDEX"`
+	// ClosedReason is the reason a closed issue has been closed for, empty for open issues
+	ClosedReason IssueClosedReason `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	// DuplicateOfID is the ID of the issue this one has been closed as a duplicate of, 0 if it is none
+	DuplicateOfID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// Estimate is the estimated time to resolve the issue in seconds, 0 if there is none
+	Estimate int64 `xorm:"NOT NULL DEFAULT 0"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -146,6 +179,11 @@ type Issue struct {
 	// IsLocked limits commenting abilities to users on an issue
 	// with write access
 	IsLocked bool `xorm:"NOT NULL DEFAULT false"`
+	// LockReason is the reason given when the issue was locked, empty for unlocked issues
+	LockReason string
+
+	// Template is the file name of the issue form template the issue was created from
+	Template string
 
 	// For view issue page.
 	ShowRole RoleDescriptor `xorm:"-"`
@@ -291,6 +329,11 @@ func (issue *Issue) loadCommentsByType(ctx context.Context, tp CommentType) (err
 	return err
 }
 
+// LoadReactions loads the reactions of the issue and of its loaded comments
+func (issue *Issue) LoadReactions(ctx context.Context) error {
+	return issue.loadReactions(ctx)
+}
+
 func (issue *Issue) loadReactions(ctx context.Context) (err error) {
 	if issue.Reactions != nil {
 		return nil
@@ -581,12 +624,23 @@ func ReplaceIssueLabels(issue *Issue, labels []*Label, doer *user_model.User) (e
 	}
 	defer committer.Close()
 
-	if err = issue.LoadRepo(ctx); err != nil {
+	if _, _, err = SetIssueLabels(ctx, issue, labels, doer); err != nil {
 		return err
 	}
 
+	return committer.Commit()
+}
+
+// SetIssueLabels changes the labels of the issue to the given labels and returns the labels
+// which have been added and removed. Labels not belonging to the repository or its owner are dropped.
+func SetIssueLabels(ctx context.Context, issue *Issue, labels []*Label, doer *user_model.User) (added, removed []*Label, err error) {
+	if err = issue.LoadRepo(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	issue.Labels = nil
 	if err = issue.LoadLabels(ctx); err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	sort.Sort(labelSorter(labels))
@@ -617,27 +671,31 @@ func ReplaceIssueLabels(issue *Issue, labels []*Label, doer *user_model.User) (e
 			removeIndex++
 		}
 	}
-	toAdd = append(toAdd, labels[addIndex:]...)
+	for _, addLabel := range labels[addIndex:] {
+		if addLabel.RepoID == issue.RepoID || addLabel.OrgID == issue.Repo.OwnerID {
+			toAdd = append(toAdd, addLabel)
+		}
+	}
 	toRemove = append(toRemove, issue.Labels[removeIndex:]...)
 
 	if len(toAdd) > 0 {
 		if err = newIssueLabels(ctx, issue, toAdd, doer); err != nil {
-			return fmt.Errorf("addLabels: %w", err)
+			return nil, nil, fmt.Errorf("addLabels: %w", err)
 		}
 	}
 
 	for _, l := range toRemove {
 		if err = deleteIssueLabel(ctx, issue, l, doer); err != nil {
-			return fmt.Errorf("removeLabel: %w", err)
+			return nil, nil, fmt.Errorf("removeLabel: %w", err)
 		}
 	}
 
 	issue.Labels = nil
 	if err = issue.LoadLabels(ctx); err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	return committer.Commit()
+	return toAdd, toRemove, nil
 }
 
 // UpdateIssueCols updates cols of issue
@@ -687,11 +745,17 @@ func doChangeIssueStatus(ctx context.Context, issue *Issue, doer *user_model.Use
 
 	if issue.IsClosed {
 		issue.ClosedUnix = timeutil.TimeStampNow()
+		if issue.ClosedReason == "" {
+			issue.ClosedReason = IssueClosedReasonCompleted
+		}
 	} else {
 		issue.ClosedUnix = 0
+		issue.ClosedReason = ""
+		// a reopened issue is no duplicate anymore
+		issue.DuplicateOfID = 0
 	}
 
-	if err := UpdateIssueCols(ctx, issue, "is_closed", "closed_unix"); err != nil {
+	if err := UpdateIssueCols(ctx, issue, "is_closed", "closed_unix", "closed_reason", "duplicate_of_id"); err != nil {
 		return nil, err
 	}
 
@@ -1162,6 +1226,15 @@ func GetIssuesByIDs(ctx context.Context, issueIDs []int64) ([]*Issue, error) {
 	return issues, db.GetEngine(ctx).In("id", issueIDs).Find(&issues)
 }
 
+// GetDuplicatesOfIssues returns the issues which have been closed as a duplicate of any of the given issues.
+func GetDuplicatesOfIssues(ctx context.Context, issueIDs []int64) (IssueList, error) {
+	issues := make([]*Issue, 0, 10)
+	if len(issueIDs) == 0 {
+		return issues, nil
+	}
+	return issues, db.GetEngine(ctx).In("duplicate_of_id", issueIDs).Find(&issues)
+}
+
 // GetIssueIDsByRepoID returns all issue ids by repo id
 func GetIssueIDsByRepoID(ctx context.Context, repoID int64) ([]int64, error) {
 	ids := make([]int64, 0, 10)
@@ -1198,11 +1271,13 @@ type IssuesOptions struct { //nolint
 	Org            *organization.Organization // issues permission scope
 	Team           *organization.Team         // issues permission scope
 	User           *user_model.User           // issues permission scope
+	// only count reactions given after this time when sorting by reaction score
+	ReactionsAfterUnix int64
 }
 
 // sortIssuesSession sort an issues-related session based on the provided
 // sortType string
-func sortIssuesSession(sess *xorm.Session, sortType string, priorityRepoID int64) {
+func sortIssuesSession(sess *xorm.Session, sortType string, priorityRepoID, reactionsAfterUnix int64) {
 	switch sortType {
 	case "oldest":
 		sess.Asc("issue.created_unix").Asc("issue.id")
@@ -1242,6 +1317,20 @@ func sortIssuesSession(sess *xorm.Session, sortType string, priorityRepoID int64
 			Desc("issue.id")
 	case "project-column-sorting":
 		sess.Asc("project_issue.sorting").Desc("issue.created_unix").Desc("issue.id")
+	case "reactionscore":
+		// the score is the number of thumbs up minus the number of thumbs down given to the issue itself
+		cond := builder.Eq{"comment_id": 0}.And(builder.In("type", "+1", "-1"))
+		if reactionsAfterUnix > 0 {
+			cond = cond.And(builder.Gte{"created_unix": reactionsAfterUnix})
+		}
+		sess.Join("LEFT", builder.
+			Select("issue_id, SUM(CASE WHEN type = '+1' THEN 1 ELSE -1 END) AS score").
+			From("reaction").
+			Where(cond).
+			GroupBy("issue_id"), "`reaction`.issue_id = `issue`.id").
+			OrderBy("COALESCE(`reaction`.score, 0) DESC").
+			Desc("issue.created_unix").
+			Desc("issue.id")
 	default:
 		sess.Desc("issue.created_unix").Desc("issue.id")
 	}
@@ -1542,7 +1631,7 @@ func Issues(ctx context.Context, opts *IssuesOptions) ([]*Issue, error) {
 		Join("INNER", "repository", "`issue`.repo_id = `repository`.id")
 	opts.setupSessionWithLimit(sess)
 
-	sortIssuesSession(sess, opts.SortType, opts.PriorityRepoID)
+	sortIssuesSession(sess, opts.SortType, opts.PriorityRepoID, opts.ReactionsAfterUnix)
 
 	issues := make([]*Issue, 0, opts.ListOptions.PageSize)
 	if err := sess.Find(&issues); err != nil {
@@ -1581,6 +1670,71 @@ func GetParticipantsIDsByIssueID(ctx context.Context, issueID int64) ([]int64, e
 		Find(&userIDs)
 }
 
+// GetParticipantsIDsByIssueIDs returns the IDs of the users who participated in comments of the issues like
+// GetParticipantsIDsByIssueID, mapped by issue ID and ordered by their first comment.
+func GetParticipantsIDsByIssueIDs(ctx context.Context, issueIDs []int64) (map[int64][]int64, error) {
+	participants := make([]*struct {
+		IssueID  int64
+		PosterID int64
+	}, 0, len(issueIDs))
+	if err := db.GetEngine(ctx).
+		Table("comment").
+		Select("issue_id, poster_id").
+		In("issue_id", issueIDs).
+		And("type in (?,?,?)", CommentTypeComment, CommentTypeCode, CommentTypeReview).
+		And("poster_id > 0").
+		GroupBy("issue_id, poster_id").
+		OrderBy("MIN(id)").
+		Find(&participants); err != nil {
+		return nil, err
+	}
+
+	participantsMap := make(map[int64][]int64, len(issueIDs))
+	for _, p := range participants {
+		participantsMap[p.IssueID] = append(participantsMap[p.IssueID], p.PosterID)
+	}
+	return participantsMap, nil
+}
+
+// GetLastResponseTimesByIssueIDs returns the time of the latest comment or review on each issue which was not
+// written by the poster of the issue, keyed by issue ID. Only comments of individual users are responses,
+// issues without a response are not contained.
+func GetLastResponseTimesByIssueIDs(ctx context.Context, issueIDs []int64) (map[int64]timeutil.TimeStamp, error) {
+	return getResponseTimesByIssueIDs(ctx, issueIDs, "MAX")
+}
+
+// GetFirstResponseTimesByIssueIDs returns the time of the first response on each issue like GetLastResponseTimesByIssueIDs.
+func GetFirstResponseTimesByIssueIDs(ctx context.Context, issueIDs []int64) (map[int64]timeutil.TimeStamp, error) {
+	return getResponseTimesByIssueIDs(ctx, issueIDs, "MIN")
+}
+
+// getResponseTimesByIssueIDs returns the response time picked by the given SQL aggregate function, MIN or MAX.
+func getResponseTimesByIssueIDs(ctx context.Context, issueIDs []int64, aggregate string) (map[int64]timeutil.TimeStamp, error) {
+	responses := make([]*struct {
+		IssueID     int64
+		CreatedUnix timeutil.TimeStamp
+	}, 0, len(issueIDs))
+	if err := db.GetEngine(ctx).
+		Table("comment").
+		Join("INNER", "issue", "issue.id = comment.issue_id").
+		Join("INNER", "`user`", "`user`.id = comment.poster_id").
+		Select("comment.issue_id AS issue_id, "+aggregate+"(comment.created_unix) AS created_unix").
+		In("comment.issue_id", issueIDs).
+		And("comment.type in (?,?)", CommentTypeComment, CommentTypeReview).
+		And("comment.poster_id != issue.poster_id").
+		And("`user`.type = ?", user_model.UserTypeIndividual).
+		GroupBy("comment.issue_id").
+		Find(&responses); err != nil {
+		return nil, err
+	}
+
+	responseTimes := make(map[int64]timeutil.TimeStamp, len(responses))
+	for _, r := range responses {
+		responseTimes[r.IssueID] = r.CreatedUnix
+	}
+	return responseTimes, nil
+}
+
 // IsUserParticipantsOfIssue return true if user is participants of an issue
 func IsUserParticipantsOfIssue(user *user_model.User, issue *Issue) bool {
 	userIDs, err := issue.GetParticipantIDsByIssue(db.DefaultContext)
@@ -1990,7 +2144,7 @@ func UpdateIssueByAPI(issue *Issue, doer *user_model.User) (statusChangeComment
 
 	if _, err := db.GetEngine(ctx).ID(issue.ID).Cols(
 		"name", "content", "milestone_id", "priority",
-		"deadline_unix", "updated_unix", "is_locked").
+		"deadline_unix", "updated_unix", "is_locked", "duplicate_of_id", "estimate").
 		Update(issue); err != nil {
 		return nil, false, err
 	}
@@ -2454,3 +2608,15 @@ func DeleteOrphanedIssues(ctx context.Context) error {
 	}
 	return nil
 }
+
+// GetFirstIssueIDsOfPosters returns the IDs of the first issue or pull request each of the posters opened in
+// each of the repositories
+func GetFirstIssueIDsOfPosters(ctx context.Context, repoIDs, posterIDs []int64) ([]int64, error) {
+	issueIDs := make([]int64, 0, len(posterIDs))
+	return issueIDs, db.GetEngine(ctx).Table("issue").
+		Select("MIN(id)").
+		In("repo_id", repoIDs).
+		In("poster_id", posterIDs).
+		GroupBy("repo_id, poster_id").
+		Find(&issueIDs)
+}
@@ -551,6 +551,11 @@ func (issues IssueList) LoadAttributes() error {
 	return issues.loadAttributes(db.DefaultContext)
 }
 
+// LoadPosters loads the posters of the issues
+func (issues IssueList) LoadPosters(ctx context.Context) error {
+	return issues.loadPosters(ctx)
+}
+
 // LoadComments loads comments
 func (issues IssueList) LoadComments(ctx context.Context) error {
 	return issues.loadComments(ctx, builder.NewCond())
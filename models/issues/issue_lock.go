@@ -4,6 +4,8 @@
 package issues
 
 import (
+	"context"
+
 	"code.gitea.io/gitea/models/db"
 	user_model "code.gitea.io/gitea/models/user"
 )
@@ -35,8 +37,10 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 	var commentType CommentType
 	if opts.Issue.IsLocked {
 		commentType = CommentTypeLock
+		opts.Issue.LockReason = opts.Reason
 	} else {
 		commentType = CommentTypeUnlock
+		opts.Issue.LockReason = ""
 	}
 
 	ctx, committer, err := db.TxContext(db.DefaultContext)
@@ -45,7 +49,7 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 	}
 	defer committer.Close()
 
-	if err := UpdateIssueCols(ctx, opts.Issue, "is_locked"); err != nil {
+	if err := UpdateIssueCols(ctx, opts.Issue, "is_locked", "lock_reason"); err != nil {
 		return err
 	}
 
@@ -62,3 +66,29 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 
 	return committer.Commit()
 }
+
+// GetLockCommentsByIssueIDs returns the latest lock comment of each of the issues with their posters loaded,
+// the map is keyed by issue ID. Issues locked without a comment, e.g. by a migration, are not contained.
+func GetLockCommentsByIssueIDs(ctx context.Context, issueIDs []int64) (map[int64]*Comment, error) {
+	comments := make(CommentList, 0, len(issueIDs))
+	if err := db.GetEngine(ctx).
+		In("issue_id", issueIDs).
+		And("type = ?", CommentTypeLock).
+		Asc("id").
+		Find(&comments); err != nil {
+		return nil, err
+	}
+
+	latest := make(map[int64]*Comment, len(comments))
+	for _, comment := range comments {
+		latest[comment.IssueID] = comment
+	}
+	lockComments := make(CommentList, 0, len(latest))
+	for _, comment := range latest {
+		lockComments = append(lockComments, comment)
+	}
+	if err := lockComments.LoadPosters(ctx); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
@@ -59,6 +59,28 @@ func (issue *Issue) projectBoardID(ctx context.Context) int64 {
 	return ip.ProjectBoardID
 }
 
+// LoadProjectBoard loads the project board the issue is placed in. It is left nil if the issue
+// is not part of a project or has not been moved to a specific board.
+func (issue *Issue) LoadProjectBoard(ctx context.Context) error {
+	if issue.ProjectBoard != nil {
+		return nil
+	}
+
+	boardID := issue.projectBoardID(ctx)
+	if boardID == 0 {
+		return nil
+	}
+	board, err := project_model.GetBoard(ctx, boardID)
+	if err != nil {
+		if project_model.IsErrProjectBoardNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	issue.ProjectBoard = board
+	return nil
+}
+
 // LoadIssuesFromBoard load issues assigned to this board
 func LoadIssuesFromBoard(ctx context.Context, b *project_model.Board) (IssueList, error) {
 	issueList := make([]*Issue, 0, 10)
@@ -198,6 +198,54 @@ func TestIssues(t *testing.T) {
 	}
 }
 
+func TestIssues_ReactionScore(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	const old, recent = 1000, 3000
+	for _, reaction := range []*issues_model.Reaction{
+		{Type: "+1", IssueID: 3, UserID: 1, CreatedUnix: recent},
+		{Type: "+1", IssueID: 3, UserID: 2, CreatedUnix: recent},
+		{Type: "+1", IssueID: 1, UserID: 1, CreatedUnix: recent},
+		{Type: "+1", IssueID: 1, UserID: 2, CreatedUnix: recent},
+		{Type: "-1", IssueID: 1, UserID: 4, CreatedUnix: recent},
+		{Type: "-1", IssueID: 5, UserID: 1, CreatedUnix: recent},
+		{Type: "+1", IssueID: 11, UserID: 1, CreatedUnix: old},
+		{Type: "+1", IssueID: 11, UserID: 2, CreatedUnix: old},
+		{Type: "+1", IssueID: 11, UserID: 4, CreatedUnix: old},
+		// reactions to comments do not count for the issue
+		{Type: "+1", IssueID: 2, CommentID: 3, UserID: 1, CreatedUnix: recent},
+		{Type: "+1", IssueID: 2, CommentID: 3, UserID: 2, CreatedUnix: recent},
+		{Type: "+1", IssueID: 2, CommentID: 3, UserID: 4, CreatedUnix: recent},
+	} {
+		_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(reaction)
+		assert.NoError(t, err)
+	}
+
+	issueIDs := func(issues []*issues_model.Issue) []int64 {
+		ids := make([]int64, 0, len(issues))
+		for _, issue := range issues {
+			ids = append(ids, issue.ID)
+		}
+		return ids
+	}
+
+	issues, err := issues_model.Issues(db.DefaultContext, &issues_model.IssuesOptions{
+		RepoID:   1,
+		SortType: "reactionscore",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{11, 3, 1, 2, 5}, issueIDs(issues))
+
+	// issues without reactions in the window have the same score and the most recent comes first
+	issues, err = issues_model.Issues(db.DefaultContext, &issues_model.IssuesOptions{
+		RepoID:             1,
+		SortType:           "reactionscore",
+		ReactionsAfterUnix: 2000,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1, 11, 2, 5}, issueIDs(issues))
+}
+
 func TestGetUserIssueStats(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	for _, test := range []struct {
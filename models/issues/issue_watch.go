@@ -124,6 +124,35 @@ func CountIssueWatchers(ctx context.Context, issueID int64) (int64, error) {
 		Join("INNER", "`user`", "`user`.id = `issue_watch`.user_id").Count(new(IssueWatch))
 }
 
+// CountIssueWatchersByIssueIDs counts the watchers of each of the given issues like CountIssueWatchers,
+// issues without watchers are not contained
+func CountIssueWatchersByIssueIDs(ctx context.Context, issueIDs []int64) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(issueIDs))
+	if len(issueIDs) == 0 {
+		return counts, nil
+	}
+
+	rows := make([]*struct {
+		IssueID int64
+		Count   int64
+	}, 0, len(issueIDs))
+	if err := db.GetEngine(ctx).Table("issue_watch").
+		Join("INNER", "`user`", "`user`.id = `issue_watch`.user_id").
+		In("`issue_watch`.issue_id", issueIDs).
+		And("`issue_watch`.is_watching = ?", true).
+		And("`user`.is_active = ?", true).
+		And("`user`.prohibit_login = ?", false).
+		GroupBy("`issue_watch`.issue_id").
+		Select("`issue_watch`.issue_id AS issue_id, COUNT(*) AS count").
+		Find(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.IssueID] = row.Count
+	}
+	return counts, nil
+}
+
 // RemoveIssueWatchersByRepoID remove issue watchers by repoID
 func RemoveIssueWatchersByRepoID(ctx context.Context, userID, repoID int64) error {
 	_, err := db.GetEngine(ctx).
@@ -354,3 +354,59 @@ func (pr *PullRequest) ResolveCrossReferences(ctx context.Context) ([]*Comment,
 
 	return refs, nil
 }
+
+// CrossReferenceSource is an issue or pull request referencing another issue
+type CrossReferenceSource struct {
+	IssueID    int64
+	RefRepoID  int64
+	RefIssueID int64
+	RefIsPull  bool
+}
+
+// GetCrossReferenceSources returns the distinct issues and pull requests referencing each of the given issues,
+// references which have been removed from their origin are ignored.
+func GetCrossReferenceSources(ctx context.Context, issueIDs []int64) (map[int64][]*CrossReferenceSource, error) {
+	sourcesMap := make(map[int64][]*CrossReferenceSource, len(issueIDs))
+	if len(issueIDs) == 0 {
+		return sourcesMap, nil
+	}
+
+	sources := make([]*CrossReferenceSource, 0, len(issueIDs))
+	if err := db.GetEngine(ctx).Table("comment").
+		Select("issue_id, ref_repo_id, ref_issue_id, ref_is_pull").
+		In("issue_id", issueIDs).
+		In("type", CommentTypeIssueRef, CommentTypeCommentRef, CommentTypePullRef).
+		And("ref_action <> ?", references.XRefActionNeutered).
+		GroupBy("issue_id, ref_repo_id, ref_issue_id, ref_is_pull").
+		Find(&sources); err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		sourcesMap[source.IssueID] = append(sourcesMap[source.IssueID], source)
+	}
+	return sourcesMap, nil
+}
+
+// GetCrossReferenceTargets returns the distinct issues and pull requests referenced by each of the given issues,
+// keyed by the referencing issue. References which have been removed from their origin are ignored.
+func GetCrossReferenceTargets(ctx context.Context, issueIDs []int64) (map[int64][]*CrossReferenceSource, error) {
+	targetsMap := make(map[int64][]*CrossReferenceSource, len(issueIDs))
+	if len(issueIDs) == 0 {
+		return targetsMap, nil
+	}
+
+	targets := make([]*CrossReferenceSource, 0, len(issueIDs))
+	if err := db.GetEngine(ctx).Table("comment").
+		Select("issue_id, ref_repo_id, ref_issue_id, ref_is_pull").
+		In("ref_issue_id", issueIDs).
+		In("type", CommentTypeIssueRef, CommentTypeCommentRef, CommentTypePullRef).
+		And("ref_action <> ?", references.XRefActionNeutered).
+		GroupBy("issue_id, ref_repo_id, ref_issue_id, ref_is_pull").
+		Find(&targets); err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		targetsMap[target.RefIssueID] = append(targetsMap[target.RefIssueID], target)
+	}
+	return targetsMap, nil
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	project_model "code.gitea.io/gitea/models/project"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
@@ -80,6 +81,47 @@ func (err ErrLabelNotExist) Unwrap() error {
 	return util.ErrNotExist
 }
 
+// ErrLabelExist represents an error that a label with the same name already exists in the repository or organization
+type ErrLabelExist struct {
+	RepoID int64
+	OrgID  int64
+	Name   string
+}
+
+// IsErrLabelExist checks if an error is a ErrLabelExist.
+func IsErrLabelExist(err error) bool {
+	_, ok := err.(ErrLabelExist)
+	return ok
+}
+
+func (err ErrLabelExist) Error() string {
+	return fmt.Sprintf("label already exists [repo_id: %d, org_id: %d, name: %s]", err.RepoID, err.OrgID, err.Name)
+}
+
+func (err ErrLabelExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// ErrLabelScopeConflict represents an error that an issue would carry two labels of the same exclusive scope
+type ErrLabelScopeConflict struct {
+	IssueID int64
+	Scope   string
+}
+
+// IsErrLabelScopeConflict checks if an error is a ErrLabelScopeConflict.
+func IsErrLabelScopeConflict(err error) bool {
+	_, ok := err.(ErrLabelScopeConflict)
+	return ok
+}
+
+func (err ErrLabelScopeConflict) Error() string {
+	return fmt.Sprintf("issue already has a label of the exclusive scope [issue_id: %d, scope: %s]", err.IssueID, err.Scope)
+}
+
+func (err ErrLabelScopeConflict) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
 // LabelColorPattern is a regexp witch can validate LabelColor
 var LabelColorPattern = regexp.MustCompile("^#?(?:[0-9a-fA-F]{6}|[0-9a-fA-F]{3})$")
 
@@ -93,6 +135,7 @@ type Label struct {
 	Color           string `xorm:"VARCHAR(7)"`
 	NumIssues       int
 	NumClosedIssues int
+	Order           int                `xorm:"'sort_order' NOT NULL DEFAULT 0"`
 	CreatedUnix     timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix     timeutil.TimeStamp `xorm:"INDEX updated"`
 
@@ -159,6 +202,22 @@ func (label *Label) BelongsToRepo() bool {
 	return label.RepoID > 0
 }
 
+// ExclusiveScope returns the scope of a label named like "scope/name", see LabelScope
+func (label *Label) ExclusiveScope() string {
+	return LabelScope(label.Name)
+}
+
+// LabelScope returns the scope of a label with the given name, which is the part before the last slash,
+// or an empty string if the label is not scoped. The labels of a scope are exclusive, an issue should
+// carry at most one of them.
+func LabelScope(name string) string {
+	lastIndex := strings.LastIndex(name, "/")
+	if lastIndex <= 0 || lastIndex == len(name)-1 {
+		return ""
+	}
+	return name[:lastIndex]
+}
+
 // SrgbToLinear converts a component of an sRGB color to its linear intensity
 // See: https://en.wikipedia.org/wiki/SRGB#The_reverse_transformation_(sRGB_to_CIE_XYZ)
 func SrgbToLinear(color uint8) float64 {
@@ -184,52 +243,97 @@ func Luminance(color uint32) float64 {
 // i.e. math.Sqrt(1.05*0.05) - 0.05
 const LuminanceThreshold float64 = 0.179
 
+// parseLabelColor parses a 3- or 6-digit hex color with an optional leading hash into its RGB value
+func parseLabelColor(color string) (uint32, bool) {
+	color = strings.TrimPrefix(color, "#")
+	if len(color) == 3 {
+		color = string([]byte{color[0], color[0], color[1], color[1], color[2], color[2]})
+	}
+	if len(color) != 6 {
+		return 0, false
+	}
+	rgb, err := strconv.ParseUint(color, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(rgb), true
+}
+
+// UseLightTextColor returns whether the label background is dark enough to prefer a light text color,
+// invalid colors fall back to a dark text color.
+func (label *Label) UseLightTextColor() bool {
+	color, ok := parseLabelColor(label.Color)
+	if !ok {
+		return false
+	}
+	// NOTE: see web_src/js/components/ContextPopup.vue for similar implementation
+	// prefer white or black based upon contrast
+	return Luminance(color) < LuminanceThreshold
+}
+
 // ForegroundColor calculates the text color for labels based
 // on their background color.
 func (label *Label) ForegroundColor() template.CSS {
-	if strings.HasPrefix(label.Color, "#") {
-		if color, err := strconv.ParseUint(label.Color[1:], 16, 64); err == nil {
-			// NOTE: see web_src/js/components/ContextPopup.vue for similar implementation
-			luminance := Luminance(uint32(color))
-
-			// prefer white or black based upon contrast
-			if luminance < LuminanceThreshold {
-				return template.CSS("#fff")
-			}
-			return template.CSS("#000")
-		}
+	if label.UseLightTextColor() {
+		return template.CSS("#fff")
 	}
-
-	// default to black
 	return template.CSS("#000")
 }
 
-// NewLabel creates a new label
-func NewLabel(ctx context.Context, label *Label) error {
-	if !LabelColorPattern.MatchString(label.Color) {
-		return fmt.Errorf("bad color code: %s", label.Color)
+// NormalizeLabelColor converts a color code matching LabelColorPattern into the
+// lower case 6-character form with a leading hash labels are stored with.
+func NormalizeLabelColor(color string) (string, error) {
+	if !LabelColorPattern.MatchString(color) {
+		return "", fmt.Errorf("bad color code: %s", color)
 	}
 
 	// normalize case
-	label.Color = strings.ToLower(label.Color)
+	color = strings.ToLower(color)
 
 	// add leading hash
-	if label.Color[0] != '#' {
-		label.Color = "#" + label.Color
+	if color[0] != '#' {
+		color = "#" + color
 	}
 
 	// convert 3-character shorthand into 6-character version
-	if len(label.Color) == 4 {
-		r := label.Color[1]
-		g := label.Color[2]
-		b := label.Color[3]
-		label.Color = fmt.Sprintf("#%c%c%c%c%c%c", r, r, g, g, b, b)
+	if len(color) == 4 {
+		r := color[1]
+		g := color[2]
+		b := color[3]
+		color = fmt.Sprintf("#%c%c%c%c%c%c", r, r, g, g, b, b)
 	}
+	return color, nil
+}
+
+// NewLabel creates a new label
+func NewLabel(ctx context.Context, label *Label) error {
+	color, err := NormalizeLabelColor(label.Color)
+	if err != nil {
+		return err
+	}
+	label.Color = color
+
+	// new labels are appended to the custom order of the labels
+	maxOrder, err := getMaxLabelOrder(ctx, label.RepoID, label.OrgID)
+	if err != nil {
+		return err
+	}
+	label.Order = maxOrder + 1
 
 	return db.Insert(ctx, label)
 }
 
-// NewLabels creates new labels
+// getMaxLabelOrder returns the highest position in the custom order of the labels of a repository or organization
+func getMaxLabelOrder(ctx context.Context, repoID, orgID int64) (int, error) {
+	var maxOrder int
+	_, err := db.GetEngine(ctx).Table("label").
+		Where("repo_id = ? AND org_id = ?", repoID, orgID).
+		Select("COALESCE(MAX(sort_order), 0)").
+		Get(&maxOrder)
+	return maxOrder, err
+}
+
+// NewLabels creates new labels, they are appended to the custom order of the labels in the given order
 func NewLabels(labels ...*Label) error {
 	ctx, committer, err := db.TxContext(db.DefaultContext)
 	if err != nil {
@@ -237,10 +341,23 @@ func NewLabels(labels ...*Label) error {
 	}
 	defer committer.Close()
 
+	type labelOwner struct{ repoID, orgID int64 }
+	maxOrders := make(map[labelOwner]int)
 	for _, label := range labels {
 		if !LabelColorPattern.MatchString(label.Color) {
 			return fmt.Errorf("bad color code: %s", label.Color)
 		}
+
+		owner := labelOwner{label.RepoID, label.OrgID}
+		maxOrder, ok := maxOrders[owner]
+		if !ok {
+			if maxOrder, err = getMaxLabelOrder(ctx, label.RepoID, label.OrgID); err != nil {
+				return err
+			}
+		}
+		label.Order = maxOrder + 1
+		maxOrders[owner] = label.Order
+
 		if err := db.Insert(ctx, label); err != nil {
 			return err
 		}
@@ -248,12 +365,76 @@ func NewLabels(labels ...*Label) error {
 	return committer.Commit()
 }
 
+// UpdateLabelOrders stores the custom order of the given labels.
+func UpdateLabelOrders(ctx context.Context, labels []*Label) error {
+	return db.AutoTx(ctx, func(ctx context.Context) error {
+		for _, label := range labels {
+			if _, err := db.GetEngine(ctx).ID(label.ID).Cols("sort_order").NoAutoTime().Update(label); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // UpdateLabel updates label information.
-func UpdateLabel(l *Label) error {
+func UpdateLabel(ctx context.Context, l *Label) error {
 	if !LabelColorPattern.MatchString(l.Color) {
 		return fmt.Errorf("bad color code: %s", l.Color)
 	}
-	return updateLabelCols(db.DefaultContext, l, "name", "description", "color")
+	return updateLabelCols(ctx, l, "name", "description", "color")
+}
+
+// RenameLabel changes the name of a label, which must not be used by another label of its repository or
+// organization yet. Moving the label into another scope fails with an ErrLabelScopeConflict if an issue
+// carrying it already has a label of that scope.
+func RenameLabel(ctx context.Context, l *Label, name string) error {
+	exist, err := db.GetEngine(ctx).
+		Where(builder.Eq{"repo_id": l.RepoID, "org_id": l.OrgID, "name": name}).
+		And(builder.Neq{"id": l.ID}).
+		Exist(new(Label))
+	if err != nil {
+		return err
+	} else if exist {
+		return ErrLabelExist{RepoID: l.RepoID, OrgID: l.OrgID, Name: name}
+	}
+
+	if scope := LabelScope(name); scope != "" && scope != l.ExclusiveScope() {
+		issueID, err := getIssueIDWithLabelInScope(ctx, l.ID, scope)
+		if err != nil {
+			return err
+		} else if issueID > 0 {
+			return ErrLabelScopeConflict{IssueID: issueID, Scope: scope}
+		}
+	}
+
+	l.Name = name
+	return updateLabelCols(ctx, l, "name")
+}
+
+// getIssueIDWithLabelInScope returns the id of an issue which carries the label and another label of the scope,
+// or 0 if there is none
+func getIssueIDWithLabelInScope(ctx context.Context, labelID int64, scope string) (int64, error) {
+	others := make([]*struct {
+		IssueID int64
+		Name    string
+	}, 0, 10)
+	// LIKE preselects the candidates, the scope itself is compared below
+	if err := db.GetEngine(ctx).Table("issue_label").
+		Join("INNER", "label", "label.id = issue_label.label_id").
+		Where(builder.In("issue_label.issue_id", builder.Select("issue_id").From("issue_label").Where(builder.Eq{"label_id": labelID}))).
+		And(builder.Neq{"issue_label.label_id": labelID}).
+		And(builder.Like{"label.name", scope + "/"}).
+		Select("issue_label.issue_id AS issue_id, label.name AS name").
+		Find(&others); err != nil {
+		return 0, err
+	}
+	for _, other := range others {
+		if LabelScope(other.Name) == scope {
+			return other.IssueID, nil
+		}
+	}
+	return 0, nil
 }
 
 // DeleteLabel delete a label
@@ -294,6 +475,55 @@ func DeleteLabel(id, labelID int64) error {
 		return err
 	}
 
+	// the boards backed by the label remain as plain boards
+	if err = project_model.ReplaceBoardsLabel(ctx, labelID, 0); err != nil {
+		return err
+	}
+
+	return committer.Commit()
+}
+
+// MergeLabels moves all issues labeled with the from label to the into label and deletes the from label.
+// Issues carrying both labels keep a single association with the into label.
+func MergeLabels(ctx context.Context, from, into *Label) error {
+	ctx, committer, err := db.TxContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	sess := db.GetEngine(ctx)
+
+	// drop the associations which would become duplicates of existing ones, MySQL does not allow
+	// deleting from a table filtered by a subquery on the same table so the IDs are selected first
+	duplicateIDs := make([]int64, 0, 10)
+	if err := sess.Table("issue_label").Cols("id").Where("label_id = ?", from.ID).
+		And(builder.In("issue_id", builder.Select("issue_id").From("issue_label").Where(builder.Eq{"label_id": into.ID}))).
+		Find(&duplicateIDs); err != nil {
+		return err
+	}
+	if len(duplicateIDs) > 0 {
+		if _, err := sess.In("id", duplicateIDs).Delete(new(IssueLabel)); err != nil {
+			return err
+		}
+	}
+	if _, err := sess.Where("label_id = ?", from.ID).Cols("label_id").Update(&IssueLabel{LabelID: into.ID}); err != nil {
+		return err
+	}
+	// keep the label history of the issues pointing to an existing label
+	if _, err := sess.Where("label_id = ?", from.ID).Cols("label_id").Update(&Comment{LabelID: into.ID}); err != nil {
+		return err
+	}
+	if err := project_model.ReplaceBoardsLabel(ctx, from.ID, into.ID); err != nil {
+		return err
+	}
+	if _, err := sess.ID(from.ID).Delete(new(Label)); err != nil {
+		return err
+	}
+	if err := updateLabelCols(ctx, into, "num_issues", "num_closed_issue"); err != nil {
+		return err
+	}
+
 	return committer.Commit()
 }
 
@@ -418,6 +648,8 @@ func GetLabelsByRepoID(ctx context.Context, repoID int64, sortType string, listO
 		sess.Asc("num_issues")
 	case "mostissues":
 		sess.Desc("num_issues")
+	case "order":
+		sess.Asc("sort_order").Asc("name")
 	default:
 		sess.Asc("name")
 	}
@@ -521,6 +753,8 @@ func GetLabelsByOrgID(ctx context.Context, orgID int64, sortType string, listOpt
 		sess.Asc("num_issues")
 	case "mostissues":
 		sess.Desc("num_issues")
+	case "order":
+		sess.Asc("sort_order").Asc("name")
 	default:
 		sess.Asc("name")
 	}
@@ -553,6 +787,36 @@ func GetLabelsByIssueID(ctx context.Context, issueID int64) ([]*Label, error) {
 		Find(&labels)
 }
 
+// GetLabelsLastUsed returns the latest update time of the issues carrying each of the labels by label ID,
+// labels which have never been used are not contained. Only the issues matching issueCond are taken into account,
+// all issues if it is nil.
+func GetLabelsLastUsed(ctx context.Context, labelIDs []int64, issueCond builder.Cond) (map[int64]timeutil.TimeStamp, error) {
+	lastUsed := make(map[int64]timeutil.TimeStamp, len(labelIDs))
+	if len(labelIDs) == 0 {
+		return lastUsed, nil
+	}
+
+	rows := make([]*struct {
+		LabelID  int64
+		LastUsed timeutil.TimeStamp
+	}, 0, len(labelIDs))
+	sess := db.GetEngine(ctx).Table("issue_label").
+		Join("INNER", "issue", "issue.id = issue_label.issue_id").
+		In("issue_label.label_id", labelIDs)
+	if issueCond != nil {
+		sess = sess.And(issueCond)
+	}
+	if err := sess.GroupBy("issue_label.label_id").
+		Select("issue_label.label_id AS label_id, MAX(issue.updated_unix) AS last_used").
+		Find(&rows); err != nil {
+		return nil, fmt.Errorf("unable to GetLabelsLastUsed: %w", err)
+	}
+	for _, row := range rows {
+		lastUsed[row.LabelID] = row.LastUsed
+	}
+	return lastUsed, nil
+}
+
 func updateLabelCols(ctx context.Context, l *Label, cols ...string) error {
 	_, err := db.GetEngine(ctx).ID(l.ID).
 		SetExpr("num_issues",
@@ -9,6 +9,7 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
@@ -55,6 +56,16 @@ func TestNewLabels(t *testing.T) {
 		unittest.AssertExistsAndLoadBean(t, label, unittest.Cond("id = ?", label.ID))
 	}
 	unittest.CheckConsistencyFor(t, &issues_model.Label{}, &repo_model.Repository{})
+
+	// the labels are appended to the custom order like labels created one by one
+	appended := []*issues_model.Label{
+		{RepoID: 2, Name: "labelName2b", Color: "#123456"},
+		{RepoID: 2, Name: "labelName2c", Color: "#123456"},
+	}
+	assert.NoError(t, issues_model.NewLabels(appended...))
+	assert.Equal(t, labels[0].Order+1, appended[0].Order)
+	assert.Equal(t, labels[0].Order+2, appended[1].Order)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: appended[1].ID, Order: appended[1].Order})
 }
 
 func TestGetLabelByID(t *testing.T) {
@@ -257,6 +268,20 @@ func TestGetLabelsByIssueID(t *testing.T) {
 	assert.Len(t, labels, 0)
 }
 
+func TestLabelScope(t *testing.T) {
+	for name, scope := range map[string]string{
+		"priority/high":  "priority",
+		"kind/bug/crash": "kind/bug",
+		"label":          "",
+		"/high":          "",
+		"priority/":      "",
+		"priority//high": "priority/",
+		"":               "",
+	} {
+		assert.Equal(t, scope, issues_model.LabelScope(name), name)
+	}
+}
+
 func TestUpdateLabel(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
@@ -269,7 +294,7 @@ func TestUpdateLabel(t *testing.T) {
 	}
 	label.Color = update.Color
 	label.Name = update.Name
-	assert.NoError(t, issues_model.UpdateLabel(update))
+	assert.NoError(t, issues_model.UpdateLabel(db.DefaultContext, update))
 	newLabel := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
 	assert.EqualValues(t, label.ID, newLabel.ID)
 	assert.EqualValues(t, label.Color, newLabel.Color)
@@ -291,6 +316,17 @@ func TestDeleteLabel(t *testing.T) {
 	unittest.CheckConsistencyFor(t, &issues_model.Label{}, &repo_model.Repository{})
 }
 
+func TestDeleteLabel_UnsetsBoardLabel(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+	board := unittest.AssertExistsAndLoadBean(t, &project_model.Board{ID: 1})
+	assert.NoError(t, project_model.SetBoardLabel(db.DefaultContext, board, label.ID))
+
+	assert.NoError(t, issues_model.DeleteLabel(label.RepoID, label.ID))
+	board = unittest.AssertExistsAndLoadBean(t, &project_model.Board{ID: 1})
+	assert.Zero(t, board.LabelID)
+}
+
 func TestHasIssueLabel(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	assert.True(t, issues_model.HasIssueLabel(db.DefaultContext, 1, 1))
@@ -11,6 +11,7 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -43,6 +44,26 @@ func (err ErrMilestoneNotExist) Unwrap() error {
 	return util.ErrNotExist
 }
 
+// ErrMilestoneAlreadyExist represents a "MilestoneAlreadyExist" kind of error.
+type ErrMilestoneAlreadyExist struct {
+	RepoID int64
+	Name   string
+}
+
+// IsErrMilestoneAlreadyExist checks if an error is a ErrMilestoneAlreadyExist.
+func IsErrMilestoneAlreadyExist(err error) bool {
+	_, ok := err.(ErrMilestoneAlreadyExist)
+	return ok
+}
+
+func (err ErrMilestoneAlreadyExist) Error() string {
+	return fmt.Sprintf("milestone already exists [name: %s, repo_id: %d]", err.Name, err.RepoID)
+}
+
+func (err ErrMilestoneAlreadyExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
 // Milestone represents a milestone of repository.
 type Milestone struct {
 	ID              int64                  `xorm:"pk autoincr"`
@@ -168,6 +189,8 @@ func UpdateMilestone(m *Milestone, oldIsClosed bool) error {
 
 	if m.IsClosed && !oldIsClosed {
 		m.ClosedDateUnix = timeutil.TimeStampNow()
+	} else if !m.IsClosed {
+		m.ClosedDateUnix = 0
 	}
 
 	if err := updateMilestone(ctx, m); err != nil {
@@ -180,6 +203,11 @@ func UpdateMilestone(m *Milestone, oldIsClosed bool) error {
 			return err
 		}
 	}
+	if oldIsClosed && !m.IsClosed {
+		if err := recountMilestone(ctx, m); err != nil {
+			return err
+		}
+	}
 
 	return committer.Commit()
 }
@@ -262,6 +290,8 @@ func changeMilestoneStatus(ctx context.Context, m *Milestone, isClosed bool) err
 	m.IsClosed = isClosed
 	if isClosed {
 		m.ClosedDateUnix = timeutil.TimeStampNow()
+	} else {
+		m.ClosedDateUnix = 0
 	}
 
 	count, err := db.GetEngine(ctx).ID(m.ID).Where("repo_id = ? AND is_closed = ?", m.RepoID, !isClosed).Cols("is_closed", "closed_date_unix").Update(m)
@@ -271,9 +301,32 @@ func changeMilestoneStatus(ctx context.Context, m *Milestone, isClosed bool) err
 	if count < 1 {
 		return nil
 	}
+	if !isClosed {
+		if err := recountMilestone(ctx, m); err != nil {
+			return err
+		}
+	}
 	return updateRepoMilestoneNum(ctx, m.RepoID)
 }
 
+// recountMilestone recalculates the issue counters of a reopened milestone, which might have gone stale
+// while it was closed, and loads them into it. The counters of all milestones are repaired by the
+// check_repo_stats cron task.
+func recountMilestone(ctx context.Context, m *Milestone) error {
+	if err := UpdateMilestoneCounters(ctx, m.ID); err != nil {
+		return err
+	}
+	counters := new(Milestone)
+	if _, err := db.GetEngine(ctx).ID(m.ID).Cols("num_issues", "num_closed_issues", "completeness").Get(counters); err != nil {
+		return err
+	}
+	m.NumIssues = counters.NumIssues
+	m.NumClosedIssues = counters.NumClosedIssues
+	m.NumOpenIssues = counters.NumOpenIssues
+	m.Completeness = counters.Completeness
+	return nil
+}
+
 // DeleteMilestoneByRepoID deletes a milestone from a repository.
 func DeleteMilestoneByRepoID(repoID, id int64) error {
 	m, err := GetMilestoneByRepoID(db.DefaultContext, repoID, id)
@@ -518,6 +571,125 @@ func CountMilestones(ctx context.Context, opts GetMilestonesOption) (int64, erro
 		Count(new(Milestone))
 }
 
+// MilestoneLabelCount represents the number of open and closed issues of a milestone carrying a label
+type MilestoneLabelCount struct {
+	LabelID   int64
+	Name      string
+	NumOpen   int64
+	NumClosed int64
+}
+
+// CountMilestoneIssuesByLabel returns the number of open and closed issues of the milestone for each label, ordered by label name.
+// Labels which are not used by any issue of the milestone are not part of the result.
+func CountMilestoneIssuesByLabel(ctx context.Context, milestoneID int64) ([]*MilestoneLabelCount, error) {
+	countsSlice := make([]*struct {
+		LabelID  int64
+		Name     string
+		IsClosed bool
+		Count    int64
+	}, 0, 10)
+	if err := db.GetEngine(ctx).Table("issue_label").
+		Join("INNER", "issue", "issue.id = issue_label.issue_id").
+		Join("INNER", "label", "label.id = issue_label.label_id").
+		Where("issue.milestone_id = ?", milestoneID).
+		GroupBy("issue_label.label_id, label.name, issue.is_closed").
+		Select("issue_label.label_id AS label_id, label.name AS name, issue.is_closed AS is_closed, COUNT(*) AS count").
+		OrderBy("label.name, issue_label.label_id").
+		Find(&countsSlice); err != nil {
+		return nil, fmt.Errorf("unable to CountMilestoneIssuesByLabel: %w", err)
+	}
+
+	counts := make([]*MilestoneLabelCount, 0, len(countsSlice))
+	for _, c := range countsSlice {
+		if len(counts) == 0 || counts[len(counts)-1].LabelID != c.LabelID {
+			counts = append(counts, &MilestoneLabelCount{LabelID: c.LabelID, Name: c.Name})
+		}
+		count := counts[len(counts)-1]
+		if c.IsClosed {
+			count.NumClosed += c.Count
+		} else {
+			count.NumOpen += c.Count
+		}
+	}
+	return counts, nil
+}
+
+// MilestoneAssigneeCount represents the number of open issues of a milestone assigned to a user
+type MilestoneAssigneeCount struct {
+	// 0 for the issues without an assignee
+	AssigneeID int64
+	NumOpen    int64
+}
+
+// CountMilestoneOpenIssuesByAssignee returns the number of open issues of the milestone for each assignee, ordered by assignee ID.
+// Issues with several assignees are counted for each of them, issues without an assignee are counted for the assignee ID 0.
+func CountMilestoneOpenIssuesByAssignee(ctx context.Context, milestoneID int64) ([]*MilestoneAssigneeCount, error) {
+	counts := make([]*MilestoneAssigneeCount, 0, 10)
+	if err := db.GetEngine(ctx).Table("issue").
+		Join("LEFT", "issue_assignees", "issue_assignees.issue_id = issue.id").
+		Where("issue.milestone_id = ? AND issue.is_closed = ?", milestoneID, false).
+		GroupBy("issue_assignees.assignee_id").
+		Select("COALESCE(issue_assignees.assignee_id, 0) AS assignee_id, COUNT(*) AS num_open").
+		OrderBy("assignee_id").
+		Find(&counts); err != nil {
+		return nil, fmt.Errorf("unable to CountMilestoneOpenIssuesByAssignee: %w", err)
+	}
+	return counts, nil
+}
+
+// MilestonePullRequestState represents the cached merge state of an open pull request of a milestone
+type MilestonePullRequestState struct {
+	IssueID int64
+	Title   string
+	Status  PullRequestStatus
+	// whether an official reviewer has requested changes which have not been dismissed
+	ChangesRequested bool
+}
+
+// GetMilestoneOpenPullRequestStates returns the merge states of the open pull requests of the milestone, ordered by issue ID.
+func GetMilestoneOpenPullRequestStates(ctx context.Context, milestoneID int64) ([]*MilestonePullRequestState, error) {
+	states := make([]*MilestonePullRequestState, 0, 10)
+	if err := db.GetEngine(ctx).Table("issue").
+		Join("INNER", "pull_request", "pull_request.issue_id = issue.id").
+		Where("issue.milestone_id = ? AND issue.is_closed = ? AND issue.is_pull = ?", milestoneID, false, true).
+		Select("issue.id AS issue_id, issue.name AS title, pull_request.status").
+		OrderBy("issue.id").
+		Find(&states); err != nil {
+		return nil, fmt.Errorf("unable to GetMilestoneOpenPullRequestStates: %w", err)
+	}
+	if len(states) == 0 {
+		return states, nil
+	}
+
+	issueIDs := make([]int64, 0, len(states))
+	for _, state := range states {
+		issueIDs = append(issueIDs, state.IssueID)
+	}
+	rejectedIDs := make([]int64, 0, len(states))
+	if err := db.GetEngine(ctx).Table("review").
+		In("issue_id", issueIDs).
+		And("type = ? AND official = ? AND dismissed = ?", ReviewTypeReject, true, false).
+		Distinct("issue_id").
+		Find(&rejectedIDs); err != nil {
+		return nil, fmt.Errorf("unable to GetMilestoneOpenPullRequestStates: %w", err)
+	}
+	rejected := container.SetOf(rejectedIDs...)
+	for _, state := range states {
+		state.ChangesRequested = rejected.Contains(state.IssueID)
+	}
+	return states, nil
+}
+
+// GetMilestoneIssueCloseTimes returns the times the closed issues of the milestone have been closed, in ascending order
+func GetMilestoneIssueCloseTimes(ctx context.Context, milestoneID int64) ([]timeutil.TimeStamp, error) {
+	closeTimes := make([]timeutil.TimeStamp, 0, 10)
+	return closeTimes, db.GetEngine(ctx).Table("issue").
+		Where("milestone_id = ? AND is_closed = ?", milestoneID, true).
+		Cols("closed_unix").
+		Asc("closed_unix").
+		Find(&closeTimes)
+}
+
 // CountMilestonesByRepoCond map from repo conditions to number of milestones matching the options`
 func CountMilestonesByRepoCond(repoCond builder.Cond, isClosed bool) (map[int64]int64, error) {
 	sess := db.GetEngine(db.DefaultContext).Where("is_closed = ?", isClosed)
@@ -311,6 +311,35 @@ func TestChangeMilestoneStatus(t *testing.T) {
 	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: milestone.RepoID}, &issues_model.Milestone{})
 }
 
+func TestChangeMilestoneStatus_Recount(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	milestone := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+
+	// the counters are not touched when the milestone is closed
+	_, err := db.GetEngine(db.DefaultContext).ID(1).Cols("num_issues", "num_closed_issues").NoAutoTime().
+		Update(&issues_model.Milestone{NumIssues: 5, NumClosedIssues: 3})
+	assert.NoError(t, err)
+	assert.NoError(t, issues_model.ChangeMilestoneStatus(milestone, true))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1, NumIssues: 5, NumClosedIssues: 3})
+
+	// but recalculated when it is reopened
+	assert.NoError(t, issues_model.ChangeMilestoneStatus(milestone, false))
+	assert.EqualValues(t, 1, milestone.NumIssues)
+	assert.EqualValues(t, 0, milestone.NumClosedIssues)
+	assert.EqualValues(t, 1, milestone.NumOpenIssues)
+	unittest.CheckConsistencyFor(t, &issues_model.Milestone{})
+
+	// the same holds when the milestone is reopened by an update
+	_, err = db.GetEngine(db.DefaultContext).ID(1).Cols("num_issues", "num_closed_issues", "is_closed").NoAutoTime().
+		Update(&issues_model.Milestone{NumIssues: 5, NumClosedIssues: 3, IsClosed: true})
+	assert.NoError(t, err)
+	milestone = unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	milestone.IsClosed = false
+	assert.NoError(t, issues_model.UpdateMilestone(milestone, true))
+	assert.EqualValues(t, 1, milestone.NumIssues)
+	unittest.CheckConsistencyFor(t, &issues_model.Milestone{})
+}
+
 func TestDeleteMilestoneByRepoID(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	assert.NoError(t, issues_model.DeleteMilestoneByRepoID(1, 1))
@@ -351,3 +380,23 @@ func TestUpdateMilestoneCounters(t *testing.T) {
 	assert.NoError(t, issues_model.UpdateMilestoneCounters(db.DefaultContext, issue.MilestoneID))
 	unittest.CheckConsistencyFor(t, &issues_model.Milestone{})
 }
+
+func TestCountMilestoneIssuesByLabel(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// move the closed issue 5 carrying label2 into milestone 1 next to the open issue 2
+	_, err := db.GetEngine(db.DefaultContext).ID(5).Cols("milestone_id").Update(&issues_model.Issue{MilestoneID: 1})
+	assert.NoError(t, err)
+
+	counts, err := issues_model.CountMilestoneIssuesByLabel(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*issues_model.MilestoneLabelCount{
+		{LabelID: 1, Name: "label1", NumOpen: 1},
+		{LabelID: 2, Name: "label2", NumClosed: 1},
+		{LabelID: 4, Name: "orglabel4", NumOpen: 1},
+	}, counts)
+
+	counts, err = issues_model.CountMilestoneIssuesByLabel(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+}
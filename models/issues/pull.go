@@ -15,6 +15,7 @@ import (
 	pull_model "code.gitea.io/gitea/models/pull"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -153,6 +154,16 @@ type PullRequest struct {
 
 	ChangedProtectedFiles []string `xorm:"TEXT JSON"`
 
+	// diff stats between the merge base and DiffStatsCommitID, stored by the last check
+	DiffStatsCommitID string `xorm:"VARCHAR(40) NOT NULL DEFAULT ''"`
+	NumChangedFiles   int    `xorm:"NOT NULL DEFAULT 0"`
+	NumAdditions      int    `xorm:"NOT NULL DEFAULT 0"`
+	NumDeletions      int    `xorm:"NOT NULL DEFAULT 0"`
+
+	// head of the base branch seen by the last check, and when a check saw it change
+	BaseCommitID    string             `xorm:"VARCHAR(40) NOT NULL DEFAULT ''"`
+	BaseChangedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+
 	IssueID int64  `xorm:"INDEX"`
 	Issue   *Issue `xorm:"-"`
 	Index   int64
@@ -631,6 +642,36 @@ func GetAllUnmergedAgitPullRequestByPoster(uid int64) ([]*PullRequest, error) {
 	return pulls, err
 }
 
+// GetMergedPullRequestPosterIDs returns which of the posters have had a pull request merged into the repositories,
+// keyed by repository ID
+func GetMergedPullRequestPosterIDs(ctx context.Context, repoIDs, posterIDs []int64) (map[int64]container.Set[int64], error) {
+	posters := make(map[int64]container.Set[int64], len(repoIDs))
+	if len(repoIDs) == 0 || len(posterIDs) == 0 {
+		return posters, nil
+	}
+
+	rows := make([]*struct {
+		RepoID   int64
+		PosterID int64
+	}, 0, len(posterIDs))
+	if err := db.GetEngine(ctx).Table("pull_request").
+		Join("INNER", "issue", "issue.id = pull_request.issue_id").
+		In("pull_request.base_repo_id", repoIDs).
+		In("issue.poster_id", posterIDs).
+		And("pull_request.has_merged = ?", true).
+		Select("DISTINCT pull_request.base_repo_id AS repo_id, issue.poster_id AS poster_id").
+		Find(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if posters[row.RepoID] == nil {
+			posters[row.RepoID] = make(container.Set[int64])
+		}
+		posters[row.RepoID].Add(row.PosterID)
+	}
+	return posters, nil
+}
+
 // Update updates all fields of pull request.
 func (pr *PullRequest) Update() error {
 	_, err := db.GetEngine(db.DefaultContext).ID(pr.ID).AllCols().Update(pr)
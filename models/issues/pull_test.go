@@ -274,3 +274,15 @@ func TestDeleteOrphanedObjects(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, countBefore, countAfter)
 }
+
+func TestGetMergedPullRequestPosterIDs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	posterIDs, err := issues_model.GetMergedPullRequestPosterIDs(db.DefaultContext, []int64{1, 3}, []int64{1, 2})
+	assert.NoError(t, err)
+	// pull request 1 by user1 has been merged into repo1
+	assert.True(t, posterIDs[1].Contains(1))
+	assert.False(t, posterIDs[1].Contains(2))
+	// pull request 6 by user2 into repo3 is not merged
+	assert.False(t, posterIDs[3].Contains(2))
+}
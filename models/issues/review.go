@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
@@ -544,6 +545,59 @@ func GetReviewersFromOriginalAuthorsByIssueID(issueID int64) ([]*Review, error)
 	return reviews, nil
 }
 
+// GetReviewersByIssueIDs gets the latest review of each reviewer for the given pull requests like GetReviewersByIssueID,
+// grouped by issue id. The reviewers of the reviews are loaded as well.
+func GetReviewersByIssueIDs(ctx context.Context, issueIDs []int64) (map[int64][]*Review, error) {
+	reviewsMap := make(map[int64][]*Review, len(issueIDs))
+	if len(issueIDs) == 0 {
+		return reviewsMap, nil
+	}
+
+	reviews := make([]*Review, 0, 10)
+	if err := db.GetEngine(ctx).
+		In("id", builder.Select("max(id)").From("review").
+			Where(builder.In("issue_id", issueIDs).
+				And(builder.Eq{"reviewer_team_id": 0, "dismissed": false, "original_author_id": 0}).
+				And(builder.In("type", ReviewTypeApprove, ReviewTypeReject, ReviewTypeRequest))).
+			GroupBy("issue_id, reviewer_id")).
+		OrderBy("updated_unix ASC").
+		Find(&reviews); err != nil {
+		return nil, err
+	}
+
+	teamReviewRequests := make([]*Review, 0, 5)
+	if err := db.GetEngine(ctx).
+		In("id", builder.Select("max(id)").From("review").
+			Where(builder.In("issue_id", issueIDs).
+				And(builder.Neq{"reviewer_team_id": 0}).
+				And(builder.Eq{"original_author_id": 0})).
+			GroupBy("issue_id, reviewer_team_id")).
+		OrderBy("updated_unix ASC").
+		Find(&teamReviewRequests); err != nil {
+		return nil, err
+	}
+
+	reviewerIDs := make(container.Set[int64], len(reviews))
+	for _, review := range reviews {
+		reviewerIDs.Add(review.ReviewerID)
+	}
+	reviewers := make(map[int64]*user_model.User, len(reviewerIDs))
+	if err := db.GetEngine(ctx).In("id", reviewerIDs.Values()).Find(&reviewers); err != nil {
+		return nil, err
+	}
+
+	for _, review := range append(reviews, teamReviewRequests...) {
+		if review.ReviewerID != 0 {
+			review.Reviewer = reviewers[review.ReviewerID]
+			if review.Reviewer == nil {
+				review.Reviewer = user_model.NewGhostUser()
+			}
+		}
+		reviewsMap[review.IssueID] = append(reviewsMap[review.IssueID], review)
+	}
+	return reviewsMap, nil
+}
+
 // GetReviewByIssueIDAndUserID get the latest review of reviewer for a pull request
 func GetReviewByIssueIDAndUserID(ctx context.Context, issueID, userID int64) (*Review, error) {
 	review := new(Review)
@@ -146,6 +146,25 @@ func TestGetReviewersByIssueID(t *testing.T) {
 	}
 }
 
+func TestGetReviewersByIssueIDs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	reviewsMap, err := issues_model.GetReviewersByIssueIDs(db.DefaultContext, []int64{2, 3, 12})
+	assert.NoError(t, err)
+	for _, issueID := range []int64{2, 3, 12} {
+		expected, err := issues_model.GetReviewersByIssueID(issueID)
+		assert.NoError(t, err)
+		if assert.Len(t, reviewsMap[issueID], len(expected)) {
+			for i, review := range reviewsMap[issueID] {
+				assert.Equal(t, expected[i].ID, review.ID)
+				if review.ReviewerID != 0 {
+					assert.Equal(t, review.ReviewerID, review.Reviewer.ID)
+				}
+			}
+		}
+	}
+}
+
 func TestDismissReview(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 
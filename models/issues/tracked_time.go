@@ -25,7 +25,10 @@ type TrackedTime struct {
 	Created     time.Time        `xorm:"-"`
 	CreatedUnix int64            `xorm:"created"`
 	Time        int64            `xorm:"NOT NULL"`
+	TimeMs      int64            `xorm:"NOT NULL DEFAULT 0"`
 	Deleted     bool             `xorm:"NOT NULL DEFAULT false"`
+	Billable    bool             `xorm:"NOT NULL DEFAULT false"`
+	Comment     *Comment         `xorm:"-"`
 }
 
 func init() {
@@ -40,12 +43,28 @@ func (t *TrackedTime) AfterLoad() {
 	t.Created = time.Unix(t.CreatedUnix, 0).In(setting.DefaultUILocation)
 }
 
-// LoadAttributes load Issue, User
+// Milliseconds returns the tracked time in milliseconds, entries without a millisecond value
+// fall back to their time in seconds.
+func (t *TrackedTime) Milliseconds() int64 {
+	if t.TimeMs == 0 {
+		return t.Time * 1000
+	}
+	return t.TimeMs
+}
+
+// LoadAttributes load Issue, User, Comment
 func (t *TrackedTime) LoadAttributes() (err error) {
 	return t.loadAttributes(db.DefaultContext)
 }
 
 func (t *TrackedTime) loadAttributes(ctx context.Context) (err error) {
+	if err = t.loadIssueAndUser(ctx); err != nil {
+		return err
+	}
+	return t.LoadComment(ctx)
+}
+
+func (t *TrackedTime) loadIssueAndUser(ctx context.Context) (err error) {
 	if t.Issue == nil {
 		t.Issue, err = GetIssueByID(ctx, t.IssueID)
 		if err != nil {
@@ -62,17 +81,64 @@ func (t *TrackedTime) loadAttributes(ctx context.Context) (err error) {
 			return
 		}
 	}
-	return err
+	return nil
+}
+
+// LoadComment loads the comment created when the time was added manually, if there is one
+func (t *TrackedTime) LoadComment(ctx context.Context) error {
+	if t.Comment != nil {
+		return nil
+	}
+	comment := new(Comment)
+	has, err := db.GetEngine(ctx).Where("time_id = ? AND type = ?", t.ID, CommentTypeAddTimeManual).Get(comment)
+	if err != nil {
+		return err
+	}
+	if has {
+		t.Comment = comment
+	}
+	return nil
 }
 
-// LoadAttributes load Issue, User
+// LoadAttributes load Issue, User, Comment
 func (tl TrackedTimeList) LoadAttributes() (err error) {
 	for _, t := range tl {
-		if err = t.LoadAttributes(); err != nil {
+		if err = t.loadIssueAndUser(db.DefaultContext); err != nil {
 			return err
 		}
 	}
-	return err
+	return tl.LoadComments(db.DefaultContext)
+}
+
+// LoadComments loads the comments created when the times were added manually at once
+func (tl TrackedTimeList) LoadComments(ctx context.Context) error {
+	timeIDs := make([]int64, 0, len(tl))
+	for _, t := range tl {
+		if t.Comment == nil {
+			timeIDs = append(timeIDs, t.ID)
+		}
+	}
+	if len(timeIDs) == 0 {
+		return nil
+	}
+
+	comments := make([]*Comment, 0, len(timeIDs))
+	if err := db.GetEngine(ctx).
+		In("time_id", timeIDs).
+		And("type = ?", CommentTypeAddTimeManual).
+		Find(&comments); err != nil {
+		return err
+	}
+	commentsByTimeID := make(map[int64]*Comment, len(comments))
+	for _, comment := range comments {
+		commentsByTimeID[comment.TimeID] = comment
+	}
+	for _, t := range tl {
+		if t.Comment == nil {
+			t.Comment = commentsByTimeID[t.ID]
+		}
+	}
+	return nil
 }
 
 // FindTrackedTimesOptions represent the filters for tracked times. If an ID is 0 it will be ignored.
@@ -146,15 +212,46 @@ func GetTrackedSeconds(ctx context.Context, opts FindTrackedTimesOptions) (track
 	return opts.toSession(db.GetEngine(ctx)).SumInt(&TrackedTime{}, "time")
 }
 
+// GetTrackedSecondsByIssueIDs returns the sum of the seconds tracked on each of the issues by issue ID,
+// issues without tracked time are not contained
+func GetTrackedSecondsByIssueIDs(ctx context.Context, issueIDs []int64) (map[int64]int64, error) {
+	sums := make([]*struct {
+		IssueID int64
+		Time    int64
+	}, 0, len(issueIDs))
+	if len(issueIDs) > 0 {
+		if err := db.GetEngine(ctx).
+			Table("tracked_time").
+			Select("issue_id, SUM(time) AS time").
+			In("issue_id", issueIDs).
+			And("deleted = ?", false).
+			GroupBy("issue_id").
+			Find(&sums); err != nil {
+			return nil, err
+		}
+	}
+
+	trackedSeconds := make(map[int64]int64, len(sums))
+	for _, sum := range sums {
+		trackedSeconds[sum.IssueID] = sum.Time
+	}
+	return trackedSeconds, nil
+}
+
 // AddTime will add the given time (in seconds) to the issue
-func AddTime(user *user_model.User, issue *Issue, amount int64, created time.Time) (*TrackedTime, error) {
+func AddTime(user *user_model.User, issue *Issue, amount int64, created time.Time, billable bool) (*TrackedTime, error) {
+	return AddTimeMs(user, issue, amount*1000, created, billable)
+}
+
+// AddTimeMs will add the given time in milliseconds to the issue, its time in seconds is rounded down
+func AddTimeMs(user *user_model.User, issue *Issue, amountMs int64, created time.Time, billable bool) (*TrackedTime, error) {
 	ctx, committer, err := db.TxContext(db.DefaultContext)
 	if err != nil {
 		return nil, err
 	}
 	defer committer.Close()
 
-	t, err := addTime(ctx, user, issue, amount, created)
+	t, err := addTime(ctx, user, issue, amountMs, created, billable)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +264,7 @@ func AddTime(user *user_model.User, issue *Issue, amount int64, created time.Tim
 		Issue:   issue,
 		Repo:    issue.Repo,
 		Doer:    user,
-		Content: util.SecToTime(amount),
+		Content: util.SecToTime(t.Time),
 		Type:    CommentTypeAddTimeManual,
 		TimeID:  t.ID,
 	}); err != nil {
@@ -177,15 +274,17 @@ func AddTime(user *user_model.User, issue *Issue, amount int64, created time.Tim
 	return t, committer.Commit()
 }
 
-func addTime(ctx context.Context, user *user_model.User, issue *Issue, amount int64, created time.Time) (*TrackedTime, error) {
+func addTime(ctx context.Context, user *user_model.User, issue *Issue, amountMs int64, created time.Time, billable bool) (*TrackedTime, error) {
 	if created.IsZero() {
 		created = time.Now()
 	}
 	tt := &TrackedTime{
-		IssueID: issue.ID,
-		UserID:  user.ID,
-		Time:    amount,
-		Created: created,
+		IssueID:  issue.ID,
+		UserID:   user.ID,
+		Time:     amountMs / 1000,
+		TimeMs:   amountMs,
+		Created:  created,
+		Billable: billable,
 	}
 	return tt, db.Insert(ctx, tt)
 }
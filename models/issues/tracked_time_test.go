@@ -25,7 +25,7 @@ func TestAddTime(t *testing.T) {
 	assert.NoError(t, err)
 
 	// 3661 = 1h 1min 1s
-	trackedTime, err := issues_model.AddTime(user3, issue1, 3661, time.Now())
+	trackedTime, err := issues_model.AddTime(user3, issue1, 3661, time.Now(), true)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(3), trackedTime.UserID)
 	assert.Equal(t, int64(1), trackedTime.IssueID)
@@ -33,6 +33,7 @@ func TestAddTime(t *testing.T) {
 
 	tt := unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{UserID: 3, IssueID: 1})
 	assert.Equal(t, int64(3661), tt.Time)
+	assert.True(t, tt.Billable)
 
 	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{Type: issues_model.CommentTypeAddTimeManual, PosterID: 3, IssueID: 1})
 	assert.Equal(t, comment.Content, "1 hour 1 minute")
@@ -115,3 +116,65 @@ func TestTotalTimes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, total, 2)
 }
+
+func TestTrackedTime_LoadComment(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user3 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	issue1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	trackedTime, err := issues_model.AddTime(user3, issue1, 3661, time.Now(), false)
+	assert.NoError(t, err)
+	assert.NoError(t, trackedTime.LoadComment(db.DefaultContext))
+	if assert.NotNil(t, trackedTime.Comment) {
+		assert.Equal(t, issues_model.CommentTypeAddTimeManual, trackedTime.Comment.Type)
+		assert.Equal(t, "1 hour 1 minute", trackedTime.Comment.Content)
+	}
+
+	// times without an associated comment
+	trackedTime = unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: 1})
+	assert.NoError(t, trackedTime.LoadComment(db.DefaultContext))
+	assert.Nil(t, trackedTime.Comment)
+}
+
+func TestTrackedTimeList_LoadComments(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user3 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	issue1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	added, err := issues_model.AddTime(user3, issue1, 60, time.Now(), false)
+	assert.NoError(t, err)
+	tl := issues_model.TrackedTimeList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: added.ID}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: 1}),
+	}
+	assert.NoError(t, tl.LoadComments(db.DefaultContext))
+	if assert.NotNil(t, tl[0].Comment) {
+		assert.Equal(t, "1 minute", tl[0].Comment.Content)
+	}
+	assert.Nil(t, tl[1].Comment)
+}
+
+func TestGetTrackedSecondsByIssueIDs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	trackedSeconds, err := issues_model.GetTrackedSecondsByIssueIDs(db.DefaultContext, []int64{1, 2, 3})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 400, trackedSeconds[1])
+	// the deleted time of issue 2 does not count
+	assert.EqualValues(t, 3682, trackedSeconds[2])
+	assert.NotContains(t, trackedSeconds, int64(3))
+}
+
+func TestAddTimeMs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	user3 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	issue1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	trackedTime, err := issues_model.AddTimeMs(user3, issue1, 2750, time.Now(), false)
+	assert.NoError(t, err)
+	tt := unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: trackedTime.ID})
+	assert.EqualValues(t, 2, tt.Time)
+	assert.EqualValues(t, 2750, tt.TimeMs)
+}
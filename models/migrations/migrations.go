@@ -442,6 +442,34 @@ var migrations = []Migration{
 	NewMigration("Add package cleanup rule table", v1_19.CreatePackageCleanupRuleTable),
 	// v235 -> v236
 	NewMigration("Add index for access_token", v1_19.AddIndexForAccessToken),
+	// v236 -> v237
+	NewMigration("Add time_ms column to tracked_time table", v1_19.AddTimeMsToTrackedTime),
+	// v237 -> v238
+	NewMigration("Add lock_reason column to issue table", v1_19.AddLockReasonToIssue),
+	// v238 -> v239
+	NewMigration("Add sort_order column to label table", v1_19.AddSortOrderToLabel),
+	// v239 -> v240
+	NewMigration("Add template column to issue table", v1_19.AddTemplateToIssue),
+	// v240 -> v241
+	NewMigration("Add closed_reason column to issue table", v1_19.AddClosedReasonToIssue),
+	// v241 -> v242
+	NewMigration("Add label_id column to project_board table", v1_19.AddLabelIDToProjectBoard),
+	// v242 -> v243
+	NewMigration("Add billable column to tracked_time table", v1_19.AddBillableToTrackedTime),
+	// v243 -> v244
+	NewMigration("Add diff stats columns to pull_request table", v1_19.AddDiffStatsToPullRequest),
+	// v244 -> v245
+	NewMigration("Add base commit columns to pull_request table", v1_19.AddBaseCommitToPullRequest),
+	// v245 -> v246
+	NewMigration("Add duplicate_of_id column to issue table", v1_19.AddDuplicateOfToIssue),
+	// v246 -> v247
+	NewMigration("Add is_public column to team table", v1_19.AddIsPublicToTeam),
+	// v247 -> v248
+	NewMigration("Add saved_reply_id column to comment table", v1_19.AddSavedReplyIDToComment),
+	// v248 -> v249
+	NewMigration("Add estimate column to issue table", v1_19.AddEstimateToIssue),
+	// v249 -> v250
+	NewMigration("Add index to time_id column of comment table", v1_19.AddIndexToCommentTimeID),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,21 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddTimeMsToTrackedTime(x *xorm.Engine) error {
+	type TrackedTime struct {
+		TimeMs int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync(new(TrackedTime)); err != nil {
+		return err
+	}
+
+	_, err := x.Exec("UPDATE tracked_time SET time_ms = time * 1000")
+	return err
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddTimeMsToTrackedTime(t *testing.T) {
+	type TrackedTime struct {
+		ID      int64 `xorm:"pk autoincr"`
+		IssueID int64 `xorm:"INDEX"`
+		Time    int64 `xorm:"NOT NULL"`
+	}
+
+	// Prepare and load the testing database
+	x, deferable := base.PrepareTestEnv(t, 0, new(TrackedTime))
+	defer deferable()
+	if x == nil || t.Failed() {
+		return
+	}
+
+	if err := AddTimeMsToTrackedTime(x); err != nil {
+		assert.NoError(t, err)
+		return
+	}
+
+	type ExpectedTrackedTime struct {
+		ID     int64
+		Time   int64
+		TimeMs int64
+	}
+
+	got := []ExpectedTrackedTime{}
+	if err := x.Table("tracked_time").Select("id, time, time_ms").Asc("id").Find(&got); !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []ExpectedTrackedTime{
+		{ID: 1, Time: 400, TimeMs: 400000},
+		{ID: 2, Time: 3661, TimeMs: 3661000},
+		{ID: 3, Time: 0, TimeMs: 0},
+	}, got)
+}
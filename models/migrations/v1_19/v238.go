@@ -0,0 +1,49 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddSortOrderToLabel(x *xorm.Engine) error {
+	type Label struct {
+		ID     int64 `xorm:"pk autoincr"`
+		RepoID int64 `xorm:"INDEX"`
+		OrgID  int64 `xorm:"INDEX"`
+		Name   string
+		Order  int `xorm:"'sort_order' NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync(new(Label)); err != nil {
+		return err
+	}
+
+	// keep the current alphabetical order of the labels of each repository and organization
+	const batchSize = 100
+	var (
+		lastRepoID, lastOrgID int64 = -1, -1
+		order                 int
+	)
+	for start := 0; ; start += batchSize {
+		labels := make([]*Label, 0, batchSize)
+		if err := x.Asc("repo_id", "org_id", "name", "id").Limit(batchSize, start).Find(&labels); err != nil {
+			return err
+		}
+		for _, label := range labels {
+			if label.RepoID != lastRepoID || label.OrgID != lastOrgID {
+				lastRepoID, lastOrgID = label.RepoID, label.OrgID
+				order = 0
+			}
+			order++
+			label.Order = order
+			if _, err := x.ID(label.ID).Cols("sort_order").Update(label); err != nil {
+				return err
+			}
+		}
+		if len(labels) < batchSize {
+			return nil
+		}
+	}
+}
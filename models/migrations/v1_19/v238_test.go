@@ -0,0 +1,50 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddSortOrderToLabel(t *testing.T) {
+	type Label struct {
+		ID     int64 `xorm:"pk autoincr"`
+		RepoID int64 `xorm:"INDEX"`
+		OrgID  int64 `xorm:"INDEX"`
+		Name   string
+	}
+
+	// Prepare and load the testing database
+	x, deferable := base.PrepareTestEnv(t, 0, new(Label))
+	defer deferable()
+	if x == nil || t.Failed() {
+		return
+	}
+
+	if err := AddSortOrderToLabel(x); err != nil {
+		assert.NoError(t, err)
+		return
+	}
+
+	type ExpectedLabel struct {
+		ID        int64
+		SortOrder int
+	}
+
+	got := []ExpectedLabel{}
+	if err := x.Table("label").Select("id, sort_order").Asc("id").Find(&got); !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []ExpectedLabel{
+		{ID: 1, SortOrder: 2},
+		{ID: 2, SortOrder: 1},
+		{ID: 3, SortOrder: 3},
+		{ID: 4, SortOrder: 2},
+		{ID: 5, SortOrder: 1},
+	}, got)
+}
@@ -0,0 +1,16 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddTemplateToIssue(x *xorm.Engine) error {
+	type Issue struct {
+		Template string
+	}
+
+	return x.Sync(new(Issue))
+}
@@ -0,0 +1,22 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddClosedReasonToIssue(x *xorm.Engine) error {
+	type Issue struct {
+		ClosedReason string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	}
+
+	if err := x.Sync(new(Issue)); err != nil {
+		return err
+	}
+
+	// there was no way to close an issue as not planned before
+	_, err := x.Exec("UPDATE issue SET closed_reason = ? WHERE is_closed = ?", "completed", true)
+	return err
+}
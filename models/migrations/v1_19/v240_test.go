@@ -0,0 +1,46 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddClosedReasonToIssue(t *testing.T) {
+	type Issue struct {
+		ID       int64 `xorm:"pk autoincr"`
+		IsClosed bool  `xorm:"INDEX"`
+	}
+
+	// Prepare and load the testing database
+	x, deferable := base.PrepareTestEnv(t, 0, new(Issue))
+	defer deferable()
+	if x == nil || t.Failed() {
+		return
+	}
+
+	if err := AddClosedReasonToIssue(x); err != nil {
+		assert.NoError(t, err)
+		return
+	}
+
+	type ExpectedIssue struct {
+		ID           int64
+		ClosedReason string
+	}
+
+	got := []ExpectedIssue{}
+	if err := x.Table("issue").Select("id, closed_reason").Asc("id").Find(&got); !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []ExpectedIssue{
+		{ID: 1, ClosedReason: ""},
+		{ID: 2, ClosedReason: "completed"},
+		{ID: 3, ClosedReason: ""},
+	}, got)
+}
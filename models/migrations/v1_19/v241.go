@@ -0,0 +1,16 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddLabelIDToProjectBoard(x *xorm.Engine) error {
+	type ProjectBoard struct {
+		LabelID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(ProjectBoard))
+}
@@ -0,0 +1,16 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddBillableToTrackedTime(x *xorm.Engine) error {
+	type TrackedTime struct {
+		Billable bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	return x.Sync(new(TrackedTime))
+}
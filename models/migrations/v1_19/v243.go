@@ -0,0 +1,19 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddDiffStatsToPullRequest(x *xorm.Engine) error {
+	type PullRequest struct {
+		DiffStatsCommitID string `xorm:"VARCHAR(40) NOT NULL DEFAULT ''"`
+		NumChangedFiles   int    `xorm:"NOT NULL DEFAULT 0"`
+		NumAdditions      int    `xorm:"NOT NULL DEFAULT 0"`
+		NumDeletions      int    `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PullRequest))
+}
@@ -0,0 +1,19 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddBaseCommitToPullRequest(x *xorm.Engine) error {
+	type PullRequest struct {
+		BaseCommitID    string             `xorm:"VARCHAR(40) NOT NULL DEFAULT ''"`
+		BaseChangedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PullRequest))
+}
@@ -0,0 +1,16 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddIsPublicToTeam(x *xorm.Engine) error {
+	type Team struct {
+		IsPublic bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	return x.Sync(new(Team))
+}
@@ -0,0 +1,16 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddSavedReplyIDToComment(x *xorm.Engine) error {
+	type Comment struct {
+		SavedReplyID int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(Comment))
+}
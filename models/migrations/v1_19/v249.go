@@ -0,0 +1,16 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_19 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddIndexToCommentTimeID(x *xorm.Engine) error {
+	type Comment struct {
+		TimeID int64 `xorm:"INDEX"`
+	}
+
+	return x.Sync(new(Comment))
+}
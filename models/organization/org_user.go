@@ -6,6 +6,7 @@ package organization
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	user_model "code.gitea.io/gitea/models/user"
@@ -62,6 +63,42 @@ func IsOrganizationMember(ctx context.Context, orgID, uid int64) (bool, error) {
 		Exist()
 }
 
+// GetOrgMembershipsByUserIDs returns which of the users are members of the organizations, keyed by organization ID
+// and user ID. The value is whether the member is an owner of the organization.
+func GetOrgMembershipsByUserIDs(ctx context.Context, orgIDs, uids []int64) (map[int64]map[int64]bool, error) {
+	memberships := make(map[int64]map[int64]bool, len(orgIDs))
+	if len(orgIDs) == 0 || len(uids) == 0 {
+		return memberships, nil
+	}
+
+	orgUsers := make([]*OrgUser, 0, len(uids))
+	if err := db.GetEngine(ctx).In("org_id", orgIDs).In("uid", uids).Find(&orgUsers); err != nil {
+		return nil, err
+	}
+	for _, orgUser := range orgUsers {
+		if memberships[orgUser.OrgID] == nil {
+			memberships[orgUser.OrgID] = make(map[int64]bool)
+		}
+		memberships[orgUser.OrgID][orgUser.UID] = false
+	}
+
+	owners := make([]*TeamUser, 0, len(uids))
+	if err := db.GetEngine(ctx).
+		Join("INNER", "team", "team.id = team_user.team_id").
+		In("team_user.org_id", orgIDs).
+		In("team_user.uid", uids).
+		And("team.lower_name = ?", strings.ToLower(OwnerTeamName)).
+		Find(&owners); err != nil {
+		return nil, err
+	}
+	for _, owner := range owners {
+		if _, ok := memberships[owner.OrgID][owner.UID]; ok {
+			memberships[owner.OrgID][owner.UID] = true
+		}
+	}
+	return memberships, nil
+}
+
 // IsPublicMembership returns true if the given user's membership of given org is public.
 func IsPublicMembership(orgID, uid int64) (bool, error) {
 	return db.GetEngine(db.DefaultContext).
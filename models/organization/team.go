@@ -86,6 +86,7 @@ type Team struct {
 	Units                   []*TeamUnit `xorm:"-"`
 	IncludesAllRepositories bool        `xorm:"NOT NULL DEFAULT false"`
 	CanCreateOrgRepo        bool        `xorm:"NOT NULL DEFAULT false"`
+	IsPublic                bool        `xorm:"NOT NULL DEFAULT false"`
 }
 
 func init() {
@@ -81,6 +81,17 @@ func GetUserOrgTeams(ctx context.Context, orgID, userID int64) (teams []*Team, e
 		Find(&teams)
 }
 
+// GetUserOrgPublicTeams returns the public teams of the organization the user belongs to, ordered by name
+func GetUserOrgPublicTeams(ctx context.Context, orgID, userID int64) (teams []*Team, err error) {
+	return teams, db.GetEngine(ctx).
+		Join("INNER", "team_user", "team_user.team_id = team.id").
+		Where("team.org_id = ?", orgID).
+		And("team_user.uid=?", userID).
+		And("team.is_public = ?", true).
+		OrderBy("team.lower_name").
+		Find(&teams)
+}
+
 // GetUserRepoTeams returns user repo's teams
 func GetUserRepoTeams(ctx context.Context, orgID, userID, repoID int64) (teams []*Team, err error) {
 	return teams, db.GetEngine(ctx).
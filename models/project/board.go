@@ -47,6 +47,8 @@ type Board struct {
 
 	ProjectID int64 `xorm:"INDEX NOT NULL"`
 	CreatorID int64 `xorm:"NOT NULL"`
+	// the label which backs this board, 0 if there is none
+	LabelID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -206,6 +208,40 @@ func UpdateBoard(ctx context.Context, board *Board) error {
 	return err
 }
 
+// SetBoardLabel sets the label which backs the board, 0 unsets it
+func SetBoardLabel(ctx context.Context, board *Board, labelID int64) error {
+	board.LabelID = labelID
+	_, err := db.GetEngine(ctx).ID(board.ID).Cols("label_id").Update(board)
+	return err
+}
+
+// ReplaceBoardsLabel lets all boards which are backed by a label be backed by another one, 0 unsets it
+func ReplaceBoardsLabel(ctx context.Context, oldLabelID, newLabelID int64) error {
+	_, err := db.GetEngine(ctx).Where("label_id = ?", oldLabelID).Cols("label_id").Update(&Board{LabelID: newLabelID})
+	return err
+}
+
+// GetBoardIDsByLabelIDs returns the IDs of the boards which are backed by the given labels, keyed by label ID.
+// Labels which do not back a board are not contained.
+func GetBoardIDsByLabelIDs(ctx context.Context, labelIDs []int64) (map[int64]int64, error) {
+	if len(labelIDs) == 0 {
+		return map[int64]int64{}, nil
+	}
+
+	boards := make([]*Board, 0, len(labelIDs))
+	if err := db.GetEngine(ctx).In("label_id", labelIDs).Asc("id").Find(&boards); err != nil {
+		return nil, err
+	}
+
+	boardIDs := make(map[int64]int64, len(boards))
+	for _, board := range boards {
+		if _, ok := boardIDs[board.LabelID]; !ok {
+			boardIDs[board.LabelID] = board.ID
+		}
+	}
+	return boardIDs, nil
+}
+
 // GetBoards fetches all boards related to a project
 // if no default board set, first board is a temporary "Uncategorized" board
 func GetBoards(ctx context.Context, projectID int64) (BoardList, error) {
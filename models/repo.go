@@ -398,10 +398,6 @@ func userStatsCorrectNumRepos(ctx context.Context, id int64) error {
 	return StatsCorrectSQL(ctx, "UPDATE `user` SET num_repos=(SELECT COUNT(*) FROM `repository` WHERE owner_id=?) WHERE id=?", id)
 }
 
-func repoStatsCorrectIssueNumComments(ctx context.Context, id int64) error {
-	return StatsCorrectSQL(ctx, "UPDATE `issue` SET num_comments=(SELECT COUNT(*) FROM `comment` WHERE issue_id=? AND type=0) WHERE id=?", id)
-}
-
 func repoStatsCorrectNumIssues(ctx context.Context, id int64) error {
 	return repo_model.UpdateRepoIssueNumbers(ctx, id, false, false)
 }
@@ -492,7 +488,7 @@ func CheckRepoStats(ctx context.Context) error {
 		// Issue.NumComments
 		{
 			statsQuery("SELECT `issue`.id FROM `issue` WHERE `issue`.num_comments!=(SELECT COUNT(*) FROM `comment` WHERE issue_id=`issue`.id AND type=0)"),
-			repoStatsCorrectIssueNumComments,
+			issues_model.UpdateIssueNumComments,
 			"issue count 'num_comments'",
 		},
 	}
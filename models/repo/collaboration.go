@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models/perm"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
 )
@@ -84,6 +85,26 @@ func IsCollaborator(ctx context.Context, repoID, userID int64) (bool, error) {
 	return db.GetEngine(ctx).Get(&Collaboration{RepoID: repoID, UserID: userID})
 }
 
+// GetCollaboratorIDsByRepoIDs returns which of the users are collaborators of the repositories, keyed by repository ID
+func GetCollaboratorIDsByRepoIDs(ctx context.Context, repoIDs, userIDs []int64) (map[int64]container.Set[int64], error) {
+	collaborators := make(map[int64]container.Set[int64], len(repoIDs))
+	if len(repoIDs) == 0 || len(userIDs) == 0 {
+		return collaborators, nil
+	}
+
+	collaborations := make([]*Collaboration, 0, len(userIDs))
+	if err := db.GetEngine(ctx).In("repo_id", repoIDs).In("user_id", userIDs).Find(&collaborations); err != nil {
+		return nil, err
+	}
+	for _, c := range collaborations {
+		if collaborators[c.RepoID] == nil {
+			collaborators[c.RepoID] = make(container.Set[int64])
+		}
+		collaborators[c.RepoID].Add(c.UserID)
+	}
+	return collaborators, nil
+}
+
 func getCollaborations(ctx context.Context, repoID int64, listOptions db.ListOptions) ([]*Collaboration, error) {
 	if listOptions.Page == 0 {
 		collaborations := make([]*Collaboration, 0, 8)
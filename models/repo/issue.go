@@ -54,6 +54,15 @@ func (repo *Repository) AllowOnlyContributorsToTrackTime() bool {
 	return u.IssuesConfig().AllowOnlyContributorsToTrackTime
 }
 
+// IsAutoCloseMilestonesEnabled returns whether milestones are closed and reopened along with their issues
+func (repo *Repository) IsAutoCloseMilestonesEnabled(ctx context.Context) bool {
+	u, err := repo.GetUnitCtx(ctx, unit.TypeIssues)
+	if err != nil {
+		return false
+	}
+	return u.IssuesConfig().AutoCloseMilestones
+}
+
 // IsDependenciesEnabled returns if dependencies are enabled and returns the default setting if not set.
 func (repo *Repository) IsDependenciesEnabled() bool {
 	return repo.IsDependenciesEnabledCtx(db.DefaultContext)
@@ -101,6 +101,8 @@ type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	// close milestones when their last open issue is closed and reopen them when one of their issues is reopened
+	AutoCloseMilestones bool
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
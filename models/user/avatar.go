@@ -4,22 +4,36 @@
 package user
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
+	"errors"
 	"fmt"
+	"image"
 	"image/png"
 	"io"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models/avatars"
 	"code.gitea.io/gitea/models/db"
 	system_model "code.gitea.io/gitea/models/system"
 	"code.gitea.io/gitea/modules/avatar"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/util"
 )
 
+// avatarGenerationPool makes sure concurrent requests only generate the random avatar of a user once
+var avatarGenerationPool = sync.NewExclusivePool()
+
 // CustomAvatarRelativePath returns user custom avatar relative path.
 func (u *User) CustomAvatarRelativePath() string {
 	return u.Avatar
@@ -27,46 +41,127 @@ func (u *User) CustomAvatarRelativePath() string {
 
 // GenerateRandomAvatar generates a random avatar for user.
 func GenerateRandomAvatar(ctx context.Context, u *User) error {
-	seed := u.Email
+	seed, seedSource := u.Email, "email"
 	if len(seed) == 0 {
-		seed = u.Name
+		seed, seedSource = u.Name, "name"
 	}
 
-	img, err := avatar.RandomImage([]byte(seed))
-	if err != nil {
-		return fmt.Errorf("RandomImage: %w", err)
-	}
+	avatarPath := avatars.HashEmail(seed)
+	logFields := randomAvatarLogFields(ctx, u, avatarPath, seedSource)
 
-	u.Avatar = avatars.HashEmail(seed)
+	// A previous generation may have stored the image without persisting the avatar column,
+	// the image only depends on the seed so the stored one can be reused.
+	if _, err := storage.Avatars.Stat(avatarPath); err != nil {
+		img, err := avatar.RandomImage([]byte(seed))
+		if err != nil {
+			return fmt.Errorf("RandomImage: %w", err)
+		}
 
-	// Don't share the images so that we can delete them easily
-	if err := storage.SaveFrom(storage.Avatars, u.CustomAvatarRelativePath(), func(w io.Writer) error {
-		if err := png.Encode(w, img); err != nil {
-			log.Error("Encode: %v", err)
+		// Don't share the images so that we can delete them easily
+		if err := saveRandomAvatar(ctx, avatarPath, img, logFields); err != nil {
+			return err
 		}
-		return err
-	}); err != nil {
-		return fmt.Errorf("Failed to create dir %s: %w", u.CustomAvatarRelativePath(), err)
 	}
 
-	if _, err := db.GetEngine(ctx).ID(u.ID).Cols("avatar").Update(u); err != nil {
+	u.Avatar = avatarPath
+	// make sure the link is only handed out once the avatar column has really been written
+	if affected, err := db.GetEngine(ctx).ID(u.ID).Cols("avatar").Update(u); err != nil {
+		u.Avatar = ""
 		return err
+	} else if affected == 0 {
+		u.Avatar = ""
+		return fmt.Errorf("avatar %s of user %d has not been persisted", avatarPath, u.ID)
 	}
 
-	log.Info("New random avatar created: %d", u.ID)
+	log.Info("New random avatar created [%s]", logFields)
 	return nil
 }
 
-// AvatarLinkWithSize returns a link to the user's avatar with size. size <= 0 means default size
-func (u *User) AvatarLinkWithSize(size int) string {
-	if u.ID == -1 {
-		// ghost user
-		return avatars.DefaultAvatarLink()
+// regenerateStaleRandomAvatar regenerates the random avatar of the user if it has been generated from a previous
+// email address, so that it follows the current one. The old image is removed unless another user still refers to it,
+// but only if the new avatar is not part of a transaction which could still be rolled back. Within a transaction the
+// old image is kept and left to the doctor's cleanup of orphaned avatars.
+// Uploaded avatars and avatars served by Gravatar are left untouched.
+func regenerateStaleRandomAvatar(ctx context.Context, u *User) error {
+	if u.UseCustomAvatar || len(u.Avatar) == 0 || len(u.Email) == 0 {
+		return nil
+	}
+	if _, autoGenerateAvatar := u.avatarMode(); !autoGenerateAvatar {
+		return nil
+	}
+	oldAvatarPath := u.Avatar
+	if oldAvatarPath == avatars.HashEmail(u.Email) {
+		return nil
+	}
+
+	if err := GenerateRandomAvatar(ctx, u); err != nil {
+		return err
+	}
+	if db.InTransaction(ctx) {
+		log.Debug("Keeping the stale random avatar %s of user %d until it is orphaned", oldAvatarPath, u.ID)
+		return nil
+	}
+	if inUse, err := ExistsWithAvatarAtStoragePath(ctx, oldAvatarPath); err != nil || inUse {
+		return err
+	}
+	if err := storage.Avatars.Delete(oldAvatarPath); err != nil {
+		return fmt.Errorf("Failed to remove %s: %w", oldAvatarPath, err)
+	}
+	return nil
+}
+
+// randomAvatarLogFields describes a random avatar generation for the logs, the pid relates the
+// messages to the request or task the generation was triggered by
+func randomAvatarLogFields(ctx context.Context, u *User, avatarPath, seedSource string) string {
+	return fmt.Sprintf("uid: %d, pid: %s, path: %s, seed: %s", u.ID, process.GetPID(ctx), avatarPath, seedSource)
+}
+
+// saveRandomAvatar stores the generated avatar image. Transient storage errors are retried with an
+// exponential backoff until ctx is done, encoding errors and permanent storage errors are returned immediately.
+func saveRandomAvatar(ctx context.Context, avatarPath string, img image.Image, logFields string) error {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return fmt.Errorf("Encode: %w", err)
+	}
+
+	backoff := setting.Avatar.StoreRetryBackoff
+	for attempt := 1; ; attempt++ {
+		_, err := storage.Avatars.Save(avatarPath, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err == nil {
+			return nil
+		}
+		if attempt >= setting.Avatar.MaxStoreAttempts || !isTransientStorageError(err) {
+			return fmt.Errorf("Failed to create dir %s: %w", avatarPath, err)
+		}
+		log.Warn("Failed to store random avatar (attempt %d of %d), retrying in %v [%s]: %v", attempt, setting.Avatar.MaxStoreAttempts, backoff, logFields, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("Failed to create dir %s: %w", avatarPath, ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
 	}
+}
 
-	useLocalAvatar := false
-	autoGenerateAvatar := false
+// isTransientStorageError returns false for storage errors which will not go away by trying again
+func isTransientStorageError(err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, os.ErrPermission), errors.Is(err, os.ErrInvalid), errors.Is(err, os.ErrExist):
+		return false
+	case errors.Is(err, util.ErrPermissionDenied), errors.Is(err, util.ErrInvalidArgument):
+		return false
+	}
+	return true
+}
 
+// avatarMode returns whether the avatar of the user is served from the local storage
+// and whether a random avatar should be generated if the user has none yet
+func (u *User) avatarMode() (useLocalAvatar, autoGenerateAvatar bool) {
 	disableGravatarSetting, _ := system_model.GetSetting(system_model.KeyPictureDisableGravatar)
 
 	disableGravatar := disableGravatarSetting.GetValueBool()
@@ -78,14 +173,84 @@ func (u *User) AvatarLinkWithSize(size int) string {
 		useLocalAvatar = true
 		autoGenerateAvatar = true
 	}
+	return useLocalAvatar, autoGenerateAvatar
+}
+
+// AvatarSourceType represents where the avatar of a user is served from
+type AvatarSourceType int
+
+const (
+	// AvatarSourceDefault is the default avatar image
+	AvatarSourceDefault AvatarSourceType = iota
+	// AvatarSourceLocal is an avatar in the avatar storage, uploaded or generated
+	AvatarSourceLocal
+	// AvatarSourceGravatar is an avatar served by Gravatar (or a compatible service)
+	AvatarSourceGravatar
+)
+
+// String returns the name of the avatar source
+func (t AvatarSourceType) String() string {
+	switch t {
+	case AvatarSourceLocal:
+		return "local"
+	case AvatarSourceGravatar:
+		return "gravatar"
+	}
+	return "default"
+}
+
+// AvatarSource returns where the avatar link of the user points to, following the same rules as AvatarLinkWithSize.
+// Unlike AvatarLinkWithSize it never generates a random avatar.
+func (u *User) AvatarSource() AvatarSourceType {
+	if u.ID == -1 {
+		// ghost user
+		return AvatarSourceDefault
+	}
+
+	useLocalAvatar, autoGenerateAvatar := u.avatarMode()
+	if !useLocalAvatar {
+		return AvatarSourceGravatar
+	}
+	if u.Avatar == "" && !autoGenerateAvatar {
+		return AvatarSourceDefault
+	}
+	return AvatarSourceLocal
+}
 
+// AvatarLinkWithSize returns a link to the user's avatar with size. size <= 0 means default size
+func (u *User) AvatarLinkWithSize(size int) string {
+	return u.AvatarLinkWithSizeInOrg(size, "")
+}
+
+// AvatarLinkWithSizeAndScale returns a link to the user's avatar for displaying it with size pixels on a screen
+// with the given pixel density, the image is requested with size*scale pixels. A scale below 1 is treated as 1,
+// size <= 0 means default size and ignores the scale.
+func (u *User) AvatarLinkWithSizeAndScale(size, scale int) string {
+	if size > 0 && scale > 1 {
+		size *= scale
+	}
+	return u.AvatarLinkWithSizeInOrg(size, "")
+}
+
+// AvatarLinkWithSizeInOrg returns the avatar link like AvatarLinkWithSize, but a user without an avatar
+// gets the default member avatar of the organization. defaultMemberAvatar is the organization's
+// SettingsKeyDefaultMemberAvatar setting, so that it is loaded once for all members of a page;
+// an empty one falls back to the global default avatar.
+func (u *User) AvatarLinkWithSizeInOrg(size int, defaultMemberAvatar string) string {
+	if u.ID == -1 {
+		// ghost user
+		return avatars.DefaultAvatarLink()
+	}
+
+	useLocalAvatar, autoGenerateAvatar := u.avatarMode()
 	if useLocalAvatar {
 		if u.Avatar == "" && autoGenerateAvatar {
-			if err := GenerateRandomAvatar(db.DefaultContext, u); err != nil {
-				log.Error("GenerateRandomAvatar: %v", err)
-			}
+			u.generateAvatarOnRead(db.DefaultContext)
 		}
 		if u.Avatar == "" {
+			if defaultMemberAvatar != "" {
+				return avatars.GenerateUserAvatarImageLink(defaultMemberAvatar, size)
+			}
 			return avatars.DefaultAvatarLink()
 		}
 		return avatars.GenerateUserAvatarImageLink(u.Avatar, size)
@@ -93,9 +258,71 @@ func (u *User) AvatarLinkWithSize(size int) string {
 	return avatars.GenerateEmailAvatarFastLink(u.AvatarEmail, size)
 }
 
+// generateAvatarOnRead generates the missing random avatar of the user. Concurrent callers for the
+// same user wait for the first generation and reuse its result instead of generating it again.
+func (u *User) generateAvatarOnRead(ctx context.Context) {
+	identity := strconv.FormatInt(u.ID, 10)
+	avatarGenerationPool.CheckIn(identity)
+	defer avatarGenerationPool.CheckOut(identity)
+
+	// another request may have generated the avatar while we were waiting
+	var avatarPath string
+	if has, err := db.GetEngine(ctx).Table(new(User)).ID(u.ID).Cols("avatar").Get(&avatarPath); err != nil {
+		log.Error("GetUserAvatar: %v", err)
+		return
+	} else if has && avatarPath != "" {
+		u.Avatar = avatarPath
+		return
+	}
+
+	if err := GenerateRandomAvatar(ctx, u); err != nil {
+		log.Error("GenerateRandomAvatar [uid: %d, pid: %s]: %v", u.ID, process.GetPID(ctx), err)
+	}
+}
+
+// AvatarImageReader opens the avatar image of the user from the avatar storage and returns it with its content type.
+// If the avatar is not stored locally (Gravatar or the default avatar) an ErrUserAvatarNotStored is returned.
+func (u *User) AvatarImageReader(ctx context.Context) (io.ReadCloser, string, error) {
+	if u.ID == -1 {
+		// ghost user
+		return nil, "", ErrUserAvatarNotStored{UID: u.ID, Link: avatars.DefaultAvatarLink()}
+	}
+
+	useLocalAvatar, autoGenerateAvatar := u.avatarMode()
+	if !useLocalAvatar {
+		return nil, "", ErrUserAvatarNotStored{UID: u.ID, Link: avatars.GenerateEmailAvatarFastLink(u.AvatarEmail, 0)}
+	}
+	if u.Avatar == "" && autoGenerateAvatar {
+		u.generateAvatarOnRead(ctx)
+	}
+	if u.Avatar == "" {
+		return nil, "", ErrUserAvatarNotStored{UID: u.ID, Link: avatars.DefaultAvatarLink()}
+	}
+
+	obj, err := storage.Avatars.Open(u.CustomAvatarRelativePath())
+	if err != nil {
+		return nil, "", err
+	}
+	st, err := typesniffer.DetectContentTypeFromReader(obj)
+	if err != nil {
+		obj.Close()
+		return nil, "", err
+	}
+	if _, err := obj.Seek(0, io.SeekStart); err != nil {
+		obj.Close()
+		return nil, "", err
+	}
+	return obj, st.GetMimeType(), nil
+}
+
 // AvatarLink returns the full avatar link with http host
 func (u *User) AvatarLink() string {
-	link := u.AvatarLinkWithSize(0)
+	return u.AvatarFullLinkWithSize(0)
+}
+
+// AvatarFullLinkWithSize returns the full avatar link with http host and size. size <= 0 means default size
+func (u *User) AvatarFullLinkWithSize(size int) string {
+	link := u.AvatarLinkWithSize(size)
 	if !strings.HasPrefix(link, "//") && !strings.Contains(link, "://") {
 		return setting.AppURL + strings.TrimPrefix(link, setting.AppSubURL+"/")
 	}
@@ -112,8 +339,89 @@ func (u *User) IsUploadAvatarChanged(data []byte) bool {
 }
 
 // ExistsWithAvatarAtStoragePath returns true if there is a user with this Avatar
+// or an organization which uses it as default member avatar
 func ExistsWithAvatarAtStoragePath(ctx context.Context, storagePath string) (bool, error) {
 	// See func (u *User) CustomAvatarRelativePath()
 	// u.Avatar is used directly as the storage path - therefore we can check for existence directly using the path
-	return db.GetEngine(ctx).Where("`avatar`=?", storagePath).Exist(new(User))
+	exists, err := db.GetEngine(ctx).Where("`avatar`=?", storagePath).Exist(new(User))
+	if err != nil || exists {
+		return exists, err
+	}
+	return db.GetEngine(ctx).Where("setting_key=? AND setting_value=?", SettingsKeyDefaultMemberAvatar, storagePath).Exist(new(Setting))
+}
+
+// MigrateAvatars copies the avatars which are in use by a user from one storage to another and returns how many have been copied.
+// Avatars which already exist with the same size in the destination are skipped, so an interrupted migration can be resumed.
+// Avatars which are not used by any user are left behind.
+func MigrateAvatars(ctx context.Context, from, to storage.ObjectStorage) (int, error) {
+	copied := 0
+	err := from.IterateObjects(func(avatarPath string, obj storage.Object) error {
+		inUse, err := ExistsWithAvatarAtStoragePath(ctx, avatarPath)
+		if err != nil {
+			return err
+		}
+		if !inUse {
+			return nil
+		}
+
+		size := int64(-1)
+		if info, err := obj.Stat(); err == nil {
+			size = info.Size()
+		}
+		if info, err := to.Stat(avatarPath); err == nil && info.Size() == size {
+			return nil
+		}
+
+		if _, err := to.Save(avatarPath, obj, size); err != nil {
+			return fmt.Errorf("failed to copy avatar %s: %w", avatarPath, err)
+		}
+		copied++
+		return nil
+	})
+	return copied, err
+}
+
+// orphanAvatarMinAge is how old a stored avatar has to be before PruneOrphanAvatars may delete it. Avatars are saved
+// before the user is updated to refer to them, younger ones may still be in the middle of being set.
+const orphanAvatarMinAge = time.Hour
+
+// PruneOrphanAvatars deletes the avatars in the avatar storage which are not used by any user and returns their paths.
+// Avatars stored less than an hour ago are kept, as they may still be in the middle of being set. The default avatar
+// is a static asset and not kept in the storage. If dryRun is true, nothing is deleted and the paths which would be
+// deleted are returned.
+func PruneOrphanAvatars(ctx context.Context, dryRun bool) ([]string, error) {
+	minModTime := timeutil.TimeStampNow().AsTime().Add(-orphanAvatarMinAge)
+	var candidates []string
+	if err := storage.Avatars.IterateObjects(func(avatarPath string, obj storage.Object) error {
+		info, err := obj.Stat()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(minModTime) {
+			return nil
+		}
+		candidates = append(candidates, avatarPath)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// check the candidates only after listing them, so that avatars set in the meantime are not deleted
+	var pruned []string
+	for _, avatarPath := range candidates {
+		inUse, err := ExistsWithAvatarAtStoragePath(ctx, avatarPath)
+		if err != nil {
+			return pruned, err
+		}
+		if inUse {
+			continue
+		}
+		if !dryRun {
+			if err := storage.Avatars.Delete(avatarPath); err != nil {
+				return pruned, fmt.Errorf("failed to remove %s: %w", avatarPath, err)
+			}
+		}
+		pruned = append(pruned, avatarPath)
+	}
+	return pruned, nil
 }
@@ -0,0 +1,478 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/avatars"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_AvatarImageReader(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	data, err := os.ReadFile("../../modules/avatar/testdata/avatar.png")
+	assert.NoError(t, err)
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user.UseCustomAvatar = true
+	_, err = storage.Avatars.Save(user.CustomAvatarRelativePath(), bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	defer storage.Avatars.Delete(user.CustomAvatarRelativePath())
+
+	rd, contentType, err := user.AvatarImageReader(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	content, err := io.ReadAll(rd)
+	assert.NoError(t, rd.Close())
+	assert.NoError(t, err)
+	assert.Equal(t, data, content)
+
+	// gravatar avatars are not stored locally
+	oldOfflineMode := setting.OfflineMode
+	setting.OfflineMode = false
+	defer func() {
+		setting.OfflineMode = oldOfflineMode
+	}()
+	user.UseCustomAvatar = false
+	_, _, err = user.AvatarImageReader(db.DefaultContext)
+	assert.True(t, user_model.IsErrUserAvatarNotStored(err))
+
+	// neither is the avatar of the ghost user
+	_, _, err = user_model.NewGhostUser().AvatarImageReader(db.DefaultContext)
+	assert.True(t, user_model.IsErrUserAvatarNotStored(err))
+}
+
+// countingStorage counts the saves and fails the first failures of them
+type countingStorage struct {
+	storage.ObjectStorage
+	mu       sync.Mutex
+	saves    int
+	failures int
+	err      error
+}
+
+func (s *countingStorage) Save(path string, r io.Reader, size int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saves++
+	if s.saves <= s.failures {
+		if s.err != nil {
+			return 0, s.err
+		}
+		return 0, errors.New("storage temporarily unavailable")
+	}
+	return s.ObjectStorage.Save(path, r, size)
+}
+
+func TestUser_AvatarLinkWithSizeGeneratesOnce(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldOfflineMode := setting.OfflineMode
+	oldAvatars := storage.Avatars
+	counting := &countingStorage{ObjectStorage: storage.Avatars}
+	setting.OfflineMode = true
+	storage.Avatars = counting
+	defer func() {
+		setting.OfflineMode = oldOfflineMode
+		storage.Avatars = oldAvatars
+	}()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user.Avatar = ""
+	_, err := db.GetEngine(db.DefaultContext).ID(user.ID).Cols("avatar").Update(user)
+	assert.NoError(t, err)
+
+	link := user.AvatarLinkWithSize(0)
+	assert.NotEmpty(t, user.Avatar)
+	defer storage.Avatars.Delete(user.CustomAvatarRelativePath())
+	assert.Equal(t, 1, counting.saves)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: user.ID, Avatar: user.Avatar})
+
+	// simulate a restart where the avatar column was lost, the stored image must be reused
+	_, err = db.GetEngine(db.DefaultContext).ID(user.ID).Cols("avatar").Update(&user_model.User{})
+	assert.NoError(t, err)
+	user = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.Empty(t, user.Avatar)
+
+	assert.Equal(t, link, user.AvatarLinkWithSize(0))
+	assert.Equal(t, 1, counting.saves)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: user.ID, Avatar: user.Avatar})
+}
+
+func TestUser_AvatarLinkWithSizeSingleFlight(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldOfflineMode := setting.OfflineMode
+	oldAvatars := storage.Avatars
+	counting := &countingStorage{ObjectStorage: storage.Avatars}
+	setting.OfflineMode = true
+	storage.Avatars = counting
+	defer func() {
+		setting.OfflineMode = oldOfflineMode
+		storage.Avatars = oldAvatars
+	}()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user.Avatar = ""
+	_, err := db.GetEngine(db.DefaultContext).ID(user.ID).Cols("avatar").Update(user)
+	assert.NoError(t, err)
+
+	links := make([]string, 20)
+	var wg sync.WaitGroup
+	for i := range links {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			u := *user
+			links[i] = u.AvatarLinkWithSize(0)
+		}(i)
+	}
+	wg.Wait()
+
+	user = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NotEmpty(t, user.Avatar)
+	defer oldAvatars.Delete(user.CustomAvatarRelativePath())
+	assert.Equal(t, 1, counting.saves)
+	for _, link := range links {
+		assert.Equal(t, user.AvatarLinkWithSize(0), link)
+	}
+}
+
+func TestUser_AvatarSource(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldOfflineMode := setting.OfflineMode
+	defer func() {
+		setting.OfflineMode = oldOfflineMode
+	}()
+	setting.OfflineMode = false
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	user.UseCustomAvatar = true
+	assert.Equal(t, user_model.AvatarSourceLocal, user.AvatarSource())
+
+	// custom avatar enabled but nothing uploaded
+	user.Avatar = ""
+	assert.Equal(t, user_model.AvatarSourceDefault, user.AvatarSource())
+
+	user.UseCustomAvatar = false
+	assert.Equal(t, user_model.AvatarSourceGravatar, user.AvatarSource())
+
+	// a random avatar would be generated in offline mode
+	setting.OfflineMode = true
+	assert.Equal(t, user_model.AvatarSourceLocal, user.AvatarSource())
+	assert.Empty(t, user.Avatar)
+
+	assert.Equal(t, user_model.AvatarSourceDefault, user_model.NewGhostUser().AvatarSource())
+	assert.Equal(t, "default", user_model.AvatarSourceDefault.String())
+}
+
+func TestGenerateRandomAvatarRetry(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldAvatars := storage.Avatars
+	oldAttempts, oldBackoff := setting.Avatar.MaxStoreAttempts, setting.Avatar.StoreRetryBackoff
+	setting.Avatar.MaxStoreAttempts = 3
+	setting.Avatar.StoreRetryBackoff = time.Millisecond
+	defer func() {
+		storage.Avatars = oldAvatars
+		setting.Avatar.MaxStoreAttempts, setting.Avatar.StoreRetryBackoff = oldAttempts, oldBackoff
+	}()
+
+	// two transient failures are retried
+	flaky := &countingStorage{ObjectStorage: oldAvatars, failures: 2}
+	storage.Avatars = flaky
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, user_model.GenerateRandomAvatar(db.DefaultContext, user))
+	defer oldAvatars.Delete(user.CustomAvatarRelativePath())
+	assert.Equal(t, 3, flaky.saves)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: user.ID, Avatar: user.CustomAvatarRelativePath()})
+
+	// giving up after the configured attempts leaves the avatar of the user untouched
+	broken := &countingStorage{ObjectStorage: oldAvatars, failures: 10}
+	storage.Avatars = broken
+	user = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	assert.Error(t, user_model.GenerateRandomAvatar(db.DefaultContext, user))
+	assert.Equal(t, 3, broken.saves)
+	assert.Equal(t, "avatar4", user.Avatar)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: user.ID, Avatar: "avatar4"})
+
+	// permanent errors are not retried
+	denied := &countingStorage{ObjectStorage: oldAvatars, failures: 10, err: os.ErrPermission}
+	storage.Avatars = denied
+	assert.ErrorIs(t, user_model.GenerateRandomAvatar(db.DefaultContext, user), os.ErrPermission)
+	assert.Equal(t, 1, denied.saves)
+
+	// the retries stop once the context is done
+	setting.Avatar.StoreRetryBackoff = time.Hour
+	canceled := &countingStorage{ObjectStorage: oldAvatars, failures: 10}
+	storage.Avatars = canceled
+	ctx, cancel := context.WithCancel(db.DefaultContext)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	assert.ErrorIs(t, user_model.GenerateRandomAvatar(ctx, user), context.Canceled)
+	assert.Equal(t, 1, canceled.saves)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: user.ID, Avatar: "avatar4"})
+}
+
+func TestGenerateRandomAvatar_NotPersisted(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// the avatar of a user which does not exist (anymore) can't be written
+	user := &user_model.User{ID: unittest.NonexistentID, Name: "nonexistent", Email: "nonexistent@example.com"}
+	assert.Error(t, user_model.GenerateRandomAvatar(db.DefaultContext, user))
+	defer storage.Avatars.Delete(avatars.HashEmail(user.Email))
+	assert.Empty(t, user.Avatar)
+}
+
+func TestGenerateRandomAvatar_LogFields(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	logger, ok := log.NamedLoggers.Load(log.DEFAULT)
+	assert.True(t, ok)
+	assert.NoError(t, logger.SetLogger("buffer", "buffer", "{}"))
+	defer logger.DelLogger("buffer")
+
+	// the generation is not necessarily running on the goroutine of the process which triggered it
+	ctx, _, finished := process.GetManager().AddTypedContext(db.DefaultContext, "TestGenerateRandomAvatar_LogFields", process.NormalProcessType, false)
+	defer finished()
+	pid := process.GetPID(ctx)
+	assert.NotEmpty(t, pid)
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, user_model.GenerateRandomAvatar(ctx, user))
+	defer storage.Avatars.Delete(user.CustomAvatarRelativePath())
+	emailAvatar := user.Avatar
+
+	user = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	user.Email = ""
+	assert.NoError(t, user_model.GenerateRandomAvatar(ctx, user))
+	defer storage.Avatars.Delete(user.CustomAvatarRelativePath())
+	nameAvatar := user.Avatar
+
+	fence := ">>>>>>>>>>>>>FENCE TestGenerateRandomAvatar_LogFields<<<<<<<<<<<<<<<"
+	log.Error(fence)
+	var content string
+	for i := 0; i < 5000; i++ {
+		var err error
+		content, err = logger.GetLoggerProviderContent("buffer")
+		assert.NoError(t, err)
+		if strings.Contains(content, fence) {
+			break
+		}
+		time.Sleep(1 * time.Millisecond)
+	}
+	assert.Contains(t, content, fmt.Sprintf("New random avatar created [uid: 2, pid: %s, path: %s, seed: email]", pid, emailAvatar))
+	assert.Contains(t, content, fmt.Sprintf("New random avatar created [uid: 4, pid: %s, path: %s, seed: name]", pid, nameAvatar))
+}
+
+func TestUser_AvatarLinkWithSizeAndScale(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldOfflineMode := setting.OfflineMode
+	defer func() {
+		setting.OfflineMode = oldOfflineMode
+	}()
+	setting.OfflineMode = false
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user.UseCustomAvatar = true
+	assert.Equal(t, avatars.GenerateUserAvatarImageLink("avatar2", 56), user.AvatarLinkWithSizeAndScale(28, 2))
+	assert.Equal(t, user.AvatarLinkWithSize(28), user.AvatarLinkWithSizeAndScale(28, 1))
+	assert.Equal(t, user.AvatarLinkWithSize(28), user.AvatarLinkWithSizeAndScale(28, 0))
+	assert.Equal(t, user.AvatarLinkWithSize(0), user.AvatarLinkWithSizeAndScale(0, 2))
+
+	user.UseCustomAvatar = false
+	assert.Equal(t, avatars.GenerateEmailAvatarFastLink(user.AvatarEmail, 56), user.AvatarLinkWithSizeAndScale(28, 2))
+
+	// the default avatar has no size
+	user.UseCustomAvatar = true
+	user.Avatar = ""
+	assert.Equal(t, avatars.DefaultAvatarLink(), user.AvatarLinkWithSizeAndScale(28, 2))
+	assert.Equal(t, avatars.DefaultAvatarLink(), user_model.NewGhostUser().AvatarLinkWithSizeAndScale(28, 2))
+}
+
+func TestUser_AvatarLinkWithSizeInOrg(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// a custom avatar which has not been uploaded yet leaves the user without an avatar
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user.UseCustomAvatar = true
+	user.Avatar = ""
+
+	// without a configured default member avatar the global default is used
+	assert.Equal(t, avatars.DefaultAvatarLink(), user.AvatarLinkWithSizeInOrg(28, ""))
+
+	assert.Equal(t, avatars.GenerateUserAvatarImageLink("org3-member", 28), user.AvatarLinkWithSizeInOrg(28, "org3-member"))
+	assert.Equal(t, avatars.DefaultAvatarLink(), user.AvatarLinkWithSize(28))
+
+	// users with an avatar keep it
+	user.Avatar = "avatar2"
+	assert.Equal(t, avatars.GenerateUserAvatarImageLink("avatar2", 28), user.AvatarLinkWithSizeInOrg(28, "org3-member"))
+
+	// the ghost user always gets the global default
+	assert.Equal(t, avatars.DefaultAvatarLink(), user_model.NewGhostUser().AvatarLinkWithSizeInOrg(28, "org3-member"))
+}
+
+func TestUser_RegenerateRandomAvatarOnEmailChange(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldOfflineMode := setting.OfflineMode
+	setting.OfflineMode = true
+	defer func() {
+		setting.OfflineMode = oldOfflineMode
+	}()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, user_model.GenerateRandomAvatar(db.DefaultContext, user))
+	oldAvatar := user.Avatar
+	assert.Equal(t, avatars.HashEmail("user2@example.com"), oldAvatar)
+
+	newEmail := &user_model.EmailAddress{UID: user.ID, Email: "user2-new@example.com", IsActivated: true}
+	assert.NoError(t, db.Insert(db.DefaultContext, newEmail))
+	assert.NoError(t, user_model.MakeEmailPrimary(&user_model.EmailAddress{ID: newEmail.ID}))
+
+	// the avatar is seeded with the new email and the old image is gone
+	user = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.Equal(t, avatars.HashEmail("user2-new@example.com"), user.Avatar)
+	defer storage.Avatars.Delete(user.CustomAvatarRelativePath())
+	_, err := storage.Avatars.Stat(user.Avatar)
+	assert.NoError(t, err)
+	_, err = storage.Avatars.Stat(oldAvatar)
+	assert.Error(t, err)
+
+	// changing the email as an administrator does the same
+	user.Email = "user2-admin@example.com"
+	assert.NoError(t, user_model.UpdateUser(db.DefaultContext, user, true))
+	user = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.Equal(t, avatars.HashEmail("user2-admin@example.com"), user.Avatar)
+	defer storage.Avatars.Delete(user.CustomAvatarRelativePath())
+
+	// within a transaction which is rolled back the old image is kept for the avatar column that is restored
+	adminAvatar := user.Avatar
+	ctx, committer, err := db.TxContext(db.DefaultContext)
+	assert.NoError(t, err)
+	user.Email = "user2-rollback@example.com"
+	assert.NoError(t, user_model.UpdateUser(ctx, user, true))
+	defer storage.Avatars.Delete(avatars.HashEmail("user2-rollback@example.com"))
+	assert.NoError(t, committer.Close())
+	user = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.Equal(t, adminAvatar, user.Avatar)
+	_, err = storage.Avatars.Stat(adminAvatar)
+	assert.NoError(t, err)
+
+	// uploaded avatars are kept
+	user.UseCustomAvatar = true
+	user.Avatar = "custom-avatar"
+	assert.NoError(t, user_model.UpdateUserCols(db.DefaultContext, user, "use_custom_avatar", "avatar"))
+	user.Email = "user2@example.com"
+	assert.NoError(t, user_model.UpdateUser(db.DefaultContext, user, true))
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2, Avatar: "custom-avatar"})
+}
+
+func TestMigrateAvatars(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	newStorage := func() storage.ObjectStorage {
+		s, err := storage.NewLocalStorage(db.DefaultContext, storage.LocalStorageConfig{Path: t.TempDir()})
+		assert.NoError(t, err)
+		return s
+	}
+	from := newStorage()
+	to := &countingStorage{ObjectStorage: newStorage()}
+
+	// avatar4 is used by user 4, the orphan is not used by anyone
+	for _, p := range []string{"avatar4", "orphan"} {
+		_, err := from.Save(p, strings.NewReader("content of "+p), -1)
+		assert.NoError(t, err)
+	}
+
+	copied, err := user_model.MigrateAvatars(db.DefaultContext, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, copied)
+	_, err = to.Stat("avatar4")
+	assert.NoError(t, err)
+	_, err = to.Stat("orphan")
+	assert.Error(t, err)
+
+	// a second run has nothing left to copy
+	copied, err = user_model.MigrateAvatars(db.DefaultContext, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, copied)
+	assert.Equal(t, 1, to.saves)
+
+	// an incompletely copied avatar is copied again
+	_, err = to.ObjectStorage.Save("avatar4", strings.NewReader("content"), -1)
+	assert.NoError(t, err)
+	copied, err = user_model.MigrateAvatars(db.DefaultContext, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, copied)
+	rd, err := to.Open("avatar4")
+	assert.NoError(t, err)
+	content, err := io.ReadAll(rd)
+	assert.NoError(t, rd.Close())
+	assert.NoError(t, err)
+	assert.Equal(t, "content of avatar4", string(content))
+}
+
+func TestPruneOrphanAvatars(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	avatarStorage, err := storage.NewLocalStorage(db.DefaultContext, storage.LocalStorageConfig{Path: t.TempDir()})
+	assert.NoError(t, err)
+	oldAvatars := storage.Avatars
+	storage.Avatars = avatarStorage
+	defer func() {
+		storage.Avatars = oldAvatars
+	}()
+
+	// avatar4 is used by user 4, the orphan is not used by anyone
+	for _, p := range []string{"avatar4", "orphan"} {
+		_, err := storage.Avatars.Save(p, strings.NewReader("content of "+p), -1)
+		assert.NoError(t, err)
+	}
+
+	// freshly stored avatars may still be in the middle of being set
+	pruned, err := user_model.PruneOrphanAvatars(db.DefaultContext, false)
+	assert.NoError(t, err)
+	assert.Empty(t, pruned)
+
+	defer timeutil.Unset()
+	timeutil.Set(time.Now().Add(2 * time.Hour))
+
+	// a dry run only reports the orphan
+	pruned, err = user_model.PruneOrphanAvatars(db.DefaultContext, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orphan"}, pruned)
+	_, err = storage.Avatars.Stat("orphan")
+	assert.NoError(t, err)
+
+	pruned, err = user_model.PruneOrphanAvatars(db.DefaultContext, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orphan"}, pruned)
+	_, err = storage.Avatars.Stat("orphan")
+	assert.Error(t, err)
+	_, err = storage.Avatars.Stat("avatar4")
+	assert.NoError(t, err)
+}
@@ -397,7 +397,14 @@ func MakeEmailPrimary(email *EmailAddress) error {
 		return err
 	}
 
-	return committer.Commit()
+	if err := committer.Commit(); err != nil {
+		return err
+	}
+
+	if err := regenerateStaleRandomAvatar(db.DefaultContext, user); err != nil {
+		log.Error("regenerateStaleRandomAvatar[%d]: %v", user.ID, err)
+	}
+	return nil
 }
 
 // VerifyActiveEmailCode verifies active email code when active account
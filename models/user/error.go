@@ -99,3 +99,26 @@ func (err ErrUserInactive) Error() string {
 func (err ErrUserInactive) Unwrap() error {
 	return util.ErrPermissionDenied
 }
+
+// ErrUserAvatarNotStored represents a "UserAvatarNotStored" kind of error.
+// It is returned when the avatar of a user is served from a remote service (e.g. Gravatar)
+// or is the default avatar, so there is no image in the avatar storage.
+type ErrUserAvatarNotStored struct {
+	UID  int64
+	Link string
+}
+
+// IsErrUserAvatarNotStored checks if an error is a ErrUserAvatarNotStored
+func IsErrUserAvatarNotStored(err error) bool {
+	_, ok := err.(ErrUserAvatarNotStored)
+	return ok
+}
+
+func (err ErrUserAvatarNotStored) Error() string {
+	return fmt.Sprintf("user avatar is not stored locally [uid: %d, link: %s]", err.UID, err.Link)
+}
+
+// Unwrap unwraps this error as a ErrNotExist error
+func (err ErrUserAvatarNotStored) Unwrap() error {
+	return util.ErrNotExist
+}
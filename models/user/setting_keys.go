@@ -8,6 +8,9 @@ const (
 	SettingsKeyHiddenCommentTypes = "issue.hidden_comment_types"
 	// SettingsKeyDiffWhitespaceBehavior is the setting key for whitespace behavior of diff
 	SettingsKeyDiffWhitespaceBehavior = "diff.whitespace_behaviour"
+	// SettingsKeyDefaultMemberAvatar is the setting key for the avatar an organization shows for its
+	// members without an avatar, the value is the path of the image in the avatar storage
+	SettingsKeyDefaultMemberAvatar = "avatar.default_member_avatar"
 	// UserActivityPubPrivPem is user's private key
 	UserActivityPubPrivPem = "activitypub.priv_pem"
 	// UserActivityPubPubPem is user's public key
@@ -941,7 +941,16 @@ func UpdateUser(ctx context.Context, u *User, changePrimaryEmail bool, cols ...s
 	} else {
 		_, err = e.ID(u.ID).Cols(cols...).Update(u)
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	if changePrimaryEmail {
+		if err := regenerateStaleRandomAvatar(ctx, u); err != nil {
+			log.Error("regenerateStaleRandomAvatar[%d]: %v", u.ID, err)
+		}
+	}
+	return nil
 }
 
 // UpdateUserCols update user according special columns
@@ -37,6 +37,8 @@ const (
 	HookEventIssueLabel                HookEventType = "issue_label"
 	HookEventIssueMilestone            HookEventType = "issue_milestone"
 	HookEventIssueComment              HookEventType = "issue_comment"
+	HookEventIssueTimeTracked          HookEventType = "issue_time_tracked"
+	HookEventMilestone                 HookEventType = "milestone"
 	HookEventPullRequest               HookEventType = "pull_request"
 	HookEventPullRequestAssign         HookEventType = "pull_request_assign"
 	HookEventPullRequestLabel          HookEventType = "pull_request_label"
@@ -70,6 +72,10 @@ func (h HookEventType) Event() string {
 		return "pull_request"
 	case HookEventIssueComment, HookEventPullRequestComment:
 		return "issue_comment"
+	case HookEventIssueTimeTracked:
+		return "issue_time_tracked"
+	case HookEventMilestone:
+		return "milestone"
 	case HookEventPullRequestReviewApproved:
 		return "pull_request_approved"
 	case HookEventPullRequestReviewRejected:
@@ -127,6 +127,8 @@ type HookEvents struct {
 	IssueLabel           bool `json:"issue_label"`
 	IssueMilestone       bool `json:"issue_milestone"`
 	IssueComment         bool `json:"issue_comment"`
+	IssueTimeTracked     bool `json:"issue_time_tracked"`
+	Milestone            bool `json:"milestone"`
 	Push                 bool `json:"push"`
 	PullRequest          bool `json:"pull_request"`
 	PullRequestAssign    bool `json:"pull_request_assign"`
@@ -149,6 +151,47 @@ type HookEvent struct {
 	BranchFilter   string `json:"branch_filter"`
 
 	HookEvents `json:"events"`
+
+	IssuePayload IssuePayloadSettings `json:"issue_payload"`
+}
+
+// IssueBodyMode is how the body of an issue is sent to a webhook
+type IssueBodyMode string
+
+const (
+	// IssueBodyFull sends the whole body
+	IssueBodyFull IssueBodyMode = ""
+	// IssueBodyTruncated sends the beginning of the body
+	IssueBodyTruncated IssueBodyMode = "truncated"
+	// IssueBodyOmitted sends no body
+	IssueBodyOmitted IssueBodyMode = "omitted"
+)
+
+// ToIssueBodyMode converts the name of an issue body mode used in the API to IssueBodyMode
+func ToIssueBodyMode(name string) IssueBodyMode {
+	if name == "full" {
+		return IssueBodyFull
+	}
+	return IssueBodyMode(name)
+}
+
+// Name returns the name of the issue body mode used in the API
+func (m IssueBodyMode) Name() string {
+	if m == IssueBodyFull {
+		return "full"
+	}
+	return string(m)
+}
+
+// DefaultIssueBodyMaxLength is the number of characters bodies are truncated to if no length is configured
+const DefaultIssueBodyMaxLength = 256
+
+// IssuePayloadSettings configures which information about issues is sent to a webhook
+type IssuePayloadSettings struct {
+	BodyMode IssueBodyMode `json:"body_mode,omitempty"`
+	// number of characters, including the ellipsis, the body is truncated to in IssueBodyTruncated mode
+	BodyMaxLength       int  `json:"body_max_length,omitempty"`
+	StripAssigneeEmails bool `json:"strip_assignee_emails,omitempty"`
 }
 
 // HookType is the type of a webhook
@@ -275,6 +318,18 @@ func (w *Webhook) HasIssueCommentEvent() bool {
 		(w.ChooseEvents && w.HookEvents.IssueComment)
 }
 
+// HasIssueTimeTrackedEvent returns true if hook enabled issue tracked time event.
+func (w *Webhook) HasIssueTimeTrackedEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.IssueTimeTracked)
+}
+
+// HasMilestoneEvent returns true if hook enabled milestone event.
+func (w *Webhook) HasMilestoneEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Milestone)
+}
+
 // HasPushEvent returns true if hook enabled push event.
 func (w *Webhook) HasPushEvent() bool {
 	return w.PushOnly || w.SendEverything ||
@@ -377,6 +432,8 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasIssuesLabelEvent, HookEventIssueLabel},
 		{w.HasIssuesMilestoneEvent, HookEventIssueMilestone},
 		{w.HasIssueCommentEvent, HookEventIssueComment},
+		{w.HasIssueTimeTrackedEvent, HookEventIssueTimeTracked},
+		{w.HasMilestoneEvent, HookEventMilestone},
 		{w.HasPullRequestEvent, HookEventPullRequest},
 		{w.HasPullRequestAssignEvent, HookEventPullRequestAssign},
 		{w.HasPullRequestLabelEvent, HookEventPullRequestLabel},
@@ -67,7 +67,7 @@ func TestWebhook_UpdateEvent(t *testing.T) {
 func TestWebhook_EventsArray(t *testing.T) {
 	assert.Equal(t, []string{
 		"create", "delete", "fork", "push",
-		"issues", "issue_assign", "issue_label", "issue_milestone", "issue_comment",
+		"issues", "issue_assign", "issue_label", "issue_milestone", "issue_comment", "issue_time_tracked", "milestone",
 		"pull_request", "pull_request_assign", "pull_request_label", "pull_request_milestone",
 		"pull_request_comment", "pull_request_review_approved", "pull_request_review_rejected",
 		"pull_request_review_comment", "pull_request_sync", "wiki", "repository", "release",
@@ -4,17 +4,21 @@
 package avatar
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
-
 	_ "image/gif"  // for processing gif images
 	_ "image/jpeg" // for processing jpeg images
 	_ "image/png"  // for processing png images
+	"io"
+	"strings"
 
 	"code.gitea.io/gitea/modules/avatar/identicon"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/typesniffer"
 
 	"github.com/nfnt/resize"
 	"github.com/oliamb/cutter"
@@ -23,35 +27,144 @@ import (
 // AvatarSize returns avatar's size
 const AvatarSize = 290
 
+// ErrAnimatedAvatarTooLarge represents an error that an animated avatar exceeds the configured limits
+type ErrAnimatedAvatarTooLarge struct {
+	Frames int
+	Size   int
+}
+
+// IsErrAnimatedAvatarTooLarge checks if an error is a ErrAnimatedAvatarTooLarge
+func IsErrAnimatedAvatarTooLarge(err error) bool {
+	_, ok := err.(ErrAnimatedAvatarTooLarge)
+	return ok
+}
+
+func (err ErrAnimatedAvatarTooLarge) Error() string {
+	return fmt.Sprintf("animated avatar is too large [frames: %d, size: %d]", err.Frames, err.Size)
+}
+
 // RandomImageSize generates and returns a random avatar image unique to input data
 // in custom size (height and width).
 func RandomImageSize(size int, data []byte) (image.Image, error) {
-	// we use white as background, and use dark colors to draw blocks
-	imgMaker, err := identicon.New(size, color.White, identicon.DarkColors...)
+	// we use white as background, and use dark colors or the configured palette to draw blocks
+	imgMaker, err := identicon.New(size, color.White, paletteColors()...)
 	if err != nil {
 		return nil, fmt.Errorf("identicon.New: %w", err)
 	}
 	return imgMaker.Make(data), nil
 }
 
+// paletteColors returns the configured palette, or the built-in dark colors if there is none
+func paletteColors() []color.Color {
+	if len(setting.Avatar.Palette) == 0 {
+		return identicon.DarkColors
+	}
+	colors := make([]color.Color, 0, len(setting.Avatar.Palette))
+	for _, hex := range setting.Avatar.Palette {
+		var r, g, b uint8
+		if _, err := fmt.Sscanf(strings.TrimPrefix(hex, "#"), "%02x%02x%02x", &r, &g, &b); err != nil {
+			continue
+		}
+		colors = append(colors, color.RGBA{R: r, G: g, B: b, A: 255})
+	}
+	if len(colors) == 0 {
+		return identicon.DarkColors
+	}
+	return colors
+}
+
 // RandomImage generates and returns a random avatar image unique to input data
 // in default size (height and width).
 func RandomImage(data []byte) (image.Image, error) {
 	return RandomImageSize(AvatarSize, data)
 }
 
+// ErrAvatarImageTooLarge represents an error that an uploaded image exceeds the dimensions which are safe to decode
+type ErrAvatarImageTooLarge struct {
+	Width  int
+	Height int
+}
+
+// IsErrAvatarImageTooLarge checks if an error is a ErrAvatarImageTooLarge
+func IsErrAvatarImageTooLarge(err error) bool {
+	_, ok := err.(ErrAvatarImageTooLarge)
+	return ok
+}
+
+func (err ErrAvatarImageTooLarge) Error() string {
+	return fmt.Sprintf("image is too large [width: %d, height: %d]", err.Width, err.Height)
+}
+
+// ErrAvatarCropOutOfBounds represents an error that a requested crop region is not within the uploaded image
+type ErrAvatarCropOutOfBounds struct {
+	Crop   image.Rectangle
+	Width  int
+	Height int
+}
+
+// IsErrAvatarCropOutOfBounds checks if an error is a ErrAvatarCropOutOfBounds
+func IsErrAvatarCropOutOfBounds(err error) bool {
+	_, ok := err.(ErrAvatarCropOutOfBounds)
+	return ok
+}
+
+func (err ErrAvatarCropOutOfBounds) Error() string {
+	return fmt.Sprintf("crop region is out of the image bounds [crop: %v, width: %d, height: %d]", err.Crop, err.Width, err.Height)
+}
+
+// ErrAvatarUnsupportedType represents an error that uploaded data is not a raster image an avatar can be decoded from
+type ErrAvatarUnsupportedType struct {
+	ContentType string
+}
+
+// IsErrAvatarUnsupportedType checks if an error is a ErrAvatarUnsupportedType
+func IsErrAvatarUnsupportedType(err error) bool {
+	_, ok := err.(ErrAvatarUnsupportedType)
+	return ok
+}
+
+func (err ErrAvatarUnsupportedType) Error() string {
+	return fmt.Sprintf("unsupported avatar type [content_type: %s]", err.ContentType)
+}
+
+// supportedContentTypes are the raster image formats avatars are decoded from
+var supportedContentTypes = []string{"image/png", "image/jpeg", "image/gif"}
+
+// CheckContentType sniffs the content type of the uploaded data and rejects everything which is not a supported
+// raster image with an ErrAvatarUnsupportedType, in particular SVG images, which can embed scripts.
+func CheckContentType(data []byte) error {
+	contentType := typesniffer.DetectContentType(data).GetMimeType()
+	for _, supported := range supportedContentTypes {
+		if contentType == supported {
+			return nil
+		}
+	}
+	return ErrAvatarUnsupportedType{ContentType: contentType}
+}
+
 // Prepare accepts a byte slice as input, validates it contains an image of an
 // acceptable format, and crops and resizes it appropriately.
+// Images exceeding the configured maximum dimensions are downscaled first,
+// images exceeding the maximum original dimensions or pixel count are rejected without being decoded.
 func Prepare(data []byte) (*image.Image, error) {
+	return prepare(data, nil)
+}
+
+// PrepareWithCrop works like Prepare, but cuts the given region out of the image first instead of
+// cropping its center. The region is given in the pixel coordinates of the uploaded image,
+// a region which is empty or not within the image is rejected with an ErrAvatarCropOutOfBounds.
+func PrepareWithCrop(data []byte, crop image.Rectangle) (*image.Image, error) {
+	return prepare(data, &crop)
+}
+
+func prepare(data []byte, crop *image.Rectangle) (*image.Image, error) {
 	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("DecodeConfig: %w", err)
 	}
-	if imgCfg.Width > setting.Avatar.MaxWidth {
-		return nil, fmt.Errorf("Image width is too large: %d > %d", imgCfg.Width, setting.Avatar.MaxWidth)
-	}
-	if imgCfg.Height > setting.Avatar.MaxHeight {
-		return nil, fmt.Errorf("Image height is too large: %d > %d", imgCfg.Height, setting.Avatar.MaxHeight)
+	if imgCfg.Width > setting.Avatar.MaxOriginalWidth || imgCfg.Height > setting.Avatar.MaxOriginalHeight ||
+		int64(imgCfg.Width)*int64(imgCfg.Height) > int64(setting.Avatar.MaxOriginalPixels) {
+		return nil, ErrAvatarImageTooLarge{Width: imgCfg.Width, Height: imgCfg.Height}
 	}
 
 	img, _, err := image.Decode(bytes.NewReader(data))
@@ -59,14 +172,34 @@ func Prepare(data []byte) (*image.Image, error) {
 		return nil, fmt.Errorf("Decode: %w", err)
 	}
 
-	if imgCfg.Width != imgCfg.Height {
+	width, height := imgCfg.Width, imgCfg.Height
+	if crop != nil {
+		if crop.Empty() || !crop.In(image.Rect(0, 0, width, height)) {
+			return nil, ErrAvatarCropOutOfBounds{Crop: *crop, Width: width, Height: height}
+		}
+		img, err = cutter.Crop(img, cutter.Config{
+			Width:  crop.Dx(),
+			Height: crop.Dy(),
+			Anchor: crop.Min,
+		})
+		if err != nil {
+			return nil, err
+		}
+		width, height = crop.Dx(), crop.Dy()
+	}
+	if width > setting.Avatar.MaxWidth || height > setting.Avatar.MaxHeight {
+		img = resize.Thumbnail(uint(setting.Avatar.MaxWidth), uint(setting.Avatar.MaxHeight), img, resize.Bilinear)
+		width, height = img.Bounds().Dx(), img.Bounds().Dy()
+	}
+
+	if width != height {
 		var newSize, ax, ay int
-		if imgCfg.Width > imgCfg.Height {
-			newSize = imgCfg.Height
-			ax = (imgCfg.Width - imgCfg.Height) / 2
+		if width > height {
+			newSize = height
+			ax = (width - height) / 2
 		} else {
-			newSize = imgCfg.Width
-			ay = (imgCfg.Height - imgCfg.Width) / 2
+			newSize = width
+			ay = (height - width) / 2
 		}
 
 		img, err = cutter.Crop(img, cutter.Config{
@@ -82,3 +215,119 @@ func Prepare(data []byte) (*image.Image, error) {
 	img = resize.Resize(AvatarSize, AvatarSize, img, resize.Bilinear)
 	return &img, nil
 }
+
+// IsAnimated returns true if data contains an animated GIF which is allowed to be stored as it is.
+// Animated avatars bypass Prepare so that their frames are preserved, they must be
+// within the configured file size and frame limits. Animated GIFs exceeding the configured
+// dimensions cannot be downscaled and are reported as not animated, so that Prepare converts them.
+func IsAnimated(data []byte) (bool, error) {
+	if !setting.Avatar.AllowAnimated {
+		return false, nil
+	}
+
+	imgCfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("DecodeConfig: %w", err)
+	}
+	if format != "gif" {
+		return false, nil
+	}
+	if imgCfg.Width > setting.Avatar.MaxWidth || imgCfg.Height > setting.Avatar.MaxHeight {
+		return false, nil
+	}
+	if int64(len(data)) > setting.Avatar.MaxFileSize {
+		return false, ErrAnimatedAvatarTooLarge{Size: len(data)}
+	}
+
+	// the frames are only counted, decoding them could allocate a full image for every frame
+	frames, err := countGIFFrames(bytes.NewReader(data), setting.Avatar.MaxAnimatedFrames)
+	if err != nil {
+		return false, fmt.Errorf("countGIFFrames: %w", err)
+	}
+	if frames > setting.Avatar.MaxAnimatedFrames {
+		return false, ErrAnimatedAvatarTooLarge{Frames: frames, Size: len(data)}
+	}
+	return frames > 1, nil
+}
+
+// countGIFFrames counts the frames of a GIF by walking its blocks without decoding the image data.
+// It stops at the first frame beyond limit, so the result is at most limit+1.
+func countGIFFrames(r io.Reader, limit int) (int, error) {
+	br := bufio.NewReader(r)
+
+	// header and logical screen descriptor
+	header := make([]byte, 13)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return 0, err
+	}
+	if !bytes.HasPrefix(header, []byte("GIF8")) {
+		return 0, errors.New("not a gif")
+	}
+	if err := skipGIFColorTable(br, header[10]); err != nil {
+		return 0, err
+	}
+
+	frames := 0
+	for {
+		introducer, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch introducer {
+		case 0x21: // extension: label and data sub-blocks
+			if _, err := br.ReadByte(); err != nil {
+				return 0, err
+			}
+			if err := skipGIFSubBlocks(br); err != nil {
+				return 0, err
+			}
+		case 0x2c: // image descriptor, local color table, LZW minimum code size and data sub-blocks
+			frames++
+			if frames > limit {
+				return frames, nil
+			}
+			descriptor := make([]byte, 9)
+			if _, err := io.ReadFull(br, descriptor); err != nil {
+				return 0, err
+			}
+			if err := skipGIFColorTable(br, descriptor[8]); err != nil {
+				return 0, err
+			}
+			if _, err := br.ReadByte(); err != nil {
+				return 0, err
+			}
+			if err := skipGIFSubBlocks(br); err != nil {
+				return 0, err
+			}
+		case 0x3b: // trailer
+			return frames, nil
+		default:
+			return 0, fmt.Errorf("unknown gif block: %#x", introducer)
+		}
+	}
+}
+
+// skipGIFColorTable skips the color table announced by the packed fields of a descriptor
+func skipGIFColorTable(br *bufio.Reader, fields byte) error {
+	if fields&0x80 == 0 {
+		return nil
+	}
+	_, err := br.Discard(3 * (1 << (fields&0x07 + 1)))
+	return err
+}
+
+// skipGIFSubBlocks skips data sub-blocks up to and including the terminating empty block
+func skipGIFSubBlocks(br *bufio.Reader) error {
+	for {
+		size, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		if size == 0 {
+			return nil
+		}
+		if _, err := br.Discard(int(size)); err != nil {
+			return err
+		}
+	}
+}
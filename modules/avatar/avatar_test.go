@@ -4,6 +4,11 @@
 package avatar
 
 import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
 	"os"
 	"testing"
 
@@ -25,6 +30,63 @@ func Test_RandomImage(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func Test_RandomImageWithPalette(t *testing.T) {
+	defer func(palette []string) { setting.Avatar.Palette = palette }(setting.Avatar.Palette)
+	setting.Avatar.Palette = []string{"#1f6feb", "#8250df", "#bf3989"}
+	palette := []color.Color{
+		color.RGBA{R: 0x1f, G: 0x6f, B: 0xeb, A: 0xff},
+		color.RGBA{R: 0x82, G: 0x50, B: 0xdf, A: 0xff},
+		color.RGBA{R: 0xbf, G: 0x39, B: 0x89, A: 0xff},
+	}
+
+	encode := func(seed string) []byte {
+		img, err := RandomImageSize(64, []byte(seed))
+		assert.NoError(t, err)
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, img))
+		return buf.Bytes()
+	}
+
+	for _, seed := range []string{"gitea@local", "user2@example.com", "user5@example.com", "org3@example.com"} {
+		img, err := RandomImageSize(64, []byte(seed))
+		assert.NoError(t, err)
+		paletted, ok := img.(*image.Paletted)
+		if assert.True(t, ok) {
+			assert.Equal(t, color.White, paletted.Palette[0])
+			assert.Contains(t, palette, paletted.Palette[1], seed)
+		}
+		assert.Equal(t, encode(seed), encode(seed), seed)
+	}
+
+	// without a palette the built-in colors are used
+	setting.Avatar.Palette = nil
+	img, err := RandomImageSize(64, []byte("gitea@local"))
+	assert.NoError(t, err)
+	assert.NotContains(t, palette, img.(*image.Paletted).Palette[1])
+}
+
+func Test_CheckContentType(t *testing.T) {
+	for _, name := range []string{"testdata/avatar.png", "testdata/avatar.jpeg"} {
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.NoError(t, CheckContentType(data), name)
+	}
+
+	for _, c := range []struct {
+		data        string
+		contentType string
+	}{
+		{`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`, "image/svg+xml"},
+		{`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`, "image/svg+xml"},
+		{"BM\x00\x00", "image/bmp"},
+		{"not an image", "text/plain"},
+	} {
+		err := CheckContentType([]byte(c.data))
+		assert.True(t, IsErrAvatarUnsupportedType(err), c.data)
+		assert.Equal(t, ErrAvatarUnsupportedType{ContentType: c.contentType}, err)
+	}
+}
+
 func Test_PrepareWithPNG(t *testing.T) {
 	setting.Avatar.MaxWidth = 4096
 	setting.Avatar.MaxHeight = 4096
@@ -61,13 +123,170 @@ func Test_PrepareWithInvalidImage(t *testing.T) {
 	assert.EqualError(t, err, "DecodeConfig: image: unknown format")
 }
 
-func Test_PrepareWithInvalidImageSize(t *testing.T) {
+func Test_PrepareWithOversizedImage(t *testing.T) {
 	setting.Avatar.MaxWidth = 5
 	setting.Avatar.MaxHeight = 5
+	defer func() {
+		setting.Avatar.MaxWidth = 4096
+		setting.Avatar.MaxHeight = 3072
+	}()
 
 	data, err := os.ReadFile("testdata/avatar.png")
 	assert.NoError(t, err)
 
+	// oversized images are downscaled instead of being rejected
+	imgPtr, err := Prepare(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 290, (*imgPtr).Bounds().Max.X)
+	assert.Equal(t, 290, (*imgPtr).Bounds().Max.Y)
+
+	// the aspect ratio is kept when downscaling, so the crop stays centered
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 10; x < 30; x++ {
+		for y := 0; y < 20; y++ {
+			img.Set(x, y, color.Black)
+		}
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, img))
+	imgPtr, err = Prepare(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, 290, (*imgPtr).Bounds().Dx())
+	assert.Equal(t, 290, (*imgPtr).Bounds().Dy())
+	r, g, b, _ := (*imgPtr).At(145, 145).RGBA()
+	assert.Zero(t, r+g+b)
+}
+
+func Test_PrepareWithTooLargeImage(t *testing.T) {
+	setting.Avatar.MaxOriginalWidth = 5
+	setting.Avatar.MaxOriginalHeight = 5
+	defer func() {
+		setting.Avatar.MaxOriginalWidth = 8192
+		setting.Avatar.MaxOriginalHeight = 8192
+	}()
+
+	data, err := os.ReadFile("testdata/avatar.png")
+	assert.NoError(t, err)
+
+	_, err = Prepare(data)
+	assert.True(t, IsErrAvatarImageTooLarge(err))
+	assert.EqualError(t, err, "image is too large [width: 10, height: 10]")
+
+	// images within the maximum dimensions are still rejected if they have too many pixels
+	setting.Avatar.MaxOriginalWidth = 8192
+	setting.Avatar.MaxOriginalHeight = 8192
+	setting.Avatar.MaxOriginalPixels = 99
+	defer func() {
+		setting.Avatar.MaxOriginalPixels = 4096 * 3072
+	}()
 	_, err = Prepare(data)
-	assert.EqualError(t, err, "Image width is too large: 10 > 5")
+	assert.True(t, IsErrAvatarImageTooLarge(err))
+
+	setting.Avatar.MaxOriginalPixels = 100
+	_, err = Prepare(data)
+	assert.NoError(t, err)
+}
+
+func Test_PrepareWithCrop(t *testing.T) {
+	// a black square in the top right corner of a white image
+	img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 30; y++ {
+			if x >= 30 && y < 10 {
+				img.Set(x, y, color.Black)
+			} else {
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, img))
+
+	imgPtr, err := PrepareWithCrop(buf.Bytes(), image.Rect(30, 0, 40, 10))
+	assert.NoError(t, err)
+	assert.Equal(t, 290, (*imgPtr).Bounds().Dx())
+	assert.Equal(t, 290, (*imgPtr).Bounds().Dy())
+	r, g, b, _ := (*imgPtr).At(145, 145).RGBA()
+	assert.Zero(t, r+g+b)
+
+	_, err = PrepareWithCrop(buf.Bytes(), image.Rect(35, 0, 45, 10))
+	assert.True(t, IsErrAvatarCropOutOfBounds(err))
+	assert.EqualError(t, err, "crop region is out of the image bounds [crop: (35,0)-(45,10), width: 40, height: 30]")
+}
+
+func encodeGIF(t *testing.T, frames int) []byte {
+	g := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		img := image.NewPaletted(image.Rect(0, 0, 10, 10), color.Palette{color.White, color.Black})
+		img.SetColorIndex(i%10, i%10, 1)
+		g.Image = append(g.Image, img)
+		g.Delay = append(g.Delay, 10)
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, gif.EncodeAll(buf, g))
+	return buf.Bytes()
+}
+
+func Test_countGIFFrames(t *testing.T) {
+	frames, err := countGIFFrames(bytes.NewReader(encodeGIF(t, 3)), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, frames)
+
+	// counting stops at the first frame beyond the limit
+	frames, err = countGIFFrames(bytes.NewReader(encodeGIF(t, 20)), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, frames)
+
+	data := encodeGIF(t, 3)
+	_, err = countGIFFrames(bytes.NewReader(data[:len(data)-10]), 5)
+	assert.Error(t, err)
+
+	_, err = countGIFFrames(bytes.NewReader([]byte("not a gif image")), 5)
+	assert.Error(t, err)
+}
+
+func Test_IsAnimated(t *testing.T) {
+	setting.Avatar.MaxWidth = 4096
+	setting.Avatar.MaxHeight = 4096
+	setting.Avatar.MaxFileSize = 1048576
+	setting.Avatar.MaxAnimatedFrames = 5
+	setting.Avatar.AllowAnimated = true
+	defer func() {
+		setting.Avatar.AllowAnimated = false
+	}()
+
+	animated, err := IsAnimated(encodeGIF(t, 3))
+	assert.NoError(t, err)
+	assert.True(t, animated)
+
+	// a single frame gif is processed like any other image
+	animated, err = IsAnimated(encodeGIF(t, 1))
+	assert.NoError(t, err)
+	assert.False(t, animated)
+
+	data, err := os.ReadFile("testdata/avatar.png")
+	assert.NoError(t, err)
+	animated, err = IsAnimated(data)
+	assert.NoError(t, err)
+	assert.False(t, animated)
+
+	_, err = IsAnimated(encodeGIF(t, 6))
+	assert.True(t, IsErrAnimatedAvatarTooLarge(err))
+
+	setting.Avatar.MaxFileSize = 10
+	_, err = IsAnimated(encodeGIF(t, 3))
+	assert.True(t, IsErrAnimatedAvatarTooLarge(err))
+	setting.Avatar.MaxFileSize = 1048576
+
+	// animated gifs exceeding the dimensions are converted like a static image
+	setting.Avatar.MaxWidth = 5
+	animated, err = IsAnimated(encodeGIF(t, 3))
+	assert.NoError(t, err)
+	assert.False(t, animated)
+	setting.Avatar.MaxWidth = 4096
+
+	setting.Avatar.AllowAnimated = false
+	animated, err = IsAnimated(encodeGIF(t, 3))
+	assert.NoError(t, err)
+	assert.False(t, animated)
 }
@@ -33,6 +33,26 @@ func GetQueryBeforeSince(ctx *Context) (before, since int64, err error) {
 	return before, since, nil
 }
 
+// GetQuerySinceUntil return parsed time from URL query's since and until, a missing value results in a zero time
+func GetQuerySinceUntil(ctx *Context) (since, until time.Time, err error) {
+	for _, arg := range []struct {
+		name  string
+		value *time.Time
+	}{{"since", &since}, {"until", &until}} {
+		value, err := prepareQueryArg(ctx, arg.name)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if len(value) == 0 {
+			continue
+		}
+		if *arg.value, err = time.Parse(time.RFC3339, value); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	return since, until, nil
+}
+
 // parseTime parse time and return unix timestamp
 func parseTime(value string) (int64, error) {
 	if len(value) != 0 {
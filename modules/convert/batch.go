@@ -0,0 +1,31 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+)
+
+// batchLoad loads the beans with the given IDs in one query and maps them by their ID, so that converting
+// a list does not query every association on its own. Duplicate IDs are looked up once, beans which
+// do not exist are not contained in the map. T is a pointer to a model, e.g. *user_model.User.
+func batchLoad[T any](ctx context.Context, ids []int64, idOf func(T) int64) (map[int64]T, error) {
+	uniqueIDs := container.SetOf(ids...).Values()
+	result := make(map[int64]T, len(uniqueIDs))
+	if len(uniqueIDs) == 0 {
+		return result, nil
+	}
+
+	beans := make([]T, 0, len(uniqueIDs))
+	if err := db.GetEngine(ctx).In("id", uniqueIDs).Find(&beans); err != nil {
+		return nil, err
+	}
+	for _, bean := range beans {
+		result[idOf(bean)] = bean
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchLoad(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	userID := func(u *user_model.User) int64 { return u.ID }
+
+	// duplicate IDs are loaded once, missing ones are left out
+	users, err := batchLoad(db.DefaultContext, []int64{2, 4, 2, 9999}, userID)
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, "user2", users[2].Name)
+		assert.Equal(t, "user4", users[4].Name)
+	}
+	assert.NotContains(t, users, int64(9999))
+
+	users, err = batchLoad(db.DefaultContext, nil, userID)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestToTrackedTimeList_Batched(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	tl := issues_model.TrackedTimeList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: 2}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: 3}),
+	}
+	for _, tt := range tl {
+		tt.Issue = issue
+	}
+
+	apiTimes, _ := ToTrackedTimeList(db.DefaultContext, tl)
+	if assert.Len(t, apiTimes, 2) {
+		// the users are loaded and the issue is converted once
+		assert.Equal(t, "user2", apiTimes[0].UserName)
+		assert.Equal(t, "user2", apiTimes[1].UserName)
+		assert.Same(t, apiTimes[0].Issue, apiTimes[1].Issue)
+		assert.Equal(t, "PT1H1M1S", apiTimes[0].ISODuration)
+		assert.Equal(t, "PT1S", apiTimes[1].ISODuration)
+	}
+}
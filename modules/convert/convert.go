@@ -170,6 +170,7 @@ func ToVerification(c *git.Commit) *api.PayloadCommitVerification {
 		Reason:   verif.Reason,
 	}
 	if c.Signature != nil {
+		commitVerification.Signed = true
 		commitVerification.Signature = c.Signature.Signature
 		commitVerification.Payload = c.Signature.Payload
 	}
@@ -268,6 +269,9 @@ func ToHook(repoLink string, w *webhook.Webhook) (*api.Hook, error) {
 		Config:              config,
 		Events:              w.EventsArray(),
 		AuthorizationHeader: authorizationHeader,
+		IssueBodyMode:       w.IssuePayload.BodyMode.Name(),
+		IssueBodyMaxLength:  w.IssuePayload.BodyMaxLength,
+		StripAssigneeEmails: w.IssuePayload.StripAssigneeEmails,
 		Updated:             w.UpdatedUnix.AsTime(),
 		Created:             w.CreatedUnix.AsTime(),
 	}, nil
@@ -340,6 +344,7 @@ func ToTeams(teams []*organization.Team, loadOrgs bool) ([]*api.Team, error) {
 			Description:             teams[i].Description,
 			IncludesAllRepositories: teams[i].IncludesAllRepositories,
 			CanCreateOrgRepo:        teams[i].CanCreateOrgRepo,
+			IsPublic:                teams[i].IsPublic,
 			Permission:              teams[i].AccessMode.String(),
 			Units:                   teams[i].GetUnitNames(),
 			UnitsMap:                teams[i].GetUnitsMap(),
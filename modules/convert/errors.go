@@ -0,0 +1,31 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLoadFailed represents a failure to load the named field of an object while converting it to API format.
+// The underlying cause is kept, so callers can tell e.g. a missing repository from a database error.
+type ErrLoadFailed struct {
+	Field string
+	Err   error
+}
+
+// IsErrLoadFailed checks if an error is or wraps a ErrLoadFailed.
+func IsErrLoadFailed(err error) bool {
+	var errLoadFailed ErrLoadFailed
+	return errors.As(err, &errLoadFailed)
+}
+
+func (err ErrLoadFailed) Error() string {
+	return fmt.Sprintf("failed to load %s: %v", err.Field, err.Err)
+}
+
+// Unwrap returns the underlying cause of the load failure
+func (err ErrLoadFailed) Unwrap() error {
+	return err.Err
+}
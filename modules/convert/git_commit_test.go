@@ -4,16 +4,25 @@
 package convert
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
 	"testing"
 	"time"
 
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 
+	"github.com/42wim/sshsig"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestToCommitMeta(t *testing.T) {
@@ -39,3 +48,62 @@ func TestToCommitMeta(t *testing.T) {
 		Created: time.Unix(0, 0),
 	}, commitMeta)
 }
+
+// sshSignPayload signs the payload with a new SSH key and returns the signature and the public key
+func sshSignPayload(t *testing.T, payload string) (string, string) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	sig, err := sshsig.Sign(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), strings.NewReader(payload), "git")
+	assert.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	assert.NoError(t, err)
+	return string(sig), string(ssh.MarshalAuthorizedKey(sshPub))
+}
+
+func TestToVerification(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	committer := &git.Signature{Name: "user2", Email: "user2@example.com", When: time.Unix(0, 0)}
+	payload := "tree 0000000000000000000000000000000000000000\n\nsigned commit\n"
+
+	// unsigned
+	verification := ToVerification(&git.Commit{Committer: committer})
+	assert.False(t, verification.Signed)
+	assert.False(t, verification.Verified)
+	assert.Nil(t, verification.Signer)
+	assert.Equal(t, "gpg.error.not_signed_commit", verification.Reason)
+
+	// signed by a key which is not known
+	sig, _ := sshSignPayload(t, payload)
+	verification = ToVerification(&git.Commit{
+		Committer: committer,
+		Signature: &git.CommitGPGSignature{Signature: sig, Payload: payload},
+	})
+	assert.True(t, verification.Signed)
+	assert.False(t, verification.Verified)
+	assert.Nil(t, verification.Signer)
+	assert.Equal(t, asymkey_model.NoKeyFound, verification.Reason)
+	assert.Equal(t, sig, verification.Signature)
+
+	// signed by a verified key of the committer
+	sig, content := sshSignPayload(t, payload)
+	fingerprint, err := asymkey_model.CalcFingerprint(content)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Insert(db.DefaultContext, &asymkey_model.PublicKey{
+		OwnerID:     2,
+		Name:        "signing-key",
+		Fingerprint: fingerprint,
+		Content:     content,
+		Verified:    true,
+	}))
+	verification = ToVerification(&git.Commit{
+		Committer: committer,
+		Signature: &git.CommitGPGSignature{Signature: sig, Payload: payload},
+	})
+	assert.True(t, verification.Signed)
+	assert.True(t, verification.Verified)
+	assert.Equal(t, &api.PayloadUser{Name: "user2", Email: "user2@example.com"}, verification.Signer)
+	assert.Equal(t, "user2 / "+fingerprint, verification.Reason)
+}
@@ -6,34 +6,83 @@ package convert
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	project_model "code.gitea.io/gitea/models/project"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/emoji"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/references"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/templates/vars"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+
+	"xorm.io/builder"
 )
 
 // ToAPIIssue converts an Issue to API format
 // it assumes some fields assigned with values:
 // Required - Poster, Labels,
-// Optional - Milestone, Assignee, PullRequest
+// Optional - Milestone, Assignee, PullRequest, Project, ProjectBoard
 func ToAPIIssue(ctx context.Context, issue *issues_model.Issue) *api.Issue {
-	if err := issue.LoadLabels(ctx); err != nil {
+	apiIssue, err := ToAPIIssueWithError(ctx, issue)
+	if err != nil {
+		log.Error("ToAPIIssue[%d]: %v", issue.ID, err)
 		return &api.Issue{}
 	}
+	return apiIssue
+}
+
+// ToAPIIssueWithError converts an Issue to API format like ToAPIIssue, but returns an ErrLoadFailed
+// instead of an empty issue when loading one of its labels, poster, repository, milestone, assignees or
+// pull request fails. The other fields are left unset if they cannot be loaded, like ToAPIIssueList does.
+func ToAPIIssueWithError(ctx context.Context, issue *issues_model.Issue) (*api.Issue, error) {
+	return toAPIIssueWithError(ctx, issue, nil)
+}
+
+// toAPIIssueWithError converts an Issue to API format like ToAPIIssueWithError, users are converted as seen by the viewer
+func toAPIIssueWithError(ctx context.Context, issue *issues_model.Issue, viewer *user_model.User) (*api.Issue, error) {
+	apiIssue, err := toAPIIssue(ctx, issue)
+	if err != nil {
+		return nil, err
+	}
+	loadIssueDetails(ctx, issues_model.IssueList{issue}, []*api.Issue{apiIssue}, viewer)
+	return apiIssue, nil
+}
+
+func toAPIIssue(ctx context.Context, issue *issues_model.Issue) (*api.Issue, error) {
+	if err := issue.LoadLabels(ctx); err != nil {
+		return nil, ErrLoadFailed{Field: "labels", Err: err}
+	}
 	if err := issue.LoadPoster(ctx); err != nil {
-		return &api.Issue{}
+		return nil, ErrLoadFailed{Field: "poster", Err: err}
 	}
 	if err := issue.LoadRepo(ctx); err != nil {
-		return &api.Issue{}
+		return nil, ErrLoadFailed{Field: "repo", Err: err}
 	}
 	if err := issue.Repo.GetOwner(ctx); err != nil {
-		return &api.Issue{}
+		return nil, ErrLoadFailed{Field: "owner", Err: err}
 	}
 
 	apiIssue := &api.Issue{
@@ -41,124 +90,1178 @@ func ToAPIIssue(ctx context.Context, issue *issues_model.Issue) *api.Issue {
 		URL:      issue.APIURL(),
 		HTMLURL:  issue.HTMLURL(),
 		Index:    issue.Index,
-		Poster:   ToUser(issue.Poster, nil),
+		Poster:   ToPoster(issue.Poster, nil),
 		Title:    issue.Title,
 		Body:     issue.Content,
 		Ref:      issue.Ref,
-		Labels:   ToLabelList(issue.Labels, issue.Repo, issue.Repo.Owner),
+		Labels:   toLabelList(issue.Labels, issue.Repo, issue.Repo.Owner),
 		State:    issue.State(),
 		IsLocked: issue.IsLocked,
 		Comments: issue.NumComments,
 		Created:  issue.CreatedUnix.AsTime(),
 		Updated:  issue.UpdatedUnix.AsTime(),
 	}
+	if issue.IsLocked {
+		apiIssue.LockReason = issue.LockReason
+	}
+	apiIssue.Template = issue.Template
+	// migrated issues report their original author next to the ghost poster, like the web UI does
+	apiIssue.OriginalAuthor = issue.OriginalAuthor
+	apiIssue.OriginalAuthorID = issue.OriginalAuthorID
+
+	apiIssue.Repo = &api.RepositoryMeta{
+		ID:       issue.Repo.ID,
+		Name:     issue.Repo.Name,
+		Owner:    issue.Repo.OwnerName,
+		FullName: issue.Repo.FullName(),
+	}
+
+	if issue.ClosedUnix != 0 {
+		apiIssue.Closed = issue.ClosedUnix.AsTimePtr()
+	}
+	if issue.IsClosed {
+		// issues created as closed, e.g. by repository migrations, carry no reason
+		apiIssue.ClosedReason = string(issues_model.IssueClosedReasonCompleted)
+		if issue.ClosedReason != "" {
+			apiIssue.ClosedReason = string(issue.ClosedReason)
+		}
+	}
+	apiIssue.AgeSeconds = issueAgeSeconds(issue)
+
+	if err := issue.LoadMilestone(ctx); err != nil {
+		return nil, ErrLoadFailed{Field: "milestone", Err: err}
+	}
+	if issue.Milestone != nil {
+		apiIssue.Milestone = ToAPIMilestone(issue.Milestone)
+	}
+
+	if issue.Project != nil && issue.Project.ID > 0 {
+		apiIssue.Project = &api.ProjectMeta{
+			ID:    issue.Project.ID,
+			Title: issue.Project.Title,
+		}
+		if issue.ProjectBoard != nil {
+			apiIssue.ProjectColumn = &api.ProjectColumnMeta{
+				ID:    issue.ProjectBoard.ID,
+				Title: issue.ProjectBoard.Title,
+			}
+		}
+	}
+
+	if err := issue.LoadAssignees(ctx); err != nil {
+		return nil, ErrLoadFailed{Field: "assignees", Err: err}
+	}
+	if len(issue.Assignees) > 0 {
+		for _, assignee := range issue.Assignees {
+			apiIssue.Assignees = append(apiIssue.Assignees, ToUser(assignee, nil))
+		}
+		apiIssue.Assignee = ToUser(issue.Assignees[0], nil) // For compatibility, we're keeping the first assignee as `apiIssue.Assignee`
+	}
+	if issue.IsPull {
+		if err := issue.LoadPullRequest(ctx); err != nil {
+			return nil, ErrLoadFailed{Field: "pull_request", Err: err}
+		}
+		apiIssue.PullRequest = &api.PullRequestMeta{
+			HasMerged: issue.PullRequest.HasMerged,
+		}
+		if issue.PullRequest.HasMerged {
+			apiIssue.PullRequest.Merged = issue.PullRequest.MergedUnix.AsTimePtr()
+		}
+		apiIssue.PullRequest.WorkInProgressPrefix = issue.PullRequest.GetWorkInProgressPrefix(ctx)
+		apiIssue.PullRequest.IsWorkInProgress = apiIssue.PullRequest.WorkInProgressPrefix != ""
+		apiIssue.PullRequest.Mergeable = cachedMergeable(issue.PullRequest)
+		setCachedDiffStats(apiIssue.PullRequest, issue.PullRequest)
+		apiIssue.PullRequest.NeedsRebase = cachedNeedsRebase(issue.PullRequest)
+	}
+	if issue.DeadlineUnix != 0 {
+		apiIssue.Deadline = issue.DeadlineUnix.AsTimePtr()
+	}
+
+	if !issue.IsPull {
+		externalURL, err := externalIssueURL(ctx, issue)
+		if err != nil {
+			return nil, ErrLoadFailed{Field: "external_url", Err: err}
+		}
+		apiIssue.ExternalURL = externalURL
+	}
+
+	return apiIssue, nil
+}
+
+// externalIssueURL returns the URL of the issue in the external tracker of its repository, like the issue page redirects to.
+// It is empty if the repository uses the internal tracker or if the tracker does not use numeric issue indexes.
+func externalIssueURL(ctx context.Context, issue *issues_model.Issue) (string, error) {
+	extUnit, err := issue.Repo.GetUnitCtx(ctx, unit.TypeExternalTracker)
+	if repo_model.IsErrUnitTypeNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	config := extUnit.ExternalTrackerConfig()
+	if config.ExternalTrackerStyle != markup.IssueNameStyleNumeric && config.ExternalTrackerStyle != "" {
+		return "", nil
+	}
+	issueURL, err := vars.Expand(config.ExternalTrackerFormat, map[string]string{
+		"user":  issue.Repo.OwnerName,
+		"repo":  issue.Repo.Name,
+		"index": strconv.FormatInt(issue.Index, 10),
+	})
+	if err != nil {
+		// a broken format must not break the conversion of the issue
+		log.Error("unable to expand the external tracker format of repository %d: %v", issue.Repo.ID, err)
+		return "", nil
+	}
+	return issueURL, nil
+}
+
+// loadEstimates sets the estimates of the issues of repositories with time tracking together with the time
+// tracked on them and whether it exceeds the estimate. The tracked times of all issues are summed up at once.
+func loadEstimates(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	timetrackerEnabled := make(map[int64]bool)
+	issueIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].ID == 0 || issue.Estimate <= 0 {
+			continue
+		}
+		enabled, ok := timetrackerEnabled[issue.RepoID]
+		if !ok {
+			enabled = issue.Repo.IsTimetrackerEnabledCtx(ctx)
+			timetrackerEnabled[issue.RepoID] = enabled
+		}
+		if enabled {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	trackedSeconds, err := issues_model.GetTrackedSecondsByIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return err
+	}
+	for i, issue := range il {
+		if apiIssues[i].ID == 0 || issue.Estimate <= 0 || !timetrackerEnabled[issue.RepoID] {
+			continue
+		}
+		estimate := issue.Estimate
+		spent := trackedSeconds[issue.ID]
+		overEstimate := spent > estimate
+		apiIssues[i].Estimate = &estimate
+		apiIssues[i].TimeSpent = &spent
+		apiIssues[i].OverEstimate = &overEstimate
+	}
+	return nil
+}
+
+// loadDuplicateOf sets the issues the given ones are duplicates of. Issues in other repositories are only
+// referenced if the viewer may read them, issues which have been deleted are not referenced.
+// The canonical issues and their repositories are loaded at once.
+func loadDuplicateOf(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue, viewer *user_model.User) error {
+	canonicalIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].ID != 0 && issue.DuplicateOfID != 0 {
+			canonicalIDs = append(canonicalIDs, issue.DuplicateOfID)
+		}
+	}
+	if len(canonicalIDs) == 0 {
+		return nil
+	}
+
+	canonicals, err := batchLoad(ctx, canonicalIDs, func(issue *issues_model.Issue) int64 { return issue.ID })
+	if err != nil {
+		return err
+	}
+	repoIDs := make([]int64, 0, len(canonicals))
+	for _, canonical := range canonicals {
+		repoIDs = append(repoIDs, canonical.RepoID)
+	}
+	repos, err := batchLoad(ctx, repoIDs, func(repo *repo_model.Repository) int64 { return repo.ID })
+	if err != nil {
+		return err
+	}
+
+	perms := make(map[int64]access_model.Permission, len(repos))
+	for i, issue := range il {
+		if apiIssues[i].ID == 0 || issue.DuplicateOfID == 0 {
+			continue
+		}
+		canonical, ok := canonicals[issue.DuplicateOfID]
+		if !ok {
+			continue
+		}
+		if canonical.RepoID != issue.RepoID {
+			repo, ok := repos[canonical.RepoID]
+			if !ok {
+				continue
+			}
+			perm, ok := perms[repo.ID]
+			if !ok {
+				if perm, err = access_model.GetUserRepoPermission(ctx, repo, viewer); err != nil {
+					return err
+				}
+				perms[repo.ID] = perm
+			}
+			if !perm.CanReadIssuesOrPulls(canonical.IsPull) {
+				continue
+			}
+			apiIssues[i].DuplicateOfRepo = &api.RepositoryMeta{
+				ID:       repo.ID,
+				Name:     repo.Name,
+				Owner:    repo.OwnerName,
+				FullName: repo.FullName(),
+			}
+		}
+		index := canonical.Index
+		apiIssues[i].DuplicateOf = &index
+	}
+	return nil
+}
+
+// cachedMergeable returns whether the pull request has no conflicts according to the status stored by its
+// last check, nil while it is being checked or when the status does not tell. No git operation is run.
+func cachedMergeable(pr *issues_model.PullRequest) *bool {
+	if pr.HasMerged {
+		return nil
+	}
+	var mergeable bool
+	switch pr.Status {
+	case issues_model.PullRequestStatusMergeable, issues_model.PullRequestStatusEmpty, issues_model.PullRequestStatusAncestor:
+		mergeable = true
+	case issues_model.PullRequestStatusConflict:
+		mergeable = false
+	default:
+		return nil
+	}
+	return &mergeable
+}
+
+// setCachedDiffStats sets the diff stats stored by the last check of the pull request,
+// they are left empty while it is being checked or if they have not been stored. No git operation is run.
+func setCachedDiffStats(meta *api.PullRequestMeta, pr *issues_model.PullRequest) {
+	if pr.DiffStatsCommitID == "" || pr.Status == issues_model.PullRequestStatusChecking {
+		return
+	}
+	changedFiles, additions, deletions := pr.NumChangedFiles, pr.NumAdditions, pr.NumDeletions
+	meta.ChangedFiles = &changedFiles
+	meta.Additions = &additions
+	meta.Deletions = &deletions
+}
+
+// cachedBaseChangedSinceReview returns whether a check of the pull request saw the head of the base branch change
+// after the given time of its latest review, nil if the pull request is not loaded or the base has not been stored.
+func cachedBaseChangedSinceReview(pr *issues_model.PullRequest, lastReviewed timeutil.TimeStamp) *bool {
+	if pr == nil || pr.HasMerged || pr.BaseCommitID == "" {
+		return nil
+	}
+	changed := lastReviewed != 0 && pr.BaseChangedUnix > lastReviewed
+	return &changed
+}
+
+// cachedNeedsRebase returns whether the head of the base branch seen by the last check of the pull request
+// is not its merge base, nil while it is being checked or if the head has not been stored. No git operation is run.
+func cachedNeedsRebase(pr *issues_model.PullRequest) *bool {
+	if pr.HasMerged || pr.BaseCommitID == "" || pr.MergeBase == "" || pr.Status == issues_model.PullRequestStatusChecking {
+		return nil
+	}
+	needsRebase := pr.BaseCommitID != pr.MergeBase
+	return &needsRebase
+}
+
+// loadAuthorAssociations sets the relationship of the posters to the repositories of the issues.
+// The strongest association wins: OWNER, MEMBER, COLLABORATOR, CONTRIBUTOR, then NONE.
+// For repositories owned by an organization, members of its owners team are OWNER
+// and members of any other team are MEMBER. The memberships, collaborations and merged
+// pull requests of all posters are loaded at once.
+func loadAuthorAssociations(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	orgIDs := make(container.Set[int64])
+	repoIDs := make(container.Set[int64])
+	posterIDs := make(container.Set[int64])
+	pending := make([]int, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].ID == 0 {
+			continue
+		}
+		switch {
+		case issue.Poster.IsGhost() || issue.OriginalAuthorID != 0:
+			apiIssues[i].AuthorAssociation = api.AuthorAssociationNone
+		case issue.Repo.OwnerID == issue.PosterID:
+			apiIssues[i].AuthorAssociation = api.AuthorAssociationOwner
+		default:
+			if issue.Repo.Owner.IsOrganization() {
+				orgIDs.Add(issue.Repo.OwnerID)
+			}
+			repoIDs.Add(issue.RepoID)
+			posterIDs.Add(issue.PosterID)
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	memberships, err := organization.GetOrgMembershipsByUserIDs(ctx, orgIDs.Values(), posterIDs.Values())
+	if err != nil {
+		return err
+	}
+	collaborators, err := repo_model.GetCollaboratorIDsByRepoIDs(ctx, repoIDs.Values(), posterIDs.Values())
+	if err != nil {
+		return err
+	}
+	contributors, err := issues_model.GetMergedPullRequestPosterIDs(ctx, repoIDs.Values(), posterIDs.Values())
+	if err != nil {
+		return err
+	}
+
+	for _, i := range pending {
+		issue := il[i]
+		association := api.AuthorAssociationNone
+		if isOwner, ok := memberships[issue.Repo.OwnerID][issue.PosterID]; ok {
+			association = api.AuthorAssociationMember
+			if isOwner {
+				association = api.AuthorAssociationOwner
+			}
+		} else if collaborators[issue.RepoID].Contains(issue.PosterID) {
+			association = api.AuthorAssociationCollaborator
+		} else if contributors[issue.RepoID].Contains(issue.PosterID) {
+			association = api.AuthorAssociationContributor
+		}
+		apiIssues[i].AuthorAssociation = association
+	}
+	return nil
+}
+
+// ToAPIIssueMinimal converts an Issue to a lightweight API format only containing the id, index, title,
+// state and poster of the issue, no other associations are loaded
+func ToAPIIssueMinimal(ctx context.Context, issue *issues_model.Issue) *api.Issue {
+	if err := issue.LoadPoster(ctx); err != nil {
+		return &api.Issue{}
+	}
+	return toAPIIssueMinimal(issue)
+}
+
+// ToAPIIssueMinimalList converts an IssueList to the lightweight API format of ToAPIIssueMinimal,
+// the posters of all issues are loaded with a single query
+func ToAPIIssueMinimalList(ctx context.Context, il issues_model.IssueList) ([]*api.Issue, error) {
+	if err := il.LoadPosters(ctx); err != nil {
+		return nil, ErrLoadFailed{Field: "poster", Err: err}
+	}
+	result := make([]*api.Issue, len(il))
+	for i, issue := range il {
+		// ghost and migrated posters are not loaded by the list
+		if issue.Poster == nil {
+			if err := issue.LoadPoster(ctx); err != nil {
+				return nil, ErrLoadFailed{Field: "poster", Err: err}
+			}
+		}
+		result[i] = toAPIIssueMinimal(issue)
+	}
+	return result, nil
+}
+
+func toAPIIssueMinimal(issue *issues_model.Issue) *api.Issue {
+	return &api.Issue{
+		ID:     issue.ID,
+		Index:  issue.Index,
+		Title:  issue.Title,
+		State:  issue.State(),
+		Poster: ToPoster(issue.Poster, nil),
+	}
+}
+
+// ToAPIIssueList converts an IssueList to API format
+func ToAPIIssueList(ctx context.Context, il issues_model.IssueList, doer *user_model.User) []*api.Issue {
+	// load the pull requests at once instead of one by one during the conversion
+	if err := il.LoadPullRequests(ctx); err != nil {
+		log.Error("LoadPullRequests: %v", err)
+	}
+	result := make([]*api.Issue, len(il))
+	for i := range il {
+		apiIssue, err := toAPIIssue(ctx, il[i])
+		if err != nil {
+			log.Error("toAPIIssue[%d]: %v", il[i].ID, err)
+			apiIssue = &api.Issue{}
+		}
+		result[i] = apiIssue
+	}
+	if err := loadReferencedBy(ctx, il, result, doer); err != nil {
+		log.Error("loadReferencedBy: %v", err)
+	}
+	loadIssueDetails(ctx, il, result, doer)
+	return result
+}
+
+// loadIssueDetails loads the fields of the API issues which are not taken from the issue itself.
+// These are optional, so a field which cannot be loaded is only logged and left unset.
+func loadIssueDetails(ctx context.Context, il issues_model.IssueList, result []*api.Issue, viewer *user_model.User) {
+	if err := loadFirstTimeContributors(ctx, il, result); err != nil {
+		log.Error("loadFirstTimeContributors: %v", err)
+	}
+	if err := loadReviewStates(ctx, il, result); err != nil {
+		log.Error("loadReviewStates: %v", err)
+	}
+	if err := loadLockers(ctx, il, result); err != nil {
+		log.Error("loadLockers: %v", err)
+	}
+	if err := loadLastResponses(ctx, il, result); err != nil {
+		log.Error("loadLastResponses: %v", err)
+	}
+	if err := loadParticipants(ctx, il, result, viewer); err != nil {
+		log.Error("loadParticipants: %v", err)
+	}
+	if err := loadWatchersCounts(ctx, il, result); err != nil {
+		log.Error("loadWatchersCounts: %v", err)
+	}
+	if err := loadLastCommitStatuses(ctx, il, result); err != nil {
+		log.Error("loadLastCommitStatuses: %v", err)
+	}
+	if err := loadLabelBoardColumnsOfIssues(ctx, il, result); err != nil {
+		log.Error("loadLabelBoardColumnsOfIssues: %v", err)
+	}
+	if err := loadLastCommented(ctx, il, result); err != nil {
+		log.Error("loadLastCommented: %v", err)
+	}
+	if err := loadAuthorAssociations(ctx, il, result); err != nil {
+		log.Error("loadAuthorAssociations: %v", err)
+	}
+	if err := loadDeadlineSetters(ctx, il, result); err != nil {
+		log.Error("loadDeadlineSetters: %v", err)
+	}
+	if err := loadDuplicateOf(ctx, il, result, viewer); err != nil {
+		log.Error("loadDuplicateOf: %v", err)
+	}
+	if err := loadEstimates(ctx, il, result); err != nil {
+		log.Error("loadEstimates: %v", err)
+	}
+}
+
+// issueStreamBatchSize is the number of issues ToAPIIssueListBatched converts at once
+const issueStreamBatchSize = 50
+
+// ToAPIIssueListBatched converts the issues like ToAPIIssueList and passes them to fn in batches, in their order.
+// The related data of a batch is still loaded with few queries but only the API representation of one batch
+// is held in memory at a time. The conversion stops at the first error returned by fn.
+func ToAPIIssueListBatched(ctx context.Context, il issues_model.IssueList, doer *user_model.User, fn func(apiIssues []*api.Issue) error) error {
+	for start := 0; start < len(il); start += issueStreamBatchSize {
+		end := start + issueStreamBatchSize
+		if end > len(il) {
+			end = len(il)
+		}
+		if err := fn(ToAPIIssueList(ctx, il[start:end], doer)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamAPIIssueList writes the issues as a JSON array to w, the output equals the JSON encoding of ToAPIIssueList.
+// The issues are converted with ToAPIIssueListBatched.
+func StreamAPIIssueList(ctx context.Context, w io.Writer, il issues_model.IssueList, doer *user_model.User) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	if err := ToAPIIssueListBatched(ctx, il, doer, func(apiIssues []*api.Issue) error {
+		for _, apiIssue := range apiIssues {
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			bs, err := json.Marshal(apiIssue)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(bs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// loadParticipants sets the participants of the issues: the poster, the users who commented and the assignees.
+// The comment authors of all issues are loaded at once, users excluded by the settings are left out.
+func loadParticipants(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue, doer *user_model.User) error {
+	issueIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].ID != 0 {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	participantIDs, err := issues_model.GetParticipantsIDsByIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return err
+	}
+	userIDs := make(container.Set[int64])
+	for _, ids := range participantIDs {
+		userIDs.AddMultiple(ids...)
+	}
+	userMap, err := batchLoad(ctx, userIDs.Values(), func(u *user_model.User) int64 { return u.ID })
+	if err != nil {
+		return err
+	}
+
+	for i, issue := range il {
+		if apiIssues[i].ID == 0 {
+			continue
+		}
+		participants := make([]*user_model.User, 0, len(participantIDs[issue.ID])+len(issue.Assignees)+1)
+		seen := make(container.Set[int64])
+		add := func(u *user_model.User) {
+			if u == nil || u.ID <= 0 || isExcludedFromParticipants(u) || !seen.Add(u.ID) {
+				return
+			}
+			participants = append(participants, u)
+		}
+		add(issue.Poster)
+		for _, id := range participantIDs[issue.ID] {
+			// comment authors which have been deleted are not listed
+			add(userMap[id])
+		}
+		for _, assignee := range issue.Assignees {
+			add(assignee)
+		}
+		apiIssues[i].Participants = ToUsers(doer, participants)
+	}
+	return nil
+}
+
+func isExcludedFromParticipants(u *user_model.User) bool {
+	for _, name := range setting.Repository.Issue.ParticipantsExcludedUsers {
+		if strings.EqualFold(name, u.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadWatchersCounts sets the number of users explicitly watching the issues
+func loadWatchersCounts(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	issueIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].ID != 0 {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+	}
+	counts, err := issues_model.CountIssueWatchersByIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return err
+	}
+	for i, issue := range il {
+		if apiIssues[i].ID != 0 {
+			apiIssues[i].WatchersCount = int(counts[issue.ID])
+		}
+	}
+	return nil
+}
+
+// loadLastCommitStatuses sets the combined latest commit statuses of the head commits seen by the last checks
+// of the pull requests. The statuses of all pull requests and their creators are loaded at once, no git operation is run.
+func loadLastCommitStatuses(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	commits := make([]git_model.RepoCommit, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].PullRequest != nil && issue.PullRequest != nil && issue.PullRequest.DiffStatsCommitID != "" {
+			commits = append(commits, git_model.RepoCommit{RepoID: issue.PullRequest.BaseRepoID, SHA: issue.PullRequest.DiffStatsCommitID})
+		}
+	}
+	statusesMap, err := git_model.GetLatestCommitStatusesByCommits(ctx, commits)
+	if err != nil {
+		return err
+	}
+	if len(statusesMap) == 0 {
+		return nil
+	}
+
+	repoIDs := make([]int64, 0, len(statusesMap))
+	creatorIDs := make([]int64, 0, len(statusesMap))
+	for commit, statuses := range statusesMap {
+		repoIDs = append(repoIDs, commit.RepoID)
+		for _, status := range statuses {
+			creatorIDs = append(creatorIDs, status.CreatorID)
+		}
+	}
+	repos, err := batchLoad(ctx, repoIDs, func(repo *repo_model.Repository) int64 { return repo.ID })
+	if err != nil {
+		return err
+	}
+	creators, err := batchLoad(ctx, creatorIDs, func(u *user_model.User) int64 { return u.ID })
+	if err != nil {
+		return err
+	}
+	for _, statuses := range statusesMap {
+		for _, status := range statuses {
+			status.Repo = repos[status.RepoID]
+			status.Creator = creators[status.CreatorID]
+		}
+	}
+
+	for i, issue := range il {
+		if apiIssues[i].PullRequest == nil || issue.PullRequest == nil || issue.PullRequest.DiffStatsCommitID == "" {
+			continue
+		}
+		statuses := statusesMap[git_model.RepoCommit{RepoID: issue.PullRequest.BaseRepoID, SHA: issue.PullRequest.DiffStatsCommitID}]
+		apiIssues[i].PullRequest.LastCommitStatus = ToCombinedStatus(statuses, nil)
+	}
+	return nil
+}
+
+// loadLockers sets the users who locked the locked issues, the lock comments of all issues are loaded at once
+func loadLockers(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	issueIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if issue.IsLocked && apiIssues[i].ID != 0 {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	lockComments, err := issues_model.GetLockCommentsByIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return err
+	}
+	for i, issue := range il {
+		if comment, ok := lockComments[issue.ID]; ok && issue.IsLocked && apiIssues[i].ID != 0 {
+			apiIssues[i].LockedBy = ToPoster(comment.Poster, nil)
+		}
+	}
+	return nil
+}
+
+// loadLastCommented sets the time of the latest comment or review of the issues,
+// the latest comments of all issues are loaded at once
+func loadLastCommented(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	issueIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].ID != 0 {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	lastComments, err := issues_model.GetLatestCommentsByType(ctx, issueIDs, issues_model.CommentTypeComment, issues_model.CommentTypeCode, issues_model.CommentTypeReview)
+	if err != nil {
+		return err
+	}
+	for i, issue := range il {
+		if comment, ok := lastComments[issue.ID]; ok && apiIssues[i].ID != 0 {
+			apiIssues[i].LastCommented = comment.CreatedUnix.AsTimePtr()
+		}
+	}
+	return nil
+}
+
+// loadDeadlineSetters sets who set the deadlines of the issues and when. Removing the deadline resets it,
+// so only the latest add or modify comment is relevant. The comments of all issues are loaded at once.
+func loadDeadlineSetters(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	issueIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].ID != 0 && issue.DeadlineUnix != 0 {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	deadlineComments, err := issues_model.GetLatestCommentsByType(ctx, issueIDs, issues_model.CommentTypeAddedDeadline, issues_model.CommentTypeModifiedDeadline)
+	if err != nil {
+		return err
+	}
+	comments := make(issues_model.CommentList, 0, len(deadlineComments))
+	for _, comment := range deadlineComments {
+		comments = append(comments, comment)
+	}
+	if err := comments.LoadPosters(ctx); err != nil {
+		return err
+	}
+	for i, issue := range il {
+		if comment, ok := deadlineComments[issue.ID]; ok && apiIssues[i].ID != 0 && issue.DeadlineUnix != 0 {
+			apiIssues[i].DeadlineSetBy = ToUser(comment.Poster, nil)
+			apiIssues[i].DeadlineSetAt = comment.CreatedUnix.AsTimePtr()
+		}
+	}
+	return nil
+}
+
+// loadLastResponses sets the seconds since the latest response of somebody else than the poster,
+// the latest responses of all issues are loaded at once
+func loadLastResponses(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	issueIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if apiIssues[i].ID != 0 {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	responseTimes, err := issues_model.GetLastResponseTimesByIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return err
+	}
+	now := timeutil.TimeStampNow()
+	for i, issue := range il {
+		if responseTime, ok := responseTimes[issue.ID]; ok && apiIssues[i].ID != 0 && responseTime < now {
+			apiIssues[i].SecondsSinceLastResponse = int64(now - responseTime)
+		}
+	}
+	return nil
+}
+
+// loadReviewStates sets the requested reviewers and the aggregate review state of the pull requests,
+// the latest reviews of all pull requests are loaded at once
+func loadReviewStates(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	issueIDs := make([]int64, 0, len(il))
+	for i, issue := range il {
+		if issue.IsPull && apiIssues[i].PullRequest != nil {
+			issueIDs = append(issueIDs, issue.ID)
+		}
+	}
+	if len(issueIDs) == 0 {
+		return nil
+	}
+
+	reviewsMap, err := issues_model.GetReviewersByIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return err
+	}
+	for i, issue := range il {
+		if !issue.IsPull || apiIssues[i].PullRequest == nil {
+			continue
+		}
+		pr := apiIssues[i].PullRequest
+		var approved, rejected, requested bool
+		var lastReviewed timeutil.TimeStamp
+		for _, review := range reviewsMap[issue.ID] {
+			switch review.Type {
+			case issues_model.ReviewTypeApprove:
+				approved = true
+			case issues_model.ReviewTypeReject:
+				rejected = true
+			case issues_model.ReviewTypeRequest:
+				requested = true
+				if review.Reviewer != nil {
+					pr.RequestedReviewers = append(pr.RequestedReviewers, ToUser(review.Reviewer, nil))
+				}
+			}
+			if review.Type != issues_model.ReviewTypeRequest && review.CreatedUnix > lastReviewed {
+				lastReviewed = review.CreatedUnix
+			}
+		}
+		pr.BaseChangedSinceReview = cachedBaseChangedSinceReview(issue.PullRequest, lastReviewed)
+		switch {
+		case rejected:
+			pr.ReviewState = api.ReviewStateRequestChanges
+		case requested || !approved:
+			pr.ReviewState = api.ReviewStatePending
+		default:
+			pr.ReviewState = api.ReviewStateApproved
+		}
+	}
+	return nil
+}
+
+// loadFirstTimeContributors marks the issues which are the first issue or pull request of their poster in the repository,
+// the owner of the repository and users excluded by the settings never count as first time contributors
+func loadFirstTimeContributors(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	repoIDs := make([]int64, 0, len(il))
+	posterIDs := make([]int64, 0, len(il))
+	for _, issue := range il {
+		if issue.PosterID > 0 && issue.OriginalAuthorID == 0 {
+			repoIDs = append(repoIDs, issue.RepoID)
+			posterIDs = append(posterIDs, issue.PosterID)
+		}
+	}
+	if len(posterIDs) == 0 {
+		return nil
+	}
+
+	firstIssueIDs, err := issues_model.GetFirstIssueIDsOfPosters(ctx, repoIDs, posterIDs)
+	if err != nil {
+		return err
+	}
+	firstIssues := container.SetOf(firstIssueIDs...)
+
+	for i, issue := range il {
+		if !firstIssues.Contains(issue.ID) || apiIssues[i].ID == 0 {
+			continue
+		}
+		excluded, err := isExcludedFromFirstTimeContributors(ctx, issue)
+		if err != nil {
+			return err
+		}
+		apiIssues[i].PosterIsFirstTimeContributor = !excluded
+	}
+	return nil
+}
+
+func isExcludedFromFirstTimeContributors(ctx context.Context, issue *issues_model.Issue) (bool, error) {
+	if issue.PosterID == issue.Repo.OwnerID {
+		return true, nil
+	}
+	for _, name := range setting.Repository.Issue.FirstTimeContributorExcludedUsers {
+		if strings.EqualFold(name, issue.Poster.Name) {
+			return true, nil
+		}
+	}
+	if setting.Repository.Issue.FirstTimeContributorExcludeMembers && issue.Repo.Owner.IsOrganization() {
+		return organization.IsOrganizationMember(ctx, issue.Repo.OwnerID, issue.PosterID)
+	}
+	return false, nil
+}
+
+// loadReferencedBy counts the issues and pull requests referencing each of the issues which are visible to the viewer
+func loadReferencedBy(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue, viewer *user_model.User) error {
+	issueIDs := make([]int64, 0, len(il))
+	for _, issue := range il {
+		issueIDs = append(issueIDs, issue.ID)
+	}
+	sourcesMap, err := issues_model.GetCrossReferenceSources(ctx, issueIDs)
+	if err != nil {
+		return err
+	}
+
+	repoIDs := make([]int64, 0, len(sourcesMap))
+	for _, sources := range sourcesMap {
+		for _, source := range sources {
+			repoIDs = append(repoIDs, source.RefRepoID)
+		}
+	}
+	repos, err := batchLoad(ctx, repoIDs, func(repo *repo_model.Repository) int64 { return repo.ID })
+	if err != nil {
+		return err
+	}
+
+	perms := make(map[int64]access_model.Permission, len(repos))
+	for i, issue := range il {
+		for _, source := range sourcesMap[issue.ID] {
+			repo, ok := repos[source.RefRepoID]
+			if !ok {
+				// the referencing repository has been deleted
+				continue
+			}
+			perm, ok := perms[repo.ID]
+			if !ok {
+				if perm, err = access_model.GetUserRepoPermission(ctx, repo, viewer); err != nil {
+					return err
+				}
+				perms[repo.ID] = perm
+			}
+			if perm.CanReadIssuesOrPulls(source.RefIsPull) {
+				apiIssues[i].ReferencedBy++
+			}
+		}
+	}
+	return nil
+}
+
+// ToAPIIssueForWebhook converts an Issue to API format like ToAPIIssue for the payload of a webhook,
+// leaving out or truncating the body and the emails of the assignees as configured for the webhook.
+func ToAPIIssueForWebhook(ctx context.Context, issue *issues_model.Issue, settings webhook_model.IssuePayloadSettings) *api.Issue {
+	return webhook_service.MaskAPIIssue(ToAPIIssue(ctx, issue), settings)
+}
+
+// ToAPIIssueRedacted converts an Issue to API format like ToAPIIssue, but strips
+// cross references to repositories and mentions of users the viewer cannot see from the body.
+func ToAPIIssueRedacted(ctx context.Context, issue *issues_model.Issue, viewer *user_model.User) *api.Issue {
+	apiIssue := ToAPIIssue(ctx, issue)
+	body, err := redactContent(ctx, issue.Content, viewer)
+	if err != nil {
+		log.Error("redactContent[%d]: %v", issue.ID, err)
+		return &api.Issue{}
+	}
+	apiIssue.Body = body
+	return apiIssue
+}
+
+// IssueETag returns an entity tag for the API representation of the issue as seen by the viewer.
+// It derives from the update time ToAPIIssue reports as updated_at and the number of comments,
+// and from the since time given to ToAPIIssueForViewer. Fields derived from the current time,
+// like age_seconds, are left out, so that an unchanged issue keeps its tag.
+// It can be computed before the issue is converted.
+func IssueETag(issue *issues_model.Issue, viewer *user_model.User, since time.Time) string {
+	var viewerID, sinceUnix int64
+	if viewer != nil {
+		viewerID = viewer.ID
+	}
+	if !since.IsZero() {
+		sinceUnix = since.Unix()
+	}
+	return fmt.Sprintf(`"%d-%d-%d-%d-%d"`, issue.ID, issue.UpdatedUnix, issue.NumComments, viewerID, sinceUnix)
+}
+
+// ToAPIIssueForViewer converts an Issue to API format like ToAPIIssue and additionally reports
+// whether the viewer may edit or comment on the issue, following the rules of the web UI.
+// If since is not zero, it also reports whether the assignees have changed after that time.
+func ToAPIIssueForViewer(ctx context.Context, issue *issues_model.Issue, viewer *user_model.User, since time.Time) *api.Issue {
+	return ToAPIIssueForViewerWithAvatarSize(ctx, issue, viewer, since, 0)
+}
+
+// ToAPIIssueForViewerWithAvatarSize converts an Issue like ToAPIIssueForViewer, but the avatar links of the
+// poster and the assignees point to avatars of the given size in pixels. size <= 0 means default size.
+func ToAPIIssueForViewerWithAvatarSize(ctx context.Context, issue *issues_model.Issue, viewer *user_model.User, since time.Time, avatarSize int) *api.Issue {
+	apiIssue, err := toAPIIssueWithError(ctx, issue, viewer)
+	if err != nil {
+		log.Error("ToAPIIssueForViewerWithAvatarSize[%d]: %v", issue.ID, err)
+		return &api.Issue{}
+	}
+	if avatarSize > 0 {
+		setAvatarSize(apiIssue, issue, avatarSize)
+	}
+	if !since.IsZero() {
+		changed, err := issues_model.HasAssigneesChangedSince(ctx, issue.ID, timeutil.TimeStamp(since.Unix()))
+		if err != nil {
+			log.Error("HasAssigneesChangedSince[%d]: %v", issue.ID, err)
+		}
+		apiIssue.AssigneesChangedSince = changed
+	}
+	if err := loadReferencedBy(ctx, issues_model.IssueList{issue}, []*api.Issue{apiIssue}, viewer); err != nil {
+		log.Error("loadReferencedBy[%d]: %v", issue.ID, err)
+	}
+	if err := loadPosterOrgRoles(ctx, apiIssue, issue); err != nil {
+		log.Error("loadPosterOrgRoles[%d]: %v", issue.ID, err)
+	}
+	if viewer == nil {
+		return apiIssue
+	}
+
+	perm, err := access_model.GetUserRepoPermission(ctx, issue.Repo, viewer)
+	if err != nil {
+		log.Error("GetUserRepoPermission[%d]: %v", issue.ID, err)
+		return apiIssue
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		return apiIssue
+	}
 
-	apiIssue.Repo = &api.RepositoryMeta{
-		ID:       issue.Repo.ID,
-		Name:     issue.Repo.Name,
-		Owner:    issue.Repo.OwnerName,
-		FullName: issue.Repo.FullName(),
+	canWrite := perm.CanWriteIssuesOrPulls(issue.IsPull) || viewer.IsAdmin
+	apiIssue.CanEdit = canWrite || issue.IsPoster(viewer.ID)
+	// locked issues can only be commented on by users with write access
+	apiIssue.CanComment = !issue.IsLocked || canWrite
+
+	if err := loadSubscription(ctx, apiIssue, issue, viewer); err != nil {
+		log.Error("loadSubscription[%d]: %v", issue.ID, err)
 	}
+	return apiIssue
+}
 
-	if issue.ClosedUnix != 0 {
-		apiIssue.Closed = issue.ClosedUnix.AsTimePtr()
+// setAvatarSize replaces the avatar links of the poster and the assignees by links to avatars of the given size.
+func setAvatarSize(apiIssue *api.Issue, issue *issues_model.Issue, size int) {
+	if apiIssue.Poster != nil && issue.Poster != nil {
+		apiIssue.Poster.AvatarURL = issue.Poster.AvatarFullLinkWithSize(size)
 	}
+	for i, assignee := range issue.Assignees {
+		apiIssue.Assignees[i].AvatarURL = assignee.AvatarFullLinkWithSize(size)
+	}
+	if apiIssue.Assignee != nil && len(issue.Assignees) > 0 {
+		apiIssue.Assignee.AvatarURL = issue.Assignees[0].AvatarFullLinkWithSize(size)
+	}
+}
 
-	if err := issue.LoadMilestone(ctx); err != nil {
-		return &api.Issue{}
+// loadPosterOrgRoles sets the names of the public teams the poster belongs to in the organization owning the
+// repository. Memberships of teams which are not public are never shown.
+func loadPosterOrgRoles(ctx context.Context, apiIssue *api.Issue, issue *issues_model.Issue) error {
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
 	}
-	if issue.Milestone != nil {
-		apiIssue.Milestone = ToAPIMilestone(issue.Milestone)
+	if err := issue.Repo.GetOwner(ctx); err != nil {
+		return err
+	}
+	if !issue.Repo.Owner.IsOrganization() {
+		// only organizations have teams
+		return nil
+	}
+	teams, err := organization.GetUserOrgPublicTeams(ctx, issue.Repo.OwnerID, issue.PosterID)
+	if err != nil {
+		return err
+	}
+	for _, team := range teams {
+		apiIssue.PosterOrgRoles = append(apiIssue.PosterOrgRoles, team.Name)
 	}
+	return nil
+}
 
-	if err := issue.LoadAssignees(ctx); err != nil {
-		return &api.Issue{}
+// loadSubscription sets whether the viewer is subscribed to the issue, either explicitly or through watching the
+// repository or participating, and whether the viewer has explicitly unsubscribed from it
+func loadSubscription(ctx context.Context, apiIssue *api.Issue, issue *issues_model.Issue, viewer *user_model.User) error {
+	iw, exists, err := issues_model.GetIssueWatch(ctx, viewer.ID, issue.ID)
+	if err != nil {
+		return err
 	}
-	if len(issue.Assignees) > 0 {
-		for _, assignee := range issue.Assignees {
-			apiIssue.Assignees = append(apiIssue.Assignees, ToUser(assignee, nil))
-		}
-		apiIssue.Assignee = ToUser(issue.Assignees[0], nil) // For compatibility, we're keeping the first assignee as `apiIssue.Assignee`
+	if exists {
+		apiIssue.Subscribed = iw.IsWatching
+		apiIssue.SubscriptionMuted = !iw.IsWatching
+		return nil
 	}
-	if issue.IsPull {
-		if err := issue.LoadPullRequest(ctx); err != nil {
-			return &api.Issue{}
+	apiIssue.Subscribed, err = issues_model.CheckIssueWatch(viewer, issue)
+	return err
+}
+
+// redactContent removes issue references to repositories and mentions of users which are not visible to viewer
+func redactContent(ctx context.Context, content string, viewer *user_model.User) (string, error) {
+	var spans []references.RefSpan
+
+	for _, ref := range references.FindAllRenderizableReferencesNumeric(content) {
+		if ref.Owner == "" {
+			// references within the same repository are visible to anyone who can see the issue
+			continue
 		}
-		apiIssue.PullRequest = &api.PullRequestMeta{
-			HasMerged: issue.PullRequest.HasMerged,
+		visible, err := isRepoIssueVisible(ctx, ref.Owner, ref.Name, ref.IsPull, viewer)
+		if err != nil {
+			return "", err
 		}
-		if issue.PullRequest.HasMerged {
-			apiIssue.PullRequest.Merged = issue.PullRequest.MergedUnix.AsTimePtr()
+		if !visible {
+			spans = append(spans, *ref.RefLocation)
 		}
 	}
-	if issue.DeadlineUnix != 0 {
-		apiIssue.Deadline = issue.DeadlineUnix.AsTimePtr()
+
+	for _, loc := range references.FindAllMentionsBytes([]byte(content)) {
+		// team mentions are of the form @org/team, visibility follows the organization
+		name, _, _ := strings.Cut(content[loc.Start+1:loc.End], "/")
+		u, err := user_model.GetUserByName(ctx, name)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		if !user_model.IsUserVisibleToViewer(ctx, u, viewer) {
+			spans = append(spans, loc)
+		}
 	}
 
-	return apiIssue
+	if len(spans) == 0 {
+		return content, nil
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	var sb strings.Builder
+	pos := 0
+	for _, span := range spans {
+		sb.WriteString(content[pos:span.Start])
+		pos = span.End
+	}
+	sb.WriteString(content[pos:])
+	return sb.String(), nil
 }
 
-// ToAPIIssueList converts an IssueList to API format
-func ToAPIIssueList(ctx context.Context, il issues_model.IssueList) []*api.Issue {
-	result := make([]*api.Issue, len(il))
-	for i := range il {
-		result[i] = ToAPIIssue(ctx, il[i])
+func isRepoIssueVisible(ctx context.Context, owner, name string, isPull bool, viewer *user_model.User) (bool, error) {
+	repo, err := repo_model.GetRepositoryByOwnerAndNameCtx(ctx, owner, name)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			return false, nil
+		}
+		return false, err
 	}
-	return result
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, viewer)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanReadIssuesOrPulls(isPull), nil
 }
 
 // ToTrackedTime converts TrackedTime to API format
 func ToTrackedTime(ctx context.Context, t *issues_model.TrackedTime) (apiT *api.TrackedTime) {
+	return toTrackedTime(ctx, t, nil)
+}
+
+// toTrackedTime converts a TrackedTime to API format, issues already converted for other times are reused from apiIssues
+func toTrackedTime(ctx context.Context, t *issues_model.TrackedTime, apiIssues map[int64]*api.Issue) (apiT *api.TrackedTime) {
 	apiT = &api.TrackedTime{
-		ID:       t.ID,
-		IssueID:  t.IssueID,
-		UserID:   t.UserID,
-		UserName: t.User.Name,
-		Time:     t.Time,
-		Created:  t.Created,
+		ID:          t.ID,
+		IssueID:     t.IssueID,
+		UserID:      t.UserID,
+		Time:        t.Time,
+		TimeMs:      t.Milliseconds(),
+		ISODuration: util.SecToISODuration(t.Time),
+		Created:     t.Created,
+		Deleted:     t.Deleted,
+		Billable:    t.Billable,
 	}
 	if t.Issue != nil {
-		apiT.Issue = ToAPIIssue(ctx, t.Issue)
+		apiIssue, ok := apiIssues[t.IssueID]
+		if !ok {
+			apiIssue = ToAPIIssue(ctx, t.Issue)
+			if apiIssues != nil {
+				apiIssues[t.IssueID] = apiIssue
+			}
+		}
+		apiT.Issue = apiIssue
 	}
 	if t.User != nil {
 		apiT.UserName = t.User.Name
 	}
+	if t.Comment != nil {
+		apiT.CommentID = t.Comment.ID
+		apiT.CommentBody = t.Comment.Content
+	}
 	return apiT
 }
 
 // ToStopWatches convert Stopwatch list to api.StopWatches
-func ToStopWatches(sws []*issues_model.Stopwatch) (api.StopWatches, error) {
+func ToStopWatches(ctx context.Context, sws []*issues_model.Stopwatch) (api.StopWatches, error) {
+	return ToStopWatchesWithCache(ctx, sws, nil)
+}
+
+// ToStopWatchesWithCache converts a Stopwatch list to api.StopWatches like ToStopWatches, looking up the
+// issues and repositories through the cache. Callers converting the stopwatches of several users can share
+// one cache, with a nil cache they are loaded for this call only.
+func ToStopWatchesWithCache(ctx context.Context, sws []*issues_model.Stopwatch, cache *StopWatchCache) (api.StopWatches, error) {
 	result := api.StopWatches(make([]api.StopWatch, 0, len(sws)))
+	if len(sws) == 0 {
+		return result, nil
+	}
 
-	issueCache := make(map[int64]*issues_model.Issue)
-	repoCache := make(map[int64]*repo_model.Repository)
-	var (
-		issue *issues_model.Issue
-		repo  *repo_model.Repository
-		ok    bool
-		err   error
-	)
+	issueIDs := make(container.Set[int64], len(sws))
+	for _, sw := range sws {
+		issueIDs.Add(sw.IssueID)
+	}
+	issueMap, err := cache.getIssues(ctx, issueIDs.Values())
+	if err != nil {
+		return nil, err
+	}
+	repoIDs := make(container.Set[int64], len(issueMap))
+	for _, issue := range issueMap {
+		repoIDs.Add(issue.RepoID)
+	}
+	repoMap, err := cache.getRepos(ctx, repoIDs.Values())
+	if err != nil {
+		return nil, err
+	}
 
 	for _, sw := range sws {
-		issue, ok = issueCache[sw.IssueID]
+		issue, ok := issueMap[sw.IssueID]
 		if !ok {
-			issue, err = issues_model.GetIssueByID(db.DefaultContext, sw.IssueID)
-			if err != nil {
-				return nil, err
-			}
+			return nil, issues_model.ErrIssueNotExist{ID: sw.IssueID}
 		}
-		repo, ok = repoCache[issue.RepoID]
+		repo, ok := repoMap[issue.RepoID]
 		if !ok {
-			repo, err = repo_model.GetRepositoryByID(issue.RepoID)
-			if err != nil {
-				return nil, err
-			}
+			return nil, repo_model.ErrRepoNotExist{ID: issue.RepoID}
 		}
 
+		// a stopwatch only exists while it is running, so the elapsed time is
+		// computed once against now and shared by Seconds and Duration
+		seconds := sw.Seconds()
 		result = append(result, api.StopWatch{
 			Created:       sw.CreatedUnix.AsTime(),
-			Seconds:       sw.Seconds(),
-			Duration:      sw.Duration(),
+			Seconds:       seconds,
+			Duration:      util.SecToTime(seconds),
+			Running:       true,
 			IssueIndex:    issue.Index,
 			IssueTitle:    issue.Title,
 			RepoOwnerName: repo.OwnerName,
@@ -168,23 +1271,317 @@ func ToStopWatches(sws []*issues_model.Stopwatch) (api.StopWatches, error) {
 	return result, nil
 }
 
-// ToTrackedTimeList converts TrackedTimeList to API format
-func ToTrackedTimeList(ctx context.Context, tl issues_model.TrackedTimeList) api.TrackedTimeList {
+// ToStopWatchSummary converts a Stopwatch list to api.StopWatchSummary,
+// totalling the time elapsed on all of the running stopwatches
+func ToStopWatchSummary(ctx context.Context, sws []*issues_model.Stopwatch) (*api.StopWatchSummary, error) {
+	apiSWs, err := ToStopWatches(ctx, sws)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &api.StopWatchSummary{StopWatches: apiSWs}
+	for _, sw := range apiSWs {
+		summary.TotalSeconds += sw.Seconds
+	}
+	summary.TotalDuration = util.SecToTime(summary.TotalSeconds)
+	return summary, nil
+}
+
+// ToTrackedTimeList converts TrackedTimeList to API format and returns the sum of its billable times in seconds.
+// The users which are not loaded yet are loaded at once and every issue is converted only once.
+func ToTrackedTimeList(ctx context.Context, tl issues_model.TrackedTimeList) (api.TrackedTimeList, int64) {
+	userIDs := make([]int64, 0, len(tl))
+	for _, t := range tl {
+		if t.User == nil {
+			userIDs = append(userIDs, t.UserID)
+		}
+	}
+	users, err := batchLoad(ctx, userIDs, func(u *user_model.User) int64 { return u.ID })
+	if err != nil {
+		log.Error("ToTrackedTimeList: %v", err)
+	}
+	for _, t := range tl {
+		if t.User == nil {
+			t.User = users[t.UserID]
+		}
+	}
+
 	result := make([]*api.TrackedTime, 0, len(tl))
+	apiIssues := make(map[int64]*api.Issue)
+	var billableTotal int64
 	for _, t := range tl {
-		result = append(result, ToTrackedTime(ctx, t))
+		result = append(result, toTrackedTime(ctx, t, apiIssues))
+		if t.Billable {
+			billableTotal += t.Time
+		}
 	}
-	return result
+	return result, billableTotal
+}
+
+// ToTrackedTimeSummary converts a TrackedTimeList to API format together with
+// the totals per issue and per user. Times tracked in repositories which have
+// the time tracker disabled are left out.
+func ToTrackedTimeSummary(ctx context.Context, tl issues_model.TrackedTimeList) (*api.TrackedTimeSummary, error) {
+	if err := tl.LoadAttributes(); err != nil {
+		return nil, err
+	}
+
+	summary := &api.TrackedTimeSummary{
+		ByIssue: make([]*api.TrackedTimeIssueTotal, 0, len(tl)),
+		ByUser:  make([]*api.TrackedTimeUserTotal, 0, len(tl)),
+		Times:   make([]*api.TrackedTime, 0, len(tl)),
+	}
+	issueTotals := make(map[int64]*api.TrackedTimeIssueTotal)
+	userTotals := make(map[int64]*api.TrackedTimeUserTotal)
+	timetrackerEnabled := make(map[int64]bool)
+	apiIssues := make(map[int64]*api.Issue)
+
+	for _, t := range tl {
+		enabled, ok := timetrackerEnabled[t.Issue.RepoID]
+		if !ok {
+			enabled = t.Issue.Repo.IsTimetrackerEnabledCtx(ctx)
+			timetrackerEnabled[t.Issue.RepoID] = enabled
+		}
+		if !enabled {
+			continue
+		}
+
+		issueTotal, ok := issueTotals[t.IssueID]
+		if !ok {
+			issueTotal = &api.TrackedTimeIssueTotal{IssueID: t.IssueID, Index: t.Issue.Index}
+			issueTotals[t.IssueID] = issueTotal
+			summary.ByIssue = append(summary.ByIssue, issueTotal)
+		}
+		issueTotal.Time += t.Time
+		issueTotal.TimeMs += t.Milliseconds()
+
+		userTotal, ok := userTotals[t.UserID]
+		if !ok {
+			userTotal = &api.TrackedTimeUserTotal{UserID: t.UserID, UserName: t.User.Name}
+			userTotals[t.UserID] = userTotal
+			summary.ByUser = append(summary.ByUser, userTotal)
+		}
+		userTotal.Time += t.Time
+		userTotal.TimeMs += t.Milliseconds()
+
+		summary.Total += t.Time
+		if t.Billable {
+			summary.BillableTotal += t.Time
+		}
+		summary.Times = append(summary.Times, toTrackedTime(ctx, t, apiIssues))
+	}
+	return summary, nil
+}
+
+// ToUserTimeDashboard converts the running stopwatches of the user and the times they tracked since the start of
+// the day into one API response. Issues and repositories are loaded at once, stopwatches and times in repositories
+// the user can no longer read the issues of are left out.
+func ToUserTimeDashboard(ctx context.Context, userID int64) (*api.UserTimeDashboard, error) {
+	user, err := user_model.GetUserByIDCtx(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sws, err := issues_model.GetUserStopwatches(userID, db.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	now := timeutil.TimeStampNow().AsLocalTime()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tl, err := issues_model.GetTrackedTimes(ctx, &issues_model.FindTrackedTimesOptions{
+		UserID:           userID,
+		CreatedAfterUnix: startOfDay.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issueIDs := make([]int64, 0, len(sws)+len(tl))
+	for _, sw := range sws {
+		issueIDs = append(issueIDs, sw.IssueID)
+	}
+	for _, t := range tl {
+		issueIDs = append(issueIDs, t.IssueID)
+	}
+	issues, err := batchLoad(ctx, issueIDs, func(issue *issues_model.Issue) int64 { return issue.ID })
+	if err != nil {
+		return nil, err
+	}
+	repoIDs := make([]int64, 0, len(issues))
+	for _, issue := range issues {
+		repoIDs = append(repoIDs, issue.RepoID)
+	}
+	repos, err := batchLoad(ctx, repoIDs, func(repo *repo_model.Repository) int64 { return repo.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	// the permission is looked up once per repository
+	canRead := make(map[int64]bool, len(repos))
+	isVisible := func(issueID int64) (bool, error) {
+		issue, ok := issues[issueID]
+		if !ok {
+			return false, nil
+		}
+		repo, ok := repos[issue.RepoID]
+		if !ok {
+			return false, nil
+		}
+		readable, ok := canRead[repo.ID]
+		if !ok {
+			perm, err := access_model.GetUserRepoPermission(ctx, repo, user)
+			if err != nil {
+				return false, err
+			}
+			readable = perm.CanReadIssuesOrPulls(issue.IsPull)
+			canRead[repo.ID] = readable
+		}
+		issue.Repo = repo
+		return readable, nil
+	}
+
+	visibleSWs := make([]*issues_model.Stopwatch, 0, len(sws))
+	for _, sw := range sws {
+		visible, err := isVisible(sw.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			visibleSWs = append(visibleSWs, sw)
+		}
+	}
+	visibleTimes := make(issues_model.TrackedTimeList, 0, len(tl))
+	for _, t := range tl {
+		visible, err := isVisible(t.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			t.Issue = issues[t.IssueID]
+			t.User = user
+			visibleTimes = append(visibleTimes, t)
+		}
+	}
+
+	apiSWs, err := ToStopWatches(ctx, visibleSWs)
+	if err != nil {
+		return nil, err
+	}
+	trackedToday, _ := ToTrackedTimeList(ctx, visibleTimes)
+	dashboard := &api.UserTimeDashboard{
+		StopWatches:  apiSWs,
+		TrackedToday: trackedToday,
+	}
+	for _, sw := range apiSWs {
+		dashboard.StopWatchSeconds += sw.Seconds
+	}
+	for _, t := range visibleTimes {
+		dashboard.TrackedTodaySeconds += t.Time
+	}
+	dashboard.TotalSeconds = dashboard.StopWatchSeconds + dashboard.TrackedTodaySeconds
+	return dashboard, nil
+}
+
+func toLabelColor(label *issues_model.Label) string {
+	return strings.TrimLeft(label.Color, "#")
+}
+
+// ToIssuePermissions returns what the viewer may do with the issue according to their permission in its repository.
+// Anonymous viewers and viewers who cannot read the issue may do nothing.
+func ToIssuePermissions(ctx context.Context, issue *issues_model.Issue, viewer *user_model.User) (*api.IssuePermissions, error) {
+	perms := &api.IssuePermissions{}
+	if viewer == nil {
+		return perms, nil
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, issue.Repo, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		return perms, nil
+	}
+
+	canWrite := perm.CanWriteIssuesOrPulls(issue.IsPull) || viewer.IsAdmin
+	// the poster may close and reopen their own issue
+	canChangeState := canWrite || issue.IsPoster(viewer.ID)
+	perms.CanClose = !issue.IsClosed && canChangeState
+	perms.CanReopen = issue.IsClosed && canChangeState
+	perms.CanAssign = canWrite
+	perms.CanLabel = canWrite
+	perms.CanLock = canWrite
+	perms.CanDelete = perm.IsAdmin() || viewer.IsAdmin
+	return perms, nil
+}
+
+// ToIssueVoteSummary counts the 👍 and 👎 reactions of the issue as votes.
+// If the viewer reacted with both, their most recent reaction is their vote.
+func ToIssueVoteSummary(ctx context.Context, issue *issues_model.Issue, viewer *user_model.User) (*api.IssueVoteSummary, error) {
+	if err := issue.LoadReactions(ctx); err != nil {
+		return nil, err
+	}
+	summary := &api.IssueVoteSummary{}
+	for _, reaction := range issue.Reactions {
+		var vote string
+		switch reaction.Type {
+		case "+1":
+			summary.Up++
+			vote = "up"
+		case "-1":
+			summary.Down++
+			vote = "down"
+		default:
+			continue
+		}
+		// reactions are sorted by their creation, so a later vote replaces an earlier one
+		if viewer != nil && reaction.OriginalAuthor == "" && reaction.UserID == viewer.ID {
+			summary.ViewerVote = vote
+		}
+	}
+	return summary, nil
+}
+
+// issueAgeSeconds returns the seconds from the creation of the issue until it was closed, or until now if it is open.
+// The server clock is used so that clients do not depend on their own one.
+func issueAgeSeconds(issue *issues_model.Issue) int64 {
+	end := timeutil.TimeStampNow()
+	if issue.IsClosed && issue.ClosedUnix != 0 {
+		end = issue.ClosedUnix
+	}
+	if end < issue.CreatedUnix {
+		return 0
+	}
+	return int64(end - issue.CreatedUnix)
 }
 
 // ToLabel converts Label to API format
-func ToLabel(label *issues_model.Label, repo *repo_model.Repository, org *user_model.User) *api.Label {
+func ToLabel(ctx context.Context, label *issues_model.Label, repo *repo_model.Repository, org *user_model.User) *api.Label {
+	return ToLabelList(ctx, []*issues_model.Label{label}, repo, org)[0]
+}
+
+func toLabel(label *issues_model.Label, repo *repo_model.Repository, org *user_model.User) *api.Label {
 	result := &api.Label{
 		ID:          label.ID,
 		Name:        label.Name,
-		Color:       strings.TrimLeft(label.Color, "#"),
+		RawName:     label.Name,
+		Color:       toLabelColor(label),
+		TextColor:   "000000",
 		Description: label.Description,
+		Order:       label.Order,
+		IsDefault:   repo_module.IsDefaultLabel(label.Name, label.Color),
 	}
+	if label.UseLightTextColor() {
+		result.TextColor = "ffffff"
+	}
+	if setting.API.RenderLabelEmoji {
+		result.Name = emoji.ReplaceAliases(label.Name)
+	}
+	if label.BelongsToOrg() {
+		result.OrgID = label.OrgID
+	}
+	result.Scope = label.ExclusiveScope()
+	result.Exclusive = result.Scope != ""
 
 	// calculate URL
 	if label.BelongsToRepo() && repo != nil {
@@ -205,10 +1602,151 @@ func ToLabel(label *issues_model.Label, repo *repo_model.Repository, org *user_m
 }
 
 // ToLabelList converts list of Label to API format
-func ToLabelList(labels []*issues_model.Label, repo *repo_model.Repository, org *user_model.User) []*api.Label {
+func ToLabelList(ctx context.Context, labels []*issues_model.Label, repo *repo_model.Repository, org *user_model.User) []*api.Label {
+	result := toLabelList(labels, repo, org)
+	if err := loadLabelBoardColumns(ctx, result); err != nil {
+		log.Error("loadLabelBoardColumns: %v", err)
+	}
+	return result
+}
+
+// LabelListOptions selects the fields ToLabelListWithOptions includes in addition to those of ToLabelList
+type LabelListOptions struct {
+	// include the time the labels have last been used on an issue or pull request the viewer can read
+	LastUsed bool
+	Viewer   *user_model.User
+	// include the number of open and closed issues carrying the labels
+	IssueCounts bool
+	// include the descriptions rendered as markdown, the HTML only keeps inline formatting and links
+	RenderDescription bool
+}
+
+// ToLabelWithOptions converts Label to API format like ToLabelListWithOptions
+func ToLabelWithOptions(ctx context.Context, label *issues_model.Label, repo *repo_model.Repository, org *user_model.User, opts LabelListOptions) (*api.Label, error) {
+	result, err := ToLabelListWithOptions(ctx, []*issues_model.Label{label}, repo, org, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result[0], nil
+}
+
+// ToLabelListWithOptions converts list of Label to API format including the fields selected by opts
+func ToLabelListWithOptions(ctx context.Context, labels []*issues_model.Label, repo *repo_model.Repository, org *user_model.User, opts LabelListOptions) ([]*api.Label, error) {
+	result := ToLabelList(ctx, labels, repo, org)
+	if opts.LastUsed {
+		if err := loadLabelsLastUsed(ctx, result, opts.Viewer); err != nil {
+			return nil, err
+		}
+	}
+	if opts.IssueCounts {
+		// the counters of the labels are kept up to date when issues are labeled, closed or reopened
+		for i, label := range labels {
+			result[i].OpenIssuesCount = label.NumIssues - label.NumClosedIssues
+			result[i].ClosedIssuesCount = label.NumClosedIssues
+		}
+	}
+	if opts.RenderDescription {
+		if err := renderLabelDescriptions(ctx, result, repo); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// renderLabelDescriptions sets the descriptions of the labels rendered as markdown
+func renderLabelDescriptions(ctx context.Context, apiLabels []*api.Label, repo *repo_model.Repository) error {
+	renderCtx := &markup.RenderContext{Ctx: ctx}
+	if repo != nil {
+		renderCtx.URLPrefix = repo.Link()
+		renderCtx.Metas = repo.ComposeMetas()
+	}
+	for _, apiLabel := range apiLabels {
+		if apiLabel.Description == "" {
+			continue
+		}
+		rendered, err := markdown.RenderString(renderCtx, apiLabel.Description)
+		if err != nil {
+			return err
+		}
+		apiLabel.RenderedDescription = strings.TrimSpace(markup.SanitizeDescription(rendered))
+	}
+	return nil
+}
+
+// loadLabelsLastUsed sets the time the labels have last been used on an issue or pull request the viewer can read
+func loadLabelsLastUsed(ctx context.Context, apiLabels []*api.Label, viewer *user_model.User) error {
+	labelIDs := make([]int64, 0, len(apiLabels))
+	for _, apiLabel := range apiLabels {
+		labelIDs = append(labelIDs, apiLabel.ID)
+	}
+
+	var issueCond builder.Cond
+	if viewer == nil || !viewer.IsAdmin {
+		issueCond = builder.Or(
+			builder.Eq{"issue.is_pull": false}.And(builder.In("issue.repo_id",
+				builder.Select("id").From("repository").Where(repo_model.AccessibleRepositoryCondition(viewer, unit.TypeIssues)))),
+			builder.Eq{"issue.is_pull": true}.And(builder.In("issue.repo_id",
+				builder.Select("id").From("repository").Where(repo_model.AccessibleRepositoryCondition(viewer, unit.TypePullRequests)))),
+		)
+	}
+	lastUsed, err := issues_model.GetLabelsLastUsed(ctx, labelIDs, issueCond)
+	if err != nil {
+		return err
+	}
+	for _, apiLabel := range apiLabels {
+		apiLabel.LastUsedUnix = int64(lastUsed[apiLabel.ID])
+	}
+	return nil
+}
+
+// toLabelList converts list of Label to API format without loading any related data
+func toLabelList(labels []*issues_model.Label, repo *repo_model.Repository, org *user_model.User) []*api.Label {
 	result := make([]*api.Label, len(labels))
 	for i := range labels {
-		result[i] = ToLabel(labels[i], repo, org)
+		result[i] = toLabel(labels[i], repo, org)
+	}
+	return result
+}
+
+// loadLabelBoardColumns sets the project board columns backed by the labels, the boards of all labels are loaded at once
+func loadLabelBoardColumns(ctx context.Context, apiLabels []*api.Label) error {
+	labelIDs := make([]int64, 0, len(apiLabels))
+	for _, apiLabel := range apiLabels {
+		labelIDs = append(labelIDs, apiLabel.ID)
+	}
+	boardIDs, err := project_model.GetBoardIDsByLabelIDs(ctx, labelIDs)
+	if err != nil {
+		return err
+	}
+	for _, apiLabel := range apiLabels {
+		apiLabel.BoardColumnID = boardIDs[apiLabel.ID]
+	}
+	return nil
+}
+
+// loadLabelBoardColumnsOfIssues sets the project board columns backed by the labels of the issues,
+// the boards of the labels of all issues are loaded at once
+func loadLabelBoardColumnsOfIssues(ctx context.Context, il issues_model.IssueList, apiIssues []*api.Issue) error {
+	apiLabels := make([]*api.Label, 0, len(il))
+	for i := range il {
+		if apiIssues[i].ID == 0 {
+			continue
+		}
+		apiLabels = append(apiLabels, apiIssues[i].Labels...)
+	}
+	return loadLabelBoardColumns(ctx, apiLabels)
+}
+
+// ToLabelTemplate converts labels into portable label definitions which can be imported into another repository.
+// The stored names are used even if emoji shortcodes are rendered in the API.
+func ToLabelTemplate(labels []*issues_model.Label) []*api.LabelTemplate {
+	result := make([]*api.LabelTemplate, len(labels))
+	for i, label := range labels {
+		result[i] = &api.LabelTemplate{
+			Name:        label.Name,
+			Color:       toLabelColor(label),
+			Description: label.Description,
+		}
 	}
 	return result
 }
@@ -224,12 +1762,145 @@ func ToAPIMilestone(m *issues_model.Milestone) *api.Milestone {
 		ClosedIssues: m.NumClosedIssues,
 		Created:      m.CreatedUnix.AsTime(),
 		Updated:      m.UpdatedUnix.AsTimePtr(),
+		SortKey:      milestoneSortKey(m),
 	}
 	if m.IsClosed {
 		apiMilestone.Closed = m.ClosedDateUnix.AsTimePtr()
 	}
 	if m.DeadlineUnix.Year() < 9999 {
 		apiMilestone.Deadline = m.DeadlineUnix.AsTimePtr()
+		apiMilestone.IsOverdue = !m.IsClosed && m.DeadlineUnix < timeutil.TimeStampNow()
 	}
 	return apiMilestone
 }
+
+// milestoneSortKey returns a key by which milestones sort lexically: open before closed ones, then by deadline, then by name.
+// The deadline is zero padded, milestones without a deadline use the year 9999 and thus sort last.
+func milestoneSortKey(m *issues_model.Milestone) string {
+	state := 0
+	if m.IsClosed {
+		state = 1
+	}
+	return fmt.Sprintf("%d-%020d-%s", state, m.DeadlineUnix, strings.ToLower(m.Name))
+}
+
+// ToAPIMilestoneWithLabelBreakdown converts Milestone into API Format including
+// the number of open and closed issues per label
+func ToAPIMilestoneWithLabelBreakdown(ctx context.Context, m *issues_model.Milestone) (*api.Milestone, error) {
+	counts, err := issues_model.CountMilestoneIssuesByLabel(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiMilestone := ToAPIMilestone(m)
+	apiMilestone.LabelBreakdown = make([]api.MilestoneLabelCount, 0, len(counts))
+	for _, c := range counts {
+		apiMilestone.LabelBreakdown = append(apiMilestone.LabelBreakdown, api.MilestoneLabelCount{
+			LabelID:      c.LabelID,
+			Name:         c.Name,
+			OpenIssues:   int(c.NumOpen),
+			ClosedIssues: int(c.NumClosed),
+		})
+	}
+	return apiMilestone, nil
+}
+
+// ToMilestoneAssigneeWorkload returns the number of open issues of the milestone per assignee, ordered by assignee ID.
+// The issues without an assignee are counted in an entry without assignee.
+func ToMilestoneAssigneeWorkload(ctx context.Context, m *issues_model.Milestone, doer *user_model.User) ([]api.AssigneeCount, error) {
+	counts, err := issues_model.CountMilestoneOpenIssuesByAssignee(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	assigneeIDs := make([]int64, 0, len(counts))
+	for _, c := range counts {
+		if c.AssigneeID != 0 {
+			assigneeIDs = append(assigneeIDs, c.AssigneeID)
+		}
+	}
+	assigneeMap, err := batchLoad(ctx, assigneeIDs, func(u *user_model.User) int64 { return u.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	workload := make([]api.AssigneeCount, 0, len(counts))
+	for _, c := range counts {
+		count := api.AssigneeCount{OpenIssues: int(c.NumOpen)}
+		if c.AssigneeID != 0 {
+			assignee, ok := assigneeMap[c.AssigneeID]
+			if !ok {
+				assignee = user_model.NewGhostUser()
+			}
+			count.Assignee = ToUser(assignee, doer)
+		}
+		workload = append(workload, count)
+	}
+	return workload, nil
+}
+
+// ToMilestonePullReadiness counts the open pull requests of the milestone by whether they can be merged, following
+// their cached mergeability, their title and the reviews of official reviewers.
+func ToMilestonePullReadiness(ctx context.Context, m *issues_model.Milestone) (*api.MilestonePullReadiness, error) {
+	states, err := issues_model.GetMilestoneOpenPullRequestStates(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	readiness := &api.MilestonePullReadiness{}
+	for _, state := range states {
+		switch {
+		case state.Status == issues_model.PullRequestStatusConflict:
+			readiness.Conflicted++
+		case state.Status == issues_model.PullRequestStatusChecking || state.Status == issues_model.PullRequestStatusError:
+			readiness.Checking++
+		case issues_model.HasWorkInProgressPrefix(state.Title):
+			readiness.WorkInProgress++
+		case state.ChangesRequested:
+			readiness.ChangesRequested++
+		default:
+			readiness.Mergeable++
+		}
+	}
+	return readiness, nil
+}
+
+// ToMilestoneBurndown converts Milestone into API Format together with the number of issues closed on each day
+// from its creation until its deadline, or until today if it has no deadline. The days follow the default
+// timezone of the UI, issues closed before the milestone was created are counted on its first day.
+func ToMilestoneBurndown(ctx context.Context, m *issues_model.Milestone) (*api.MilestoneBurndown, error) {
+	closeTimes, err := issues_model.GetMilestoneIssueCloseTimes(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := timeutil.TimeStampNow()
+	if m.DeadlineUnix.Year() < 9999 {
+		end = m.DeadlineUnix
+	}
+	startOfDay := func(ts timeutil.TimeStamp) time.Time {
+		t := ts.AsTimeInLocation(setting.DefaultUILocation)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, setting.DefaultUILocation)
+	}
+	lastDay := startOfDay(end)
+
+	burndown := &api.MilestoneBurndown{
+		Milestone: ToAPIMilestone(m),
+		Days:      make([]*api.MilestoneBurndownDay, 0, 30),
+	}
+	i, numClosed := 0, 0
+	for day := startOfDay(m.CreatedUnix); !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		nextDay := timeutil.TimeStamp(day.AddDate(0, 0, 1).Unix())
+		closedOnDay := 0
+		for ; i < len(closeTimes) && closeTimes[i] < nextDay; i++ {
+			closedOnDay++
+		}
+		numClosed += closedOnDay
+		burndown.Days = append(burndown.Days, &api.MilestoneBurndownDay{
+			Date:         day.Format("2006-01-02"),
+			ClosedIssues: closedOnDay,
+			OpenIssues:   m.NumIssues - numClosed,
+		})
+	}
+	return burndown, nil
+}
@@ -5,8 +5,10 @@ package convert
 
 import (
 	"context"
+	"time"
 
 	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
@@ -16,19 +18,44 @@ import (
 // ToComment converts a issues_model.Comment to the api.Comment format
 func ToComment(c *issues_model.Comment) *api.Comment {
 	return &api.Comment{
-		ID:       c.ID,
-		Poster:   ToUser(c.Poster, nil),
-		HTMLURL:  c.HTMLURL(),
-		IssueURL: c.IssueURL(),
-		PRURL:    c.PRURL(),
-		Body:     c.Content,
-		Created:  c.CreatedUnix.AsTime(),
-		Updated:  c.UpdatedUnix.AsTime(),
+		ID:               c.ID,
+		Poster:           ToPoster(c.Poster, nil),
+		OriginalAuthor:   c.OriginalAuthor,
+		OriginalAuthorID: c.OriginalAuthorID,
+		HTMLURL:          c.HTMLURL(),
+		IssueURL:         c.IssueURL(),
+		PRURL:            c.PRURL(),
+		Body:             c.Content,
+		Created:          c.CreatedUnix.AsTime(),
+		Updated:          c.UpdatedUnix.AsTime(),
+		SavedReplyID:     c.SavedReplyID,
 	}
 }
 
 // ToTimelineComment converts a issues_model.Comment to the api.TimelineComment format
 func ToTimelineComment(ctx context.Context, c *issues_model.Comment, doer *user_model.User) *api.TimelineComment {
+	return ToTimelineCommentList(ctx, []*issues_model.Comment{c}, doer)[0]
+}
+
+// ToTimelineCommentList converts comments to the api.TimelineComment format like ToTimelineComment,
+// the board columns backed by the labels of all comments are loaded at once.
+// Comments which fail to convert are nil in the result.
+func ToTimelineCommentList(ctx context.Context, comments []*issues_model.Comment, doer *user_model.User) []*api.TimelineComment {
+	result := make([]*api.TimelineComment, len(comments))
+	apiLabels := make([]*api.Label, 0, len(comments))
+	for i, c := range comments {
+		result[i] = toTimelineComment(ctx, c, doer)
+		if result[i] != nil && result[i].Label != nil {
+			apiLabels = append(apiLabels, result[i].Label)
+		}
+	}
+	if err := loadLabelBoardColumns(ctx, apiLabels); err != nil {
+		log.Error("loadLabelBoardColumns: %v", err)
+	}
+	return result
+}
+
+func toTimelineComment(ctx context.Context, c *issues_model.Comment, doer *user_model.User) *api.TimelineComment {
 	err := c.LoadMilestone(ctx)
 	if err != nil {
 		log.Error("LoadMilestone: %v", err)
@@ -68,7 +95,7 @@ func ToTimelineComment(ctx context.Context, c *issues_model.Comment, doer *user_
 	comment := &api.TimelineComment{
 		ID:       c.ID,
 		Type:     c.Type.String(),
-		Poster:   ToUser(c.Poster, nil),
+		Poster:   ToPoster(c.Poster, nil),
 		HTMLURL:  c.HTMLURL(),
 		IssueURL: c.IssueURL(),
 		PRURL:    c.PRURL(),
@@ -152,7 +179,7 @@ func ToTimelineComment(ctx context.Context, c *issues_model.Comment, doer *user_
 				return nil
 			}
 		}
-		comment.Label = ToLabel(c.Label, repo, org)
+		comment.Label = toLabel(c.Label, repo, org)
 	}
 
 	if c.Assignee != nil {
@@ -172,3 +199,62 @@ func ToTimelineComment(ctx context.Context, c *issues_model.Comment, doer *user_
 
 	return comment
 }
+
+// ToAPIIssueTimeline converts an issue together with its comments and events, ordered by creation, as seen by the viewer.
+// References from other repositories are left out unless the viewer can read the issues or pulls there.
+func ToAPIIssueTimeline(ctx context.Context, issue *issues_model.Issue, viewer *user_model.User) (*api.IssueTimeline, error) {
+	comments, err := issues_model.FindComments(ctx, &issues_model.FindCommentsOptions{
+		IssueID: issue.ID,
+		Type:    issues_model.CommentTypeUnknown,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := issues_model.CommentList(comments).LoadPosters(ctx); err != nil {
+		return nil, err
+	}
+
+	perms := make(map[int64]*access_model.Permission)
+	visible := make([]*issues_model.Comment, 0, len(comments))
+	for _, c := range comments {
+		// code comments are part of their review
+		if c.Type == issues_model.CommentTypeCode {
+			continue
+		}
+		if issues_model.CommentTypeIsRef(c.Type) && c.RefRepoID != issue.RepoID && c.RefRepoID != 0 {
+			perm, ok := perms[c.RefRepoID]
+			if !ok {
+				refRepo, err := repo_model.GetRepositoryByIDCtx(ctx, c.RefRepoID)
+				if err != nil && !repo_model.IsErrRepoNotExist(err) {
+					return nil, err
+				}
+				if refRepo != nil {
+					p, err := access_model.GetUserRepoPermission(ctx, refRepo, viewer)
+					if err != nil {
+						return nil, err
+					}
+					perm = &p
+				}
+				perms[c.RefRepoID] = perm
+			}
+			if perm == nil || !perm.CanReadIssuesOrPulls(c.RefIsPull) {
+				continue
+			}
+		}
+
+		c.Issue = issue
+		visible = append(visible, c)
+	}
+
+	events := make([]*api.TimelineComment, 0, len(visible))
+	for _, event := range ToTimelineCommentList(ctx, visible, viewer) {
+		if event != nil {
+			events = append(events, event)
+		}
+	}
+
+	return &api.IssueTimeline{
+		Issue:  ToAPIIssueForViewer(ctx, issue, viewer, time.Time{}),
+		Events: events,
+	}, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAPIIssueTimeline(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	// issue 1 already has a label event and two comments
+	for i, c := range []*issues_model.Comment{
+		{Type: issues_model.CommentTypeAssignees, AssigneeID: 1},
+		{Type: issues_model.CommentTypeMilestone, MilestoneID: 1},
+		{Type: issues_model.CommentTypeClose},
+		// referenced from issue 4 in the private repo2
+		{Type: issues_model.CommentTypeIssueRef, RefRepoID: 2, RefIssueID: 4},
+		{Type: issues_model.CommentTypeReopen},
+	} {
+		c.PosterID = 2
+		c.IssueID = issue.ID
+		c.CreatedUnix = timeutil.TimeStamp(946684813 + i)
+		c.UpdatedUnix = c.CreatedUnix
+		_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(c)
+		assert.NoError(t, err)
+	}
+
+	eventTypes := func(viewer *user_model.User) []string {
+		timeline, err := ToAPIIssueTimeline(db.DefaultContext, issue, viewer)
+		assert.NoError(t, err)
+		assert.EqualValues(t, issue.ID, timeline.Issue.ID)
+		types := make([]string, 0, len(timeline.Events))
+		for _, event := range timeline.Events {
+			types = append(types, event.Type)
+		}
+		return types
+	}
+
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.Equal(t, []string{"label", "comment", "comment", "assignees", "milestone", "close", "issue_ref", "reopen"}, eventTypes(owner))
+
+	other := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	assert.Equal(t, []string{"label", "comment", "comment", "assignees", "milestone", "close", "reopen"}, eventTypes(other))
+	assert.Equal(t, []string{"label", "comment", "comment", "assignees", "milestone", "close", "reopen"}, eventTypes(nil))
+}
+
+func TestToComment_SavedReply(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NoError(t, issue.LoadRepo(db.DefaultContext))
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	create := func(savedReplyID int64) *issues_model.Comment {
+		comment, err := issues_model.CreateComment(&issues_model.CreateCommentOptions{
+			Type:         issues_model.CommentTypeComment,
+			Doer:         doer,
+			Repo:         issue.Repo,
+			Issue:        issue,
+			Content:      "Thanks for the report!",
+			SavedReplyID: savedReplyID,
+		})
+		assert.NoError(t, err)
+		comment, err = issues_model.GetCommentByID(db.DefaultContext, comment.ID)
+		assert.NoError(t, err)
+		assert.NoError(t, comment.LoadPoster(db.DefaultContext))
+		return comment
+	}
+
+	// the origin is stored with the comment
+	assert.EqualValues(t, 7, ToComment(create(7)).SavedReplyID)
+	assert.Zero(t, ToComment(create(0)).SavedReplyID)
+}
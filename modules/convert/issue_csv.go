@@ -0,0 +1,148 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// IssueCSVColumn is a column of the CSV export of issues
+type IssueCSVColumn string
+
+// The columns of the CSV export of issues
+const (
+	IssueCSVColumnIndex     IssueCSVColumn = "index"
+	IssueCSVColumnTitle     IssueCSVColumn = "title"
+	IssueCSVColumnState     IssueCSVColumn = "state"
+	IssueCSVColumnLabels    IssueCSVColumn = "labels"
+	IssueCSVColumnAssignees IssueCSVColumn = "assignees"
+	IssueCSVColumnMilestone IssueCSVColumn = "milestone"
+	IssueCSVColumnCreated   IssueCSVColumn = "created"
+	IssueCSVColumnUpdated   IssueCSVColumn = "updated"
+)
+
+// IssueCSVColumns are all columns of the CSV export of issues in their default order
+var IssueCSVColumns = []IssueCSVColumn{
+	IssueCSVColumnIndex,
+	IssueCSVColumnTitle,
+	IssueCSVColumnState,
+	IssueCSVColumnLabels,
+	IssueCSVColumnAssignees,
+	IssueCSVColumnMilestone,
+	IssueCSVColumnCreated,
+	IssueCSVColumnUpdated,
+}
+
+// IssuesCSVOptions are the options of the CSV export of issues
+type IssuesCSVOptions struct {
+	// the columns to write in this order, all columns if empty
+	Columns []IssueCSVColumn
+	// the separator of the labels and assignees within their column, ", " if empty
+	Separator string
+}
+
+// ToIssuesCSV writes the issues as CSV according to RFC 4180 to w, starting with a header row of the column names.
+// The issues are converted with ToAPIIssueListBatched, labels are written by name and assignees by login.
+// Values which a spreadsheet would evaluate as a formula are prefixed with a single quote.
+func ToIssuesCSV(ctx context.Context, w io.Writer, il issues_model.IssueList, doer *user_model.User, opts IssuesCSVOptions) error {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = IssueCSVColumns
+	}
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !isIssueCSVColumn(column) {
+			return util.SilentWrap{Message: fmt.Sprintf("unknown issue CSV column %q", column), Err: util.ErrInvalidArgument}
+		}
+		header = append(header, string(column))
+	}
+	separator := opts.Separator
+	if separator == "" {
+		separator = ", "
+	}
+
+	csvWriter := csv.NewWriter(w)
+	csvWriter.UseCRLF = true
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	if err := ToAPIIssueListBatched(ctx, il, doer, func(apiIssues []*api.Issue) error {
+		for _, apiIssue := range apiIssues {
+			record := make([]string, 0, len(columns))
+			for _, column := range columns {
+				record = append(record, escapeCSVFormula(issueCSVValue(apiIssue, column, separator)))
+			}
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func isIssueCSVColumn(column IssueCSVColumn) bool {
+	for _, c := range IssueCSVColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeCSVFormula prefixes values starting with a formula character with a single quote,
+// so that spreadsheets show them as text instead of evaluating them
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func issueCSVValue(apiIssue *api.Issue, column IssueCSVColumn, separator string) string {
+	switch column {
+	case IssueCSVColumnIndex:
+		return strconv.FormatInt(apiIssue.Index, 10)
+	case IssueCSVColumnTitle:
+		return apiIssue.Title
+	case IssueCSVColumnState:
+		return string(apiIssue.State)
+	case IssueCSVColumnLabels:
+		names := make([]string, 0, len(apiIssue.Labels))
+		for _, label := range apiIssue.Labels {
+			names = append(names, label.RawName)
+		}
+		return strings.Join(names, separator)
+	case IssueCSVColumnAssignees:
+		logins := make([]string, 0, len(apiIssue.Assignees))
+		for _, assignee := range apiIssue.Assignees {
+			logins = append(logins, assignee.UserName)
+		}
+		return strings.Join(logins, separator)
+	case IssueCSVColumnMilestone:
+		if apiIssue.Milestone == nil {
+			return ""
+		}
+		return apiIssue.Milestone.Title
+	case IssueCSVColumnCreated:
+		return apiIssue.Created.Format(time.RFC3339)
+	case IssueCSVColumnUpdated:
+		return apiIssue.Updated.Format(time.RFC3339)
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToIssuesCSV(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// issue 1 carries label1 and is assigned to user1, issue 2 carries label1 and orglabel4 and is in milestone1
+	issue1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	issue1.Title = "a title, with \"quotes\"\nand a second line"
+	issue2 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	il := issues_model.IssueList{issue1, issue2}
+
+	var buf bytes.Buffer
+	assert.NoError(t, ToIssuesCSV(db.DefaultContext, &buf, il, nil, IssuesCSVOptions{}))
+	assert.True(t, strings.HasSuffix(buf.String(), "\r\n"))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"index", "title", "state", "labels", "assignees", "milestone", "created", "updated"},
+		{
+			"1", issue1.Title, "open", "label1", "user1", "",
+			issue1.CreatedUnix.AsTime().Format(time.RFC3339), issue1.UpdatedUnix.AsTime().Format(time.RFC3339),
+		},
+		{
+			"2", "issue2", "open", "label1, orglabel4", "", "milestone1",
+			issue2.CreatedUnix.AsTime().Format(time.RFC3339), issue2.UpdatedUnix.AsTime().Format(time.RFC3339),
+		},
+	}, records)
+
+	buf.Reset()
+	assert.NoError(t, ToIssuesCSV(db.DefaultContext, &buf, il, nil, IssuesCSVOptions{
+		Columns:   []IssueCSVColumn{IssueCSVColumnLabels, IssueCSVColumnIndex},
+		Separator: "|",
+	}))
+	records, err = csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"labels", "index"}, {"label1", "1"}, {"label1|orglabel4", "2"}}, records)
+
+	// values which a spreadsheet would evaluate as a formula are escaped
+	buf.Reset()
+	issue1.Title = "=HYPERLINK(\"https://example.com\")"
+	issue2.Title = "-1+1"
+	assert.NoError(t, ToIssuesCSV(db.DefaultContext, &buf, il, nil, IssuesCSVOptions{
+		Columns:   []IssueCSVColumn{IssueCSVColumnTitle, IssueCSVColumnLabels},
+		Separator: "@",
+	}))
+	records, err = csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"title", "labels"},
+		{"'=HYPERLINK(\"https://example.com\")", "label1"},
+		{"'-1+1", "label1@orglabel4"},
+	}, records)
+	assert.Equal(t, "'+a", escapeCSVFormula("+a"))
+	assert.Equal(t, "'@a", escapeCSVFormula("@a"))
+	assert.Equal(t, "a=b", escapeCSVFormula("a=b"))
+
+	err = ToIssuesCSV(db.DefaultContext, &buf, il, nil, IssuesCSVOptions{Columns: []IssueCSVColumn{"body"}})
+	assert.True(t, errors.Is(err, util.ErrInvalidArgument))
+}
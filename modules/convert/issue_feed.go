@@ -0,0 +1,55 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"fmt"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+
+	"github.com/gorilla/feeds"
+)
+
+// ToFeedItem converts an issue into an item of an Atom or RSS feed, whose description is the body rendered as sanitized HTML.
+// The visibility of the issue is not checked, callers have to leave out the issues of repositories the reader cannot see.
+func ToFeedItem(ctx context.Context, issue *issues_model.Issue) (*feeds.Item, error) {
+	if err := issue.LoadRepo(ctx); err != nil {
+		return nil, fmt.Errorf("LoadRepo: %w", err)
+	}
+	if err := issue.LoadPoster(ctx); err != nil {
+		return nil, fmt.Errorf("LoadPoster: %w", err)
+	}
+
+	description, err := markdown.RenderString(&markup.RenderContext{
+		Ctx:       ctx,
+		URLPrefix: issue.Repo.Link(),
+		Metas:     issue.Repo.ComposeMetas(),
+	}, issue.Content)
+	if err != nil {
+		return nil, fmt.Errorf("RenderString: %w", err)
+	}
+
+	author := &feeds.Author{
+		Name:  issue.Poster.DisplayName(),
+		Email: issue.Poster.GetEmail(),
+	}
+	// issues migrated from other services are attributed to their original author
+	if issue.OriginalAuthor != "" {
+		author = &feeds.Author{Name: issue.OriginalAuthor}
+	}
+
+	link := issue.HTMLURL()
+	return &feeds.Item{
+		Id:          link,
+		Title:       issue.Title,
+		Link:        &feeds.Link{Href: link},
+		Author:      author,
+		Description: description,
+		Created:     issue.CreatedUnix.AsTime(),
+		Updated:     issue.UpdatedUnix.AsTime(),
+	}, nil
+}
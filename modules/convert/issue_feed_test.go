@@ -0,0 +1,43 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFeedItem(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	issue.Content = "content for the **first** issue<script>alert(1)</script>"
+	item, err := ToFeedItem(db.DefaultContext, issue)
+	assert.NoError(t, err)
+
+	link := setting.AppURL + "user2/repo1/issues/1"
+	assert.Equal(t, link, item.Id)
+	assert.Equal(t, "issue1", item.Title)
+	assert.Equal(t, link, item.Link.Href)
+	assert.Equal(t, "User One", item.Author.Name)
+	assert.Equal(t, "user1@example.com", item.Author.Email)
+	assert.Equal(t, time.Unix(946684800, 0), item.Created)
+	assert.Equal(t, time.Unix(978307200, 0), item.Updated)
+	assert.Equal(t, "<p>content for the <strong>first</strong> issue</p>\n", item.Description)
+
+	// migrated issues are attributed to their original author
+	issue.OriginalAuthor = "someone"
+	issue.OriginalAuthorID = 123
+	item, err = ToFeedItem(db.DefaultContext, issue)
+	assert.NoError(t, err)
+	assert.Equal(t, "someone", item.Author.Name)
+	assert.Empty(t, item.Author.Email)
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"sort"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// MaxIssueGraphDepth is the number of hops ToIssueGraph follows the relations of an issue at most
+const MaxIssueGraphDepth = 5
+
+// ToIssueGraph converts the references, dependencies and duplicates around an issue into a graph, following
+// them up to depth hops in both directions. Issues the viewer cannot see and the edges to them are left out,
+// the given issue itself included. Every issue and every edge is contained once, however many paths lead to it.
+func ToIssueGraph(ctx context.Context, issue *issues_model.Issue, depth int, viewer *user_model.User) (*api.IssueGraph, error) {
+	if depth > MaxIssueGraphDepth {
+		depth = MaxIssueGraphDepth
+	}
+
+	g := &issueGraphBuilder{
+		ctx:    ctx,
+		viewer: viewer,
+		perms:  make(map[int64]access_model.Permission),
+		nodes:  make(map[int64]*issues_model.Issue),
+		hidden: make(map[int64]bool),
+		edges:  make(map[api.IssueGraphEdge]bool),
+	}
+
+	if err := issue.LoadRepo(ctx); err != nil {
+		return nil, ErrLoadFailed{Field: "repo", Err: err}
+	}
+	visible, err := g.canRead(issue, issue.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return &api.IssueGraph{Nodes: []*api.Issue{}, Edges: []*api.IssueGraphEdge{}}, nil
+	}
+	g.addNode(issue)
+
+	// every level only expands the issues added by the previous one, so cycles end the traversal
+	frontier := issues_model.IssueList{issue}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		if frontier, err = g.expand(frontier); err != nil {
+			return nil, err
+		}
+	}
+
+	nodes, err := ToAPIIssueMinimalList(ctx, g.order)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*api.IssueGraphEdge, 0, len(g.edges))
+	for edge := range g.edges {
+		edge := edge
+		edges = append(edges, &edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		if edges[i].To != edges[j].To {
+			return edges[i].To < edges[j].To
+		}
+		return edges[i].Type < edges[j].Type
+	})
+	return &api.IssueGraph{Nodes: nodes, Edges: edges}, nil
+}
+
+type issueGraphBuilder struct {
+	ctx    context.Context
+	viewer *user_model.User
+	perms  map[int64]access_model.Permission
+	nodes  map[int64]*issues_model.Issue
+	order  issues_model.IssueList
+	// hidden contains the issues which the viewer cannot see or which do not exist anymore
+	hidden map[int64]bool
+	edges  map[api.IssueGraphEdge]bool
+}
+
+func (g *issueGraphBuilder) addNode(issue *issues_model.Issue) {
+	g.nodes[issue.ID] = issue
+	g.order = append(g.order, issue)
+}
+
+func (g *issueGraphBuilder) canRead(issue *issues_model.Issue, repo *repo_model.Repository) (bool, error) {
+	perm, ok := g.perms[repo.ID]
+	if !ok {
+		var err error
+		if perm, err = access_model.GetUserRepoPermission(g.ctx, repo, g.viewer); err != nil {
+			return false, err
+		}
+		g.perms[repo.ID] = perm
+	}
+	return perm.CanReadIssuesOrPulls(issue.IsPull), nil
+}
+
+// expand collects the relations of the given issues, adds the visible issues they lead to as nodes
+// and returns the ones which have not been part of the graph before.
+func (g *issueGraphBuilder) expand(frontier issues_model.IssueList) (issues_model.IssueList, error) {
+	issueIDs := make([]int64, 0, len(frontier))
+	for _, issue := range frontier {
+		issueIDs = append(issueIDs, issue.ID)
+	}
+
+	candidates := make([]api.IssueGraphEdge, 0, len(frontier))
+	for _, issue := range frontier {
+		if issue.DuplicateOfID != 0 {
+			candidates = append(candidates, api.IssueGraphEdge{From: issue.ID, To: issue.DuplicateOfID, Type: api.IssueGraphEdgeDuplicates})
+		}
+	}
+	duplicates, err := issues_model.GetDuplicatesOfIssues(g.ctx, issueIDs)
+	if err != nil {
+		return nil, ErrLoadFailed{Field: "duplicates", Err: err}
+	}
+	for _, duplicate := range duplicates {
+		candidates = append(candidates, api.IssueGraphEdge{From: duplicate.ID, To: duplicate.DuplicateOfID, Type: api.IssueGraphEdgeDuplicates})
+	}
+
+	deps, err := issues_model.GetIssueDependenciesByIssueIDs(g.ctx, issueIDs)
+	if err != nil {
+		return nil, ErrLoadFailed{Field: "dependencies", Err: err}
+	}
+	for _, dep := range deps {
+		candidates = append(candidates, api.IssueGraphEdge{From: dep.DependencyID, To: dep.IssueID, Type: api.IssueGraphEdgeBlocks})
+	}
+
+	sourcesMap, err := issues_model.GetCrossReferenceSources(g.ctx, issueIDs)
+	if err != nil {
+		return nil, ErrLoadFailed{Field: "references", Err: err}
+	}
+	targetsMap, err := issues_model.GetCrossReferenceTargets(g.ctx, issueIDs)
+	if err != nil {
+		return nil, ErrLoadFailed{Field: "references", Err: err}
+	}
+	for _, refsMap := range []map[int64][]*issues_model.CrossReferenceSource{sourcesMap, targetsMap} {
+		for _, refs := range refsMap {
+			for _, ref := range refs {
+				candidates = append(candidates, api.IssueGraphEdge{From: ref.RefIssueID, To: ref.IssueID, Type: api.IssueGraphEdgeReferences})
+			}
+		}
+	}
+
+	unknownIDs := make([]int64, 0, len(candidates))
+	for _, edge := range candidates {
+		for _, id := range []int64{edge.From, edge.To} {
+			if _, ok := g.nodes[id]; !ok && !g.hidden[id] {
+				unknownIDs = append(unknownIDs, id)
+			}
+		}
+	}
+	issues, err := batchLoad(g.ctx, unknownIDs, func(issue *issues_model.Issue) int64 { return issue.ID })
+	if err != nil {
+		return nil, ErrLoadFailed{Field: "issues", Err: err}
+	}
+	repoIDs := make([]int64, 0, len(issues))
+	for _, issue := range issues {
+		repoIDs = append(repoIDs, issue.RepoID)
+	}
+	repos, err := batchLoad(g.ctx, repoIDs, func(repo *repo_model.Repository) int64 { return repo.ID })
+	if err != nil {
+		return nil, ErrLoadFailed{Field: "repos", Err: err}
+	}
+
+	for _, id := range unknownIDs {
+		g.hidden[id] = true
+	}
+	added := make(issues_model.IssueList, 0, len(issues))
+	for _, issue := range issues {
+		repo, ok := repos[issue.RepoID]
+		if !ok {
+			continue
+		}
+		visible, err := g.canRead(issue, repo)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			issue.Repo = repo
+			delete(g.hidden, issue.ID)
+			added = append(added, issue)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool { return added[i].ID < added[j].ID })
+	for _, issue := range added {
+		g.addNode(issue)
+	}
+
+	for _, edge := range candidates {
+		if edge.From == edge.To || g.hidden[edge.From] || g.hidden[edge.To] {
+			continue
+		}
+		g.edges[edge] = true
+	}
+	return added, nil
+}
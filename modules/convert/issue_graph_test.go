@@ -0,0 +1,81 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToIssueGraph(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// 1 blocks 2 blocks 5 blocks 1, and 5 blocks 4 of the private repo2
+	for _, dep := range []*issues_model.IssueDependency{
+		{UserID: 1, IssueID: 2, DependencyID: 1},
+		{UserID: 1, IssueID: 5, DependencyID: 2},
+		{UserID: 1, IssueID: 1, DependencyID: 5},
+		{UserID: 1, IssueID: 4, DependencyID: 5},
+	} {
+		assert.NoError(t, db.Insert(db.DefaultContext, dep))
+	}
+	_, err := db.GetEngine(db.DefaultContext).ID(11).Cols("duplicate_of_id").Update(&issues_model.Issue{DuplicateOfID: 5})
+	assert.NoError(t, err)
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+
+	nodeIDs := func(graph *api.IssueGraph) []int64 {
+		ids := make([]int64, 0, len(graph.Nodes))
+		for _, node := range graph.Nodes {
+			ids = append(ids, node.ID)
+		}
+		return ids
+	}
+
+	graph, err := ToIssueGraph(db.DefaultContext, issue, 0, user4)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, nodeIDs(graph))
+	assert.Empty(t, graph.Edges)
+
+	graph, err = ToIssueGraph(db.DefaultContext, issue, 1, user4)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 5}, nodeIDs(graph))
+	assert.Equal(t, []*api.IssueGraphEdge{
+		{From: 1, To: 2, Type: api.IssueGraphEdgeBlocks},
+		{From: 5, To: 1, Type: api.IssueGraphEdgeBlocks},
+	}, graph.Edges)
+
+	// the cycle is only followed once and issue 4 is hidden from user4
+	graph, err = ToIssueGraph(db.DefaultContext, issue, MaxIssueGraphDepth+10, user4)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 5, 11}, nodeIDs(graph))
+	assert.Equal(t, "issue5", graph.Nodes[2].Title)
+	assert.Equal(t, []*api.IssueGraphEdge{
+		{From: 1, To: 2, Type: api.IssueGraphEdgeBlocks},
+		{From: 2, To: 5, Type: api.IssueGraphEdgeBlocks},
+		{From: 5, To: 1, Type: api.IssueGraphEdgeBlocks},
+		{From: 11, To: 5, Type: api.IssueGraphEdgeDuplicates},
+	}, graph.Edges)
+
+	graph, err = ToIssueGraph(db.DefaultContext, issue, 2, admin)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 5, 4, 11}, nodeIDs(graph))
+	assert.Contains(t, graph.Edges, &api.IssueGraphEdge{From: 5, To: 4, Type: api.IssueGraphEdgeBlocks})
+
+	// nothing is revealed about an issue the viewer cannot see
+	private := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 4})
+	graph, err = ToIssueGraph(db.DefaultContext, private, 2, user4)
+	assert.NoError(t, err)
+	assert.Empty(t, graph.Nodes)
+	assert.Empty(t, graph.Edges)
+}
@@ -0,0 +1,29 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToReaction converts a Reaction to API format, its user is converted as seen by the viewer.
+// The user of the reaction has to be loaded, e.g. by ReactionList.LoadUsers.
+func ToReaction(r *issues_model.Reaction, viewer *user_model.User) *api.Reaction {
+	return &api.Reaction{
+		User:     ToUser(r.User, viewer),
+		Reaction: r.Type,
+		Created:  r.CreatedUnix.AsTime(),
+	}
+}
+
+// ToReactionList converts a ReactionList to API format like ToReaction
+func ToReactionList(list issues_model.ReactionList, viewer *user_model.User) []*api.Reaction {
+	result := make([]*api.Reaction, len(list))
+	for i := range list {
+		result[i] = ToReaction(list[i], viewer)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToReactionList(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	// user2 keeps their email private, user1 does not
+	list := issues_model.ReactionList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Reaction{ID: 3}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Reaction{ID: 2}),
+	}
+	_, err := list.LoadUsers(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1}))
+	assert.NoError(t, err)
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	for _, c := range []struct {
+		viewerID int64
+		email    string
+	}{
+		{2, user2.Email},
+		{1, user2.Email}, // site admin
+		{4, user2.GetEmail()},
+		{0, user2.GetEmail()},
+	} {
+		var viewer *user_model.User
+		if c.viewerID != 0 {
+			viewer = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: c.viewerID})
+		}
+		reactions := ToReactionList(list, viewer)
+		if assert.Len(t, reactions, 2) {
+			assert.Equal(t, "eyes", reactions[0].Reaction)
+			assert.Equal(t, list[0].CreatedUnix.AsTime(), reactions[0].Created)
+			assert.Equal(t, "user2", reactions[0].User.UserName)
+			assert.Equal(t, c.email, reactions[0].User.Email, "viewer %d", c.viewerID)
+			assert.Equal(t, "zzz", reactions[1].Reaction)
+			assert.Equal(t, "user1", reactions[1].User.UserName)
+		}
+	}
+	assert.NotEqual(t, user2.Email, user2.GetEmail())
+
+	assert.Empty(t, ToReactionList(nil, nil))
+}
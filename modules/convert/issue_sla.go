@@ -0,0 +1,80 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// SLAPolicy is a service level agreement on the handling of issues, targets which are 0 do not apply
+type SLAPolicy struct {
+	// time from the creation of an issue until somebody else than the poster responds to it
+	FirstResponse time.Duration
+	// time from the creation of an issue until it is closed
+	Resolution time.Duration
+}
+
+// ToAPIIssueWithSLA converts an Issue to API format like ToAPIIssue and additionally reports whether it missed
+// a target of the policy and when the earliest pending target is due. Responses are the comments and reviews
+// counted for seconds_since_last_response, closing an issue meets both targets.
+func ToAPIIssueWithSLA(ctx context.Context, issue *issues_model.Issue, policy SLAPolicy) *api.Issue {
+	apiIssue := ToAPIIssue(ctx, issue)
+	if apiIssue.ID == 0 {
+		return apiIssue
+	}
+	if err := setSLA(ctx, apiIssue, issue, policy); err != nil {
+		log.Error("setSLA[%d]: %v", issue.ID, err)
+	}
+	return apiIssue
+}
+
+func setSLA(ctx context.Context, apiIssue *api.Issue, issue *issues_model.Issue, policy SLAPolicy) error {
+	now := timeutil.TimeStampNow()
+	var resolved timeutil.TimeStamp
+	if issue.IsClosed {
+		resolved = issue.ClosedUnix
+	}
+
+	var breached bool
+	var dueAt timeutil.TimeStamp
+	// checkTarget checks a target which has been met at the given time, 0 if it is still pending
+	checkTarget := func(target time.Duration, metAt timeutil.TimeStamp) {
+		due := issue.CreatedUnix.AddDuration(target)
+		if metAt != 0 {
+			breached = breached || metAt > due
+			return
+		}
+		breached = breached || now > due
+		if dueAt == 0 || due < dueAt {
+			dueAt = due
+		}
+	}
+
+	if policy.FirstResponse > 0 {
+		responseTimes, err := issues_model.GetFirstResponseTimesByIssueIDs(ctx, []int64{issue.ID})
+		if err != nil {
+			return err
+		}
+		responded := responseTimes[issue.ID]
+		if resolved != 0 && (responded == 0 || resolved < responded) {
+			responded = resolved
+		}
+		checkTarget(policy.FirstResponse, responded)
+	}
+	if policy.Resolution > 0 {
+		checkTarget(policy.Resolution, resolved)
+	}
+
+	apiIssue.SLABreached = breached
+	if dueAt != 0 {
+		apiIssue.SLADueAt = dueAt.AsTimePtr()
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAPIIssueWithSLA(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// issue 1 is open and user5 responded 12 seconds after its creation
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	created := issue.CreatedUnix.AsTime()
+	timeutil.Set(created.Add(2 * time.Hour))
+	defer timeutil.Unset()
+
+	apiIssue := ToAPIIssueWithSLA(db.DefaultContext, issue, SLAPolicy{})
+	assert.False(t, apiIssue.SLABreached)
+	assert.Nil(t, apiIssue.SLADueAt)
+
+	// responded in time and the resolution is pending
+	apiIssue = ToAPIIssueWithSLA(db.DefaultContext, issue, SLAPolicy{FirstResponse: time.Minute, Resolution: 24 * time.Hour})
+	assert.False(t, apiIssue.SLABreached)
+	if assert.NotNil(t, apiIssue.SLADueAt) {
+		assert.Equal(t, created.Add(24*time.Hour).Unix(), apiIssue.SLADueAt.Unix())
+	}
+
+	// the resolution is overdue
+	apiIssue = ToAPIIssueWithSLA(db.DefaultContext, issue, SLAPolicy{FirstResponse: time.Minute, Resolution: time.Hour})
+	assert.True(t, apiIssue.SLABreached)
+	if assert.NotNil(t, apiIssue.SLADueAt) {
+		assert.Equal(t, created.Add(time.Hour).Unix(), apiIssue.SLADueAt.Unix())
+	}
+
+	// responded too late
+	apiIssue = ToAPIIssueWithSLA(db.DefaultContext, issue, SLAPolicy{FirstResponse: 5 * time.Second})
+	assert.True(t, apiIssue.SLABreached)
+	assert.Nil(t, apiIssue.SLADueAt)
+
+	// issue 5 has no response, closing it in time meets both targets
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5})
+	issue.ClosedUnix = issue.CreatedUnix.AddDuration(30 * time.Minute)
+	apiIssue = ToAPIIssueWithSLA(db.DefaultContext, issue, SLAPolicy{FirstResponse: time.Hour, Resolution: time.Hour})
+	assert.False(t, apiIssue.SLABreached)
+	assert.Nil(t, apiIssue.SLADueAt)
+
+	issue.ClosedUnix = issue.CreatedUnix.AddDuration(90 * time.Minute)
+	apiIssue = ToAPIIssueWithSLA(db.DefaultContext, issue, SLAPolicy{Resolution: time.Hour})
+	assert.True(t, apiIssue.SLABreached)
+	assert.Nil(t, apiIssue.SLADueAt)
+}
@@ -4,16 +4,33 @@
 package convert
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/perm"
+	project_model "code.gitea.io/gitea/models/project"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/references"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -23,11 +40,213 @@ func TestLabel_ToLabel(t *testing.T) {
 	label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
 	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: label.RepoID})
 	assert.Equal(t, &api.Label{
-		ID:    label.ID,
-		Name:  label.Name,
-		Color: "abcdef",
-		URL:   fmt.Sprintf("%sapi/v1/repos/user2/repo1/labels/%d", setting.AppURL, label.ID),
-	}, ToLabel(label, repo, nil))
+		ID:        label.ID,
+		Name:      label.Name,
+		RawName:   label.Name,
+		Color:     "abcdef",
+		TextColor: "000000",
+		URL:       fmt.Sprintf("%sapi/v1/repos/user2/repo1/labels/%d", setting.AppURL, label.ID),
+	}, ToLabel(db.DefaultContext, label, repo, nil))
+}
+
+func TestToLabelListWithLastUsed(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	unused := &issues_model.Label{RepoID: repo.ID, Name: "unused", Color: "#000000"}
+	assert.NoError(t, issues_model.NewLabel(db.DefaultContext, unused))
+	labels := []*issues_model.Label{
+		// on issues 1 and 2, the latest update is the one of issue 1
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1}),
+		// on issue 5
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 2}),
+		unused,
+	}
+
+	apiLabels, err := ToLabelListWithOptions(db.DefaultContext, labels, repo, nil, LabelListOptions{LastUsed: true})
+	assert.NoError(t, err)
+	if assert.Len(t, apiLabels, 3) {
+		assert.EqualValues(t, 978307200, apiLabels[0].LastUsedUnix)
+		assert.EqualValues(t, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5}).UpdatedUnix, apiLabels[1].LastUsedUnix)
+		assert.Zero(t, apiLabels[2].LastUsedUnix)
+	}
+
+	// not set unless requested
+	assert.Zero(t, ToLabelList(db.DefaultContext, labels, repo, nil)[0].LastUsedUnix)
+
+	// issues of private repositories only count for users who can read them
+	_, err = db.GetEngine(db.DefaultContext).ID(repo.ID).Cols("is_private").Update(&repo_model.Repository{IsPrivate: true})
+	assert.NoError(t, err)
+	apiLabels, err = ToLabelListWithOptions(db.DefaultContext, labels, repo, nil, LabelListOptions{LastUsed: true})
+	assert.NoError(t, err)
+	assert.Zero(t, apiLabels[0].LastUsedUnix)
+	apiLabels, err = ToLabelListWithOptions(db.DefaultContext, labels, repo, nil, LabelListOptions{LastUsed: true, Viewer: unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})})
+	assert.NoError(t, err)
+	assert.Zero(t, apiLabels[0].LastUsedUnix)
+	apiLabels, err = ToLabelListWithOptions(db.DefaultContext, labels, repo, nil, LabelListOptions{LastUsed: true, Viewer: unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 978307200, apiLabels[0].LastUsedUnix)
+}
+
+func TestLabel_ToLabelIsDefault(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	for _, c := range []struct {
+		name      string
+		color     string
+		isDefault bool
+	}{
+		{"bug", "#ee0701", true},
+		{"Help Wanted", "#128a0c", true},
+		{"bug", "#EE0701", true},
+		{"bug", "#000000", false},
+		{"custom", "#ee0701", false},
+	} {
+		label := &issues_model.Label{ID: 1, RepoID: repo.ID, Name: c.name, Color: c.color}
+		assert.Equal(t, c.isDefault, ToLabel(db.DefaultContext, label, repo, nil).IsDefault, "%s %s", c.name, c.color)
+	}
+}
+
+func TestLabel_ToLabelBoardColumnID(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	bound := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+	unbound := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 2})
+
+	_, err := db.GetEngine(db.DefaultContext).ID(2).Cols("label_id").Update(&project_model.Board{LabelID: bound.ID})
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 2, ToLabel(db.DefaultContext, bound, repo, nil).BoardColumnID)
+	assert.Zero(t, ToLabel(db.DefaultContext, unbound, repo, nil).BoardColumnID)
+
+	apiLabels := ToLabelList(db.DefaultContext, []*issues_model.Label{bound, unbound}, repo, nil)
+	assert.EqualValues(t, 2, apiLabels[0].BoardColumnID)
+	assert.Zero(t, apiLabels[1].BoardColumnID)
+
+	// issue 1 carries the bound label
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues_model.IssueList{unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})}, nil)
+	if assert.Len(t, apiIssues[0].Labels, 1) {
+		assert.EqualValues(t, 2, apiIssues[0].Labels[0].BoardColumnID)
+	}
+}
+
+func TestLabel_ToLabelWithOptions_RenderDescription(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+	label.Description = "See [the **guide**](https://example.com/guide) <script>alert(1)</script>"
+
+	assert.Empty(t, ToLabel(db.DefaultContext, label, repo, nil).RenderedDescription)
+
+	apiLabel, err := ToLabelWithOptions(db.DefaultContext, label, repo, nil, LabelListOptions{RenderDescription: true})
+	assert.NoError(t, err)
+	assert.Equal(t, label.Description, apiLabel.Description)
+	assert.Equal(t, `<p>See <a href="https://example.com/guide" rel="nofollow">the <strong>guide</strong></a> </p>`, apiLabel.RenderedDescription)
+
+	// labels without a description are not rendered
+	label.Description = ""
+	apiLabel, err = ToLabelWithOptions(db.DefaultContext, label, repo, nil, LabelListOptions{RenderDescription: true})
+	assert.NoError(t, err)
+	assert.Empty(t, apiLabel.RenderedDescription)
+}
+
+func TestLabel_ToLabelTextColor(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	for _, c := range []struct {
+		color     string
+		textColor string
+	}{
+		{"#ffffff", "000000"},
+		{"#fef2c0", "000000"},
+		{"#abcdef", "000000"},
+		{"#fff", "000000"},
+		{"#000000", "ffffff"},
+		{"#2c3e50", "ffffff"},
+		{"#b60205", "ffffff"},
+		{"#00f", "ffffff"},
+		{"00aabb", "000000"},
+		{"", "000000"},
+		{"#12345", "000000"},
+		{"#zzzzzz", "000000"},
+	} {
+		label := &issues_model.Label{ID: 1, RepoID: repo.ID, Color: c.color}
+		assert.Equal(t, c.textColor, ToLabel(db.DefaultContext, label, repo, nil).TextColor, "color %q", c.color)
+	}
+}
+
+func TestLabel_ToLabelEmoji(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	defer func(enabled bool) { setting.API.RenderLabelEmoji = enabled }(setting.API.RenderLabelEmoji)
+
+	for _, c := range []struct {
+		name     string
+		rendered string
+	}{
+		{":rocket:", "\U0001f680"},
+		{"kind/:bug: bug", "kind/\U0001f41b bug"},
+		{":rocket: launch :unknown_shortcode:", "\U0001f680 launch :unknown_shortcode:"},
+		{"plain", "plain"},
+	} {
+		label := &issues_model.Label{ID: 1, RepoID: repo.ID, Name: c.name, Color: "#abcdef"}
+
+		setting.API.RenderLabelEmoji = false
+		apiLabel := ToLabel(db.DefaultContext, label, repo, nil)
+		assert.Equal(t, c.name, apiLabel.Name)
+		assert.Equal(t, c.name, apiLabel.RawName)
+
+		setting.API.RenderLabelEmoji = true
+		apiLabel = ToLabel(db.DefaultContext, label, repo, nil)
+		assert.Equal(t, c.rendered, apiLabel.Name)
+		assert.Equal(t, c.name, apiLabel.RawName)
+		assert.Equal(t, c.name, label.Name)
+	}
+}
+
+func TestLabel_ToLabelOrgLabel(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 3})
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: label.OrgID})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+
+	apiLabel := ToLabel(db.DefaultContext, label, repo, org)
+	assert.EqualValues(t, org.ID, apiLabel.OrgID)
+	// org labels keep pointing to the org even when converted in a repo context
+	assert.Equal(t, fmt.Sprintf("%sapi/v1/orgs/%s/labels/%d", setting.AppURL, org.Name, label.ID), apiLabel.URL)
+}
+
+func TestLabel_ToLabelListWithOptions(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	labels := []*issues_model.Label{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 2}),
+	}
+
+	apiLabels, err := ToLabelListWithOptions(db.DefaultContext, labels, repo, nil, LabelListOptions{IssueCounts: true})
+	assert.NoError(t, err)
+	if assert.Len(t, apiLabels, 2) {
+		assert.EqualValues(t, 2, apiLabels[0].OpenIssuesCount)
+		assert.EqualValues(t, 0, apiLabels[0].ClosedIssuesCount)
+		assert.EqualValues(t, 0, apiLabels[1].OpenIssuesCount)
+		assert.EqualValues(t, 1, apiLabels[1].ClosedIssuesCount)
+	}
+
+	apiLabel, err := ToLabelWithOptions(db.DefaultContext, labels[0], repo, nil, LabelListOptions{IssueCounts: true})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, apiLabel.OpenIssuesCount)
+
+	// the options can be combined
+	apiLabel, err = ToLabelWithOptions(db.DefaultContext, labels[0], repo, nil, LabelListOptions{LastUsed: true, IssueCounts: true})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, apiLabel.OpenIssuesCount)
+	assert.NotZero(t, apiLabel.LastUsedUnix)
+
+	// counts are not part of the plain conversion
+	assert.Zero(t, ToLabel(db.DefaultContext, labels[0], repo, nil).OpenIssuesCount)
 }
 
 func TestMilestone_APIFormat(t *testing.T) {
@@ -53,5 +272,1754 @@ func TestMilestone_APIFormat(t *testing.T) {
 		Created:      milestone.CreatedUnix.AsTime(),
 		Updated:      milestone.UpdatedUnix.AsTimePtr(),
 		Deadline:     milestone.DeadlineUnix.AsTimePtr(),
+		IsOverdue:    true,
+		SortKey:      "0-00000000000946684800-milestonename",
 	}, *ToAPIMilestone(milestone))
 }
+
+func TestToStopWatches(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	sw := unittest.AssertExistsAndLoadBean(t, &issues_model.Stopwatch{ID: 1})
+
+	defer timeutil.Unset()
+	timeutil.Set(sw.CreatedUnix.AsLocalTime().Add(90 * time.Second))
+	apiSWs, err := ToStopWatches(db.DefaultContext, []*issues_model.Stopwatch{sw})
+	assert.NoError(t, err)
+	if assert.Len(t, apiSWs, 1) {
+		assert.EqualValues(t, 90, apiSWs[0].Seconds)
+		assert.Equal(t, util.SecToTime(90), apiSWs[0].Duration)
+		assert.True(t, apiSWs[0].Running)
+		assert.EqualValues(t, 1, apiSWs[0].IssueIndex)
+		assert.Equal(t, "repo1", apiSWs[0].RepoName)
+	}
+
+	// advancing the clock must be reflected in both Seconds and Duration
+	timeutil.Set(sw.CreatedUnix.AsLocalTime().Add(time.Hour + 90*time.Second))
+	apiSWs, err = ToStopWatches(db.DefaultContext, []*issues_model.Stopwatch{sw})
+	assert.NoError(t, err)
+	if assert.Len(t, apiSWs, 1) {
+		assert.EqualValues(t, 3690, apiSWs[0].Seconds)
+		assert.Equal(t, util.SecToTime(3690), apiSWs[0].Duration)
+	}
+}
+
+func TestToStopWatchSummary(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	sw1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Stopwatch{ID: 1})
+	// a second stopwatch of the same user, running on an issue of another repository
+	sw2 := &issues_model.Stopwatch{UserID: 1, IssueID: 4, CreatedUnix: sw1.CreatedUnix + 30}
+
+	defer timeutil.Unset()
+	timeutil.Set(sw1.CreatedUnix.AsLocalTime().Add(90 * time.Second))
+	summary, err := ToStopWatchSummary(db.DefaultContext, []*issues_model.Stopwatch{sw1, sw2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 90+60, summary.TotalSeconds)
+	assert.Equal(t, util.SecToTime(150), summary.TotalDuration)
+	if assert.Len(t, summary.StopWatches, 2) {
+		assert.EqualValues(t, 90, summary.StopWatches[0].Seconds)
+		assert.Equal(t, "repo1", summary.StopWatches[0].RepoName)
+		assert.EqualValues(t, 60, summary.StopWatches[1].Seconds)
+		assert.Equal(t, "repo2", summary.StopWatches[1].RepoName)
+	}
+
+	// the total follows the live elapsed time of the stopwatches
+	timeutil.Set(sw1.CreatedUnix.AsLocalTime().Add(time.Hour))
+	summary, err = ToStopWatchSummary(db.DefaultContext, []*issues_model.Stopwatch{sw1, sw2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3600+3570, summary.TotalSeconds)
+
+	summary, err = ToStopWatchSummary(db.DefaultContext, nil)
+	assert.NoError(t, err)
+	assert.Zero(t, summary.TotalSeconds)
+	assert.Empty(t, summary.StopWatches)
+}
+
+func TestToAPIIssue_ProjectPlacement(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// project placement is only converted when it has been loaded
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 3})
+	apiIssue := ToAPIIssue(db.DefaultContext, issue)
+	assert.Nil(t, apiIssue.Project)
+	assert.Nil(t, apiIssue.ProjectColumn)
+
+	assert.NoError(t, issue.LoadProject())
+	assert.NoError(t, issue.LoadProjectBoard(db.DefaultContext))
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	assert.Equal(t, &api.ProjectMeta{ID: 1, Title: "First project"}, apiIssue.Project)
+	assert.Equal(t, &api.ProjectColumnMeta{ID: 2, Title: "In Progress"}, apiIssue.ProjectColumn)
+
+	// issue in a project without a specific column
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.NoError(t, issue.LoadProject())
+	assert.NoError(t, issue.LoadProjectBoard(db.DefaultContext))
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	assert.EqualValues(t, 1, apiIssue.Project.ID)
+	assert.Nil(t, apiIssue.ProjectColumn)
+
+	// issue not in any project
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 4})
+	assert.NoError(t, issue.LoadProject())
+	assert.NoError(t, issue.LoadProjectBoard(db.DefaultContext))
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	assert.Nil(t, apiIssue.Project)
+	assert.Nil(t, apiIssue.ProjectColumn)
+}
+
+func TestToAPIIssue_LastCommented(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// system comments interleaved with user comments
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.Equal(t, timeutil.TimeStamp(946684812).AsTime(), *ToAPIIssue(db.DefaultContext, issue).LastCommented)
+
+	_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(&issues_model.Comment{
+		Type:        issues_model.CommentTypeClose,
+		PosterID:    2,
+		IssueID:     issue.ID,
+		CreatedUnix: 946684813,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, timeutil.TimeStamp(946684812).AsTime(), *ToAPIIssue(db.DefaultContext, issue).LastCommented)
+
+	_, err = db.GetEngine(db.DefaultContext).NoAutoTime().Insert(&issues_model.Comment{
+		Type:        issues_model.CommentTypeComment,
+		PosterID:    2,
+		IssueID:     issue.ID,
+		Content:     "reopened by a human",
+		CreatedUnix: 946684814,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, timeutil.TimeStamp(946684814).AsTime(), *ToAPIIssue(db.DefaultContext, issue).LastCommented)
+
+	// no comments at all
+	issue4 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 4})
+	assert.Nil(t, ToAPIIssue(db.DefaultContext, issue4).LastCommented)
+
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues_model.IssueList{issue, issue4}, nil)
+	assert.Equal(t, timeutil.TimeStamp(946684814).AsTime(), *apiIssues[0].LastCommented)
+	assert.Nil(t, apiIssues[1].LastCommented)
+}
+
+func TestToAPIIssueRedacted(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	issue.Content = "Relates to #2, user2/repo1#2 and user2/repo2#1. cc @user4 @user31"
+
+	// user2/repo2 is private and user31 has a private profile
+	viewer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	apiIssue := ToAPIIssueRedacted(db.DefaultContext, issue, viewer)
+	assert.Equal(t, "Relates to #2, user2/repo1#2 and . cc @user4 ", apiIssue.Body)
+
+	// the owner of the private repository can see everything it references
+	viewer = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	apiIssue = ToAPIIssueRedacted(db.DefaultContext, issue, viewer)
+	assert.Equal(t, "Relates to #2, user2/repo1#2 and user2/repo2#1. cc @user4 ", apiIssue.Body)
+
+	// anonymous viewers only see public references
+	apiIssue = ToAPIIssueRedacted(db.DefaultContext, issue, nil)
+	assert.Equal(t, "Relates to #2, user2/repo1#2 and . cc @user4 ", apiIssue.Body)
+
+	// the original conversion is left untouched
+	assert.Equal(t, issue.Content, ToAPIIssue(db.DefaultContext, issue).Body)
+}
+
+func TestToTrackedTime_Comment(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	trackedTime, err := issues_model.AddTime(user, issue, 120, time.Now(), false)
+	assert.NoError(t, err)
+	assert.NoError(t, trackedTime.LoadAttributes())
+
+	apiTime := ToTrackedTime(db.DefaultContext, trackedTime)
+	assert.Equal(t, trackedTime.Comment.ID, apiTime.CommentID)
+	assert.Equal(t, "2 minutes", apiTime.CommentBody)
+
+	// time tracked without a note
+	trackedTime = unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: 1})
+	assert.NoError(t, trackedTime.LoadAttributes())
+	apiTime = ToTrackedTime(db.DefaultContext, trackedTime)
+	assert.Zero(t, apiTime.CommentID)
+	assert.Empty(t, apiTime.CommentBody)
+}
+
+func TestToTrackedTime_Milliseconds(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	// second granularity entries round-trip unchanged
+	added, err := issues_model.AddTime(user, issue, 120, time.Now(), false)
+	assert.NoError(t, err)
+	trackedTime := unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: added.ID})
+	assert.NoError(t, trackedTime.LoadAttributes())
+	apiTime := ToTrackedTime(db.DefaultContext, trackedTime)
+	assert.EqualValues(t, 120, apiTime.Time)
+	assert.EqualValues(t, 120000, apiTime.TimeMs)
+
+	// entries without a millisecond value
+	trackedTime = unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: 1})
+	assert.NoError(t, trackedTime.LoadAttributes())
+	apiTime = ToTrackedTime(db.DefaultContext, trackedTime)
+	assert.EqualValues(t, 400, apiTime.Time)
+	assert.EqualValues(t, 400000, apiTime.TimeMs)
+
+	// sub-second precision is kept
+	trackedTime = &issues_model.TrackedTime{UserID: user.ID, IssueID: issue.ID, Time: 1, TimeMs: 1500}
+	assert.NoError(t, db.Insert(db.DefaultContext, trackedTime))
+	trackedTime = unittest.AssertExistsAndLoadBean(t, &issues_model.TrackedTime{ID: trackedTime.ID})
+	assert.NoError(t, trackedTime.LoadAttributes())
+	apiTime = ToTrackedTime(db.DefaultContext, trackedTime)
+	assert.EqualValues(t, 1, apiTime.Time)
+	assert.EqualValues(t, 1500, apiTime.TimeMs)
+}
+
+func TestToTrackedTimeSummary(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer func(enabled bool) { setting.Service.EnableTimetracking = enabled }(setting.Service.EnableTimetracking)
+	setting.Service.EnableTimetracking = true
+
+	// repository 3 has the time tracker disabled
+	_, err := db.GetEngine(db.DefaultContext).Insert(&issues_model.TrackedTime{UserID: 2, IssueID: 6, Time: 500})
+	assert.NoError(t, err)
+
+	var tl issues_model.TrackedTimeList
+	for _, issueID := range []int64{1, 2, 6} {
+		times, err := issues_model.GetTrackedTimes(db.DefaultContext, &issues_model.FindTrackedTimesOptions{IssueID: issueID})
+		assert.NoError(t, err)
+		tl = append(tl, times...)
+	}
+	assert.Len(t, tl, 5)
+
+	summary, err := ToTrackedTimeSummary(db.DefaultContext, tl)
+	assert.NoError(t, err)
+	assert.Len(t, summary.Times, 4)
+	assert.EqualValues(t, 400+3661+1+20, summary.Total)
+	assert.Equal(t, []*api.TrackedTimeIssueTotal{
+		{IssueID: 1, Index: 1, Time: 400, TimeMs: 400000},
+		{IssueID: 2, Index: 2, Time: 3661 + 1 + 20, TimeMs: (3661 + 1 + 20) * 1000},
+	}, summary.ByIssue)
+	assert.Equal(t, []*api.TrackedTimeUserTotal{
+		{UserID: 1, UserName: "user1", Time: 400 + 20, TimeMs: (400 + 20) * 1000},
+		{UserID: 2, UserName: "user2", Time: 3661 + 1, TimeMs: (3661 + 1) * 1000},
+	}, summary.ByUser)
+}
+
+func TestToTrackedTimeSummary_Billable(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer func(enabled bool) { setting.Service.EnableTimetracking = enabled }(setting.Service.EnableTimetracking)
+	setting.Service.EnableTimetracking = true
+
+	// existing entries are not billable
+	tl, err := issues_model.GetTrackedTimes(db.DefaultContext, &issues_model.FindTrackedTimesOptions{IssueID: 2})
+	assert.NoError(t, err)
+	summary, err := ToTrackedTimeSummary(db.DefaultContext, tl)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3661+1+20, summary.Total)
+	assert.Zero(t, summary.BillableTotal)
+
+	_, err = db.GetEngine(db.DefaultContext).In("id", 2, 6).Cols("billable").Update(&issues_model.TrackedTime{Billable: true})
+	assert.NoError(t, err)
+	tl, err = issues_model.GetTrackedTimes(db.DefaultContext, &issues_model.FindTrackedTimesOptions{IssueID: 2})
+	assert.NoError(t, err)
+	summary, err = ToTrackedTimeSummary(db.DefaultContext, tl)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3661+1+20, summary.Total)
+	assert.EqualValues(t, 3661+20, summary.BillableTotal)
+	_, billableTotal := ToTrackedTimeList(db.DefaultContext, tl)
+	assert.EqualValues(t, 3661+20, billableTotal)
+
+	billable := make(map[int64]bool, len(summary.Times))
+	for _, apiT := range summary.Times {
+		billable[apiT.ID] = apiT.Billable
+	}
+	assert.Equal(t, map[int64]bool{2: true, 3: false, 6: true}, billable)
+}
+
+func TestToIssuePermissions(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	open := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	closed := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5, IsClosed: true})
+	collaborator := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	assert.NoError(t, repo_module.AddCollaborator(unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1}), collaborator))
+
+	for _, c := range []struct {
+		name     string
+		issue    *issues_model.Issue
+		viewer   *user_model.User
+		expected api.IssuePermissions
+	}{
+		{
+			name:     "admin",
+			issue:    open,
+			viewer:   unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1}),
+			expected: api.IssuePermissions{CanClose: true, CanAssign: true, CanLabel: true, CanLock: true, CanDelete: true},
+		},
+		{
+			name:     "collaborator",
+			issue:    open,
+			viewer:   collaborator,
+			expected: api.IssuePermissions{CanClose: true, CanAssign: true, CanLabel: true, CanLock: true},
+		},
+		{
+			name:     "collaborator on closed issue",
+			issue:    closed,
+			viewer:   collaborator,
+			expected: api.IssuePermissions{CanReopen: true, CanAssign: true, CanLabel: true, CanLock: true},
+		},
+		{
+			name:   "reader",
+			issue:  open,
+			viewer: unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5}),
+		},
+		{
+			name:  "anonymous",
+			issue: open,
+		},
+	} {
+		perms, err := ToIssuePermissions(db.DefaultContext, c.issue, c.viewer)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, *perms, c.name)
+	}
+}
+
+func TestToAPIIssue_LockReason(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NoError(t, issue.LoadRepo(db.DefaultContext))
+
+	apiIssue := ToAPIIssue(db.DefaultContext, issue)
+	assert.False(t, apiIssue.IsLocked)
+	assert.Empty(t, apiIssue.LockReason)
+
+	assert.NoError(t, issues_model.LockIssue(&issues_model.IssueLockOptions{Doer: doer, Issue: issue, Reason: "Spam"}))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1, IsLocked: true})
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	assert.True(t, apiIssue.IsLocked)
+	assert.Equal(t, "Spam", apiIssue.LockReason)
+
+	assert.NoError(t, issues_model.UnlockIssue(&issues_model.IssueLockOptions{Doer: doer, Issue: issue}))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.Empty(t, issue.LockReason)
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	assert.False(t, apiIssue.IsLocked)
+	assert.Empty(t, apiIssue.LockReason)
+}
+
+func TestToAPIIssue_DeadlineSetBy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	poster := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	// the deadline has never been set
+	apiIssue := ToAPIIssue(db.DefaultContext, issue)
+	assert.Nil(t, apiIssue.Deadline)
+	assert.Nil(t, apiIssue.DeadlineSetBy)
+	assert.Nil(t, apiIssue.DeadlineSetAt)
+
+	assert.NoError(t, issues_model.UpdateIssueDeadline(issue, timeutil.TimeStamp(1700000000), poster))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	if assert.NotNil(t, apiIssue.DeadlineSetBy) {
+		assert.Equal(t, poster.ID, apiIssue.DeadlineSetBy.ID)
+	}
+
+	// the latest change wins
+	assert.NoError(t, issues_model.UpdateIssueDeadline(issue, timeutil.TimeStamp(1800000000), owner))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: 1, Type: issues_model.CommentTypeModifiedDeadline})
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	assert.Equal(t, timeutil.TimeStamp(1800000000).AsTimePtr(), apiIssue.Deadline)
+	if assert.NotNil(t, apiIssue.DeadlineSetBy) {
+		assert.Equal(t, owner.ID, apiIssue.DeadlineSetBy.ID)
+	}
+	assert.Equal(t, comment.CreatedUnix.AsTimePtr(), apiIssue.DeadlineSetAt)
+
+	// issue 2 has no deadline, so only issue 1 gets a setter in lists
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues_model.IssueList{
+		issue,
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+	}, nil)
+	if assert.NotNil(t, apiIssues[0].DeadlineSetBy) {
+		assert.Equal(t, owner.ID, apiIssues[0].DeadlineSetBy.ID)
+	}
+	assert.Nil(t, apiIssues[1].DeadlineSetBy)
+
+	// removing the deadline clears the audit fields again
+	assert.NoError(t, issues_model.UpdateIssueDeadline(issue, 0, owner))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	assert.Nil(t, apiIssue.DeadlineSetBy)
+	assert.Nil(t, apiIssue.DeadlineSetAt)
+}
+
+func TestToAPIIssue_Template(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// issues created without a template report none
+	apiIssue := ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}))
+	assert.Empty(t, apiIssue.Template)
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	issue.Template = ".gitea/ISSUE_TEMPLATE/bug_report.yaml"
+	assert.NoError(t, issues_model.UpdateIssueCols(db.DefaultContext, issue, "template"))
+
+	apiIssue = ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}))
+	assert.Equal(t, ".gitea/ISSUE_TEMPLATE/bug_report.yaml", apiIssue.Template)
+}
+
+func TestToAPIIssue_ClosedReason(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	// closed issues without a stored reason count as completed
+	apiIssue := ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5}))
+	assert.EqualValues(t, api.StateClosed, apiIssue.State)
+	assert.Equal(t, "completed", apiIssue.ClosedReason)
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.Empty(t, ToAPIIssue(db.DefaultContext, issue).ClosedReason)
+
+	issue.ClosedReason = issues_model.IssueClosedReasonNotPlanned
+	_, err := issues_model.ChangeIssueStatus(db.DefaultContext, issue, doer, true)
+	assert.NoError(t, err)
+	apiIssue = ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}))
+	assert.EqualValues(t, api.StateClosed, apiIssue.State)
+	assert.Equal(t, "not_planned", apiIssue.ClosedReason)
+
+	// reopening clears the reason, closing again defaults to completed
+	_, err = issues_model.ChangeIssueStatus(db.DefaultContext, issue, doer, false)
+	assert.NoError(t, err)
+	apiIssue = ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}))
+	assert.EqualValues(t, api.StateOpen, apiIssue.State)
+	assert.Empty(t, apiIssue.ClosedReason)
+
+	_, err = issues_model.ChangeIssueStatus(db.DefaultContext, issue, doer, true)
+	assert.NoError(t, err)
+	apiIssue = ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}))
+	assert.Equal(t, "completed", apiIssue.ClosedReason)
+}
+
+func TestToAPIIssue_GhostPoster(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	ghost := ToUser(user_model.NewGhostUser(), nil)
+
+	// the account of the poster has been deleted
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	issue.PosterID = 9999
+	assert.NoError(t, issues_model.UpdateIssueCols(db.DefaultContext, issue, "poster_id"))
+	apiIssue := ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}))
+	assert.Equal(t, ghost, apiIssue.Poster)
+
+	// migrated issues and comments without a local author
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	issue.PosterID = -1
+	issue.OriginalAuthor = "migrated-author"
+	issue.OriginalAuthorID = 1234
+	assert.NoError(t, issues_model.UpdateIssueCols(db.DefaultContext, issue, "poster_id", "original_author", "original_author_id"))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	assert.Equal(t, ghost, apiIssue.Poster)
+	assert.Equal(t, "migrated-author", apiIssue.OriginalAuthor)
+	assert.EqualValues(t, 1234, apiIssue.OriginalAuthorID)
+
+	issues := issues_model.IssueList{unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})}
+	apiIssues, err := ToAPIIssueMinimalList(db.DefaultContext, issues)
+	assert.NoError(t, err)
+	assert.Equal(t, ghost, apiIssues[0].Poster)
+
+	_, err = db.GetEngine(db.DefaultContext).ID(2).Cols("poster_id", "original_author", "original_author_id").
+		Update(&issues_model.Comment{PosterID: 0, OriginalAuthor: "migrated-commenter", OriginalAuthorID: 5678})
+	assert.NoError(t, err)
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: 2})
+	assert.NoError(t, comment.LoadPoster(db.DefaultContext))
+	apiComment := ToComment(comment)
+	assert.Equal(t, ghost, apiComment.Poster)
+	assert.Equal(t, "migrated-commenter", apiComment.OriginalAuthor)
+}
+
+func TestIssueETag(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	etag := IssueETag(issue, doer, time.Time{})
+	assert.Equal(t, etag, IssueETag(unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}), doer, time.Time{}))
+	assert.NotEqual(t, etag, IssueETag(issue, nil, time.Time{}))
+	assert.NotEqual(t, etag, IssueETag(issue, doer, time.Unix(1000, 0)))
+
+	// the passing of time alone does not change the tag
+	defer timeutil.Unset()
+	timeutil.Set(time.Now().Add(time.Hour))
+	assert.Equal(t, etag, IssueETag(issue, doer, time.Time{}))
+
+	// adding a label bumps the update time of the issue
+	label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 2})
+	assert.NoError(t, issues_model.NewIssueLabel(issue, label, doer))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NotEqual(t, etag, IssueETag(issue, doer, time.Time{}))
+
+	apiIssue := ToAPIIssue(db.DefaultContext, issue)
+	assert.Equal(t, issue.UpdatedUnix.AsTime(), apiIssue.Updated)
+}
+
+func TestToAPIIssueForViewer(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	poster := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	nonCollaborator := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	assertPermissions := func(viewer *user_model.User, canEdit, canComment bool) {
+		apiIssue := ToAPIIssueForViewer(db.DefaultContext, issue, viewer, time.Time{})
+		assert.Equal(t, canEdit, apiIssue.CanEdit)
+		assert.Equal(t, canComment, apiIssue.CanComment)
+	}
+
+	assertPermissions(nil, false, false)
+	assertPermissions(owner, true, true)
+	assertPermissions(poster, true, true)
+	assertPermissions(nonCollaborator, false, true)
+
+	// the viewer-less conversion does not report permissions
+	apiIssue := ToAPIIssue(db.DefaultContext, issue)
+	assert.False(t, apiIssue.CanEdit)
+	assert.False(t, apiIssue.CanComment)
+
+	// only users with write access may comment on a locked issue
+	assert.NoError(t, issues_model.LockIssue(&issues_model.IssueLockOptions{Doer: owner, Issue: issue}))
+	assertPermissions(owner, true, true)
+	assertPermissions(nonCollaborator, false, false)
+
+	// private repository
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 4})
+	assertPermissions(nonCollaborator, false, false)
+}
+
+func TestToAPIIssue_AgeSeconds(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer timeutil.Unset()
+
+	open := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	timeutil.Set(open.CreatedUnix.AsLocalTime().Add(36 * time.Hour))
+	assert.EqualValues(t, 36*60*60, ToAPIIssue(db.DefaultContext, open).AgeSeconds)
+
+	closed := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5})
+	closed.ClosedUnix = closed.CreatedUnix.Add(90)
+	assert.EqualValues(t, 90, ToAPIIssue(db.DefaultContext, closed).AgeSeconds)
+
+	// the age of open issues follows the clock, so lists report the same ages only as long as it is pinned
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues_model.IssueList{open}, nil)
+	assert.EqualValues(t, 36*60*60, apiIssues[0].AgeSeconds)
+	timeutil.Set(open.CreatedUnix.AsLocalTime().Add(37 * time.Hour))
+	assert.EqualValues(t, 37*60*60, ToAPIIssue(db.DefaultContext, open).AgeSeconds)
+}
+
+func TestToAPIIssue_SecondsSinceLastResponse(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer timeutil.Unset()
+
+	// issue 1 was posted by user1, user3 and user5 responded
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	lastResponse := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: 3})
+	timeutil.Set(lastResponse.CreatedUnix.AsLocalTime().Add(time.Hour))
+	assert.EqualValues(t, 60*60, ToAPIIssue(db.DefaultContext, issue).SecondsSinceLastResponse)
+
+	// comments of the poster are no responses
+	_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(&issues_model.Comment{
+		Type:        issues_model.CommentTypeComment,
+		PosterID:    issue.PosterID,
+		IssueID:     issue.ID,
+		CreatedUnix: lastResponse.CreatedUnix.Add(30 * 60),
+	})
+	assert.NoError(t, err)
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues_model.IssueList{
+		issue,
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+	}, nil)
+	assert.EqualValues(t, 60*60, apiIssues[0].SecondsSinceLastResponse)
+	// nobody but the poster commented on issue 2
+	assert.Zero(t, apiIssues[1].SecondsSinceLastResponse)
+}
+
+func TestToAPIIssue_LockedBy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	locker := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.Nil(t, ToAPIIssue(db.DefaultContext, issue).LockedBy)
+
+	assert.NoError(t, issues_model.LockIssue(&issues_model.IssueLockOptions{Doer: locker, Issue: issue, Reason: "Spam"}))
+	apiIssue := ToAPIIssue(db.DefaultContext, issue)
+	if assert.NotNil(t, apiIssue.LockedBy) {
+		assert.Equal(t, locker.ID, apiIssue.LockedBy.ID)
+	}
+
+	// the latest lock counts and lists are loaded the same way
+	other := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	assert.NoError(t, issues_model.UnlockIssue(&issues_model.IssueLockOptions{Doer: locker, Issue: issue}))
+	assert.Nil(t, ToAPIIssue(db.DefaultContext, issue).LockedBy)
+	assert.NoError(t, issues_model.LockIssue(&issues_model.IssueLockOptions{Doer: other, Issue: issue}))
+	issues := issues_model.IssueList{issue, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})}
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues, nil)
+	if assert.NotNil(t, apiIssues[0].LockedBy) {
+		assert.Equal(t, other.ID, apiIssues[0].LockedBy.ID)
+	}
+	assert.Nil(t, apiIssues[1].LockedBy)
+
+	// a deleted locker is reported as the ghost user
+	_, err := db.GetEngine(db.DefaultContext).Where("issue_id = ? AND type = ?", issue.ID, issues_model.CommentTypeLock).
+		Cols("poster_id").Update(&issues_model.Comment{PosterID: 9999})
+	assert.NoError(t, err)
+	apiIssue = ToAPIIssue(db.DefaultContext, issue)
+	if assert.NotNil(t, apiIssue.LockedBy) {
+		assert.Equal(t, user_model.NewGhostUser().ID, apiIssue.LockedBy.ID)
+	}
+}
+
+func TestToAPIIssue_Participants(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	participantIDs := func(apiIssue *api.Issue) []int64 {
+		ids := make([]int64, 0, len(apiIssue.Participants))
+		for _, u := range apiIssue.Participants {
+			ids = append(ids, u.ID)
+		}
+		return ids
+	}
+
+	// issue 1 has been opened by user 1, who is also assigned, and commented on by users 3 and 5
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.Equal(t, []int64{1, 3, 5}, participantIDs(ToAPIIssue(db.DefaultContext, issue)))
+
+	for _, posterID := range []int64{4, 3, 9999} {
+		assert.NoError(t, db.Insert(db.DefaultContext, &issues_model.Comment{
+			Type:     issues_model.CommentTypeComment,
+			IssueID:  issue.ID,
+			PosterID: posterID,
+		}))
+	}
+	// label changes do not count as participation
+	assert.NoError(t, db.Insert(db.DefaultContext, &issues_model.Comment{
+		Type:     issues_model.CommentTypeLabel,
+		IssueID:  issue.ID,
+		PosterID: 8,
+	}))
+	assert.Equal(t, []int64{1, 3, 5, 4}, participantIDs(ToAPIIssue(db.DefaultContext, issue)))
+
+	issues := issues_model.IssueList{issue, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})}
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues, nil)
+	assert.Equal(t, []int64{1, 3, 5, 4}, participantIDs(apiIssues[0]))
+	// issue 6 has been opened by user 1 and is assigned to users 1 and 2
+	assert.Equal(t, []int64{1, 2}, participantIDs(apiIssues[1]))
+
+	// email addresses are shown following the privacy settings of the users for the viewer
+	user5 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	viewer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	apiIssue := ToAPIIssueForViewer(db.DefaultContext, issue, viewer, time.Time{})
+	assert.Equal(t, user5.Email, apiIssue.Participants[2].Email)
+	assert.Equal(t, user5.GetEmail(), ToAPIIssue(db.DefaultContext, issue).Participants[2].Email)
+
+	defer func(users []string) {
+		setting.Repository.Issue.ParticipantsExcludedUsers = users
+	}(setting.Repository.Issue.ParticipantsExcludedUsers)
+	setting.Repository.Issue.ParticipantsExcludedUsers = []string{"User3"}
+	assert.Equal(t, []int64{1, 5, 4}, participantIDs(ToAPIIssue(db.DefaultContext, issue)))
+}
+
+func TestStreamAPIIssueList(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	var issues issues_model.IssueList
+	assert.NoError(t, db.GetEngine(db.DefaultContext).OrderBy("id").Find(&issues))
+	// repeat the issues to span several batches
+	for len(issues) <= 2*issueStreamBatchSize {
+		issues = append(issues, issues...)
+	}
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	// AgeSeconds of open issues depends on the clock, it must not change between the conversions
+	defer timeutil.Unset()
+	timeutil.Set(time.Unix(1700000000, 0))
+
+	for _, il := range []issues_model.IssueList{issues, issues[:issueStreamBatchSize], issues[:1], {}} {
+		expected, err := json.Marshal(ToAPIIssueList(db.DefaultContext, il, doer))
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, StreamAPIIssueList(db.DefaultContext, &buf, il, doer))
+		assert.Equal(t, string(expected), buf.String(), "%d issues", len(il))
+	}
+}
+
+func TestToAPIIssueList_ReferencedBy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// issue 1 is referenced from a public (repo 50) and a private repository (repo 2),
+	// from a deleted repository and by a reference which has been removed again
+	for _, c := range []*issues_model.Comment{
+		{Type: issues_model.CommentTypeIssueRef, IssueID: 1, RefRepoID: 50, RefIssueID: 13},
+		{Type: issues_model.CommentTypeCommentRef, IssueID: 1, RefRepoID: 50, RefIssueID: 13, RefCommentID: 1},
+		{Type: issues_model.CommentTypeIssueRef, IssueID: 1, RefRepoID: 2, RefIssueID: 4},
+		{Type: issues_model.CommentTypeIssueRef, IssueID: 1, RefRepoID: 9999, RefIssueID: 9999},
+		{Type: issues_model.CommentTypeIssueRef, IssueID: 1, RefRepoID: 2, RefIssueID: 7, RefAction: references.XRefActionNeutered},
+	} {
+		assert.NoError(t, db.Insert(db.DefaultContext, c))
+	}
+
+	issues := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+	}
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	other := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues, owner)
+	assert.Equal(t, 2, apiIssues[0].ReferencedBy)
+	assert.Zero(t, apiIssues[1].ReferencedBy)
+
+	apiIssues = ToAPIIssueList(db.DefaultContext, issues, other)
+	assert.Equal(t, 1, apiIssues[0].ReferencedBy)
+
+	apiIssues = ToAPIIssueList(db.DefaultContext, issues, nil)
+	assert.Equal(t, 1, apiIssues[0].ReferencedBy)
+
+	assert.Equal(t, 2, ToAPIIssueForViewer(db.DefaultContext, issues[0], owner, time.Time{}).ReferencedBy)
+}
+
+func TestToAPIMilestone_IsOverdue(t *testing.T) {
+	now := timeutil.TimeStampNow()
+	noDeadline := timeutil.TimeStamp(time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+
+	for _, c := range []struct {
+		deadline  timeutil.TimeStamp
+		isClosed  bool
+		isOverdue bool
+	}{
+		{now.Add(86400), false, false},
+		{now.Add(-86400), false, true},
+		{now.Add(-86400), true, false},
+		{noDeadline, false, false},
+	} {
+		m := &issues_model.Milestone{ID: 1, DeadlineUnix: c.deadline, IsClosed: c.isClosed}
+		apiMilestone := ToAPIMilestone(m)
+		assert.Equal(t, c.isOverdue, apiMilestone.IsOverdue, "deadline %v, closed %v", c.deadline, c.isClosed)
+		assert.Equal(t, c.deadline != noDeadline, apiMilestone.Deadline != nil)
+	}
+}
+
+func TestToAPIMilestone_SortKey(t *testing.T) {
+	noDeadline := timeutil.TimeStamp(time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC).Unix())
+	milestones := []*issues_model.Milestone{
+		{Name: "v1.0", DeadlineUnix: 1000},
+		{Name: "v1.1", DeadlineUnix: 1000},
+		{Name: "Backlog", DeadlineUnix: 999999999},
+		{Name: "Someday", DeadlineUnix: noDeadline},
+		{Name: "v0.9", DeadlineUnix: 1000, IsClosed: true},
+		{Name: "Archive", DeadlineUnix: noDeadline, IsClosed: true},
+	}
+
+	// the milestones are listed in the order their keys have to sort in
+	for i := 1; i < len(milestones); i++ {
+		prev, cur := ToAPIMilestone(milestones[i-1]).SortKey, ToAPIMilestone(milestones[i]).SortKey
+		assert.Less(t, prev, cur, "%q should sort before %q", milestones[i-1].Name, milestones[i].Name)
+	}
+}
+
+func TestToMilestoneBurndown(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer func(loc *time.Location) {
+		setting.DefaultUILocation = loc
+	}(setting.DefaultUILocation)
+	setting.DefaultUILocation = time.UTC
+
+	at := func(day, hour int) timeutil.TimeStamp {
+		return timeutil.TimeStamp(time.Date(2022, time.March, day, hour, 30, 0, 0, time.UTC).Unix())
+	}
+	m := &issues_model.Milestone{
+		RepoID:       1,
+		Name:         "sprint",
+		NumIssues:    4,
+		CreatedUnix:  at(1, 10),
+		UpdatedUnix:  at(1, 10),
+		DeadlineUnix: at(5, 0),
+	}
+	_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(m)
+	assert.NoError(t, err)
+	for i, closed := range []timeutil.TimeStamp{at(1, 9) - 3*86400, at(2, 8), at(2, 23), 0} {
+		_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(&issues_model.Issue{
+			RepoID:      1,
+			Index:       int64(1000 + i),
+			PosterID:    1,
+			MilestoneID: m.ID,
+			IsClosed:    closed != 0,
+			ClosedUnix:  closed,
+			CreatedUnix: at(1, 11),
+			UpdatedUnix: at(1, 11),
+		})
+		assert.NoError(t, err)
+	}
+
+	burndown, err := ToMilestoneBurndown(db.DefaultContext, m)
+	assert.NoError(t, err)
+	assert.Equal(t, m.ID, burndown.Milestone.ID)
+	assert.Equal(t, []*api.MilestoneBurndownDay{
+		{Date: "2022-03-01", ClosedIssues: 1, OpenIssues: 3},
+		{Date: "2022-03-02", ClosedIssues: 2, OpenIssues: 1},
+		{Date: "2022-03-03", ClosedIssues: 0, OpenIssues: 1},
+		{Date: "2022-03-04", ClosedIssues: 0, OpenIssues: 1},
+		{Date: "2022-03-05", ClosedIssues: 0, OpenIssues: 1},
+	}, burndown.Days)
+
+	// without a deadline the burndown ends today
+	m.CreatedUnix = timeutil.TimeStampNow() - 2*86400
+	m.DeadlineUnix = timeutil.TimeStamp(time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	burndown, err = ToMilestoneBurndown(db.DefaultContext, m)
+	assert.NoError(t, err)
+	if assert.Len(t, burndown.Days, 3) {
+		assert.Equal(t, time.Now().UTC().Format("2006-01-02"), burndown.Days[2].Date)
+		assert.Equal(t, 3, burndown.Days[0].ClosedIssues)
+		assert.Equal(t, 1, burndown.Days[2].OpenIssues)
+	}
+}
+
+func TestToAPIIssue_PosterIsFirstTimeContributor(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	isFirstTime := func(issueID int64) bool {
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issueID})
+		return ToAPIIssue(db.DefaultContext, issue).PosterIsFirstTimeContributor
+	}
+
+	// issue 1 is the first issue of user 1 in repo 1, issue 2 is a later one
+	assert.True(t, isFirstTime(1))
+	assert.False(t, isFirstTime(2))
+	// issue 5 has been opened by the owner of the repository
+	assert.False(t, isFirstTime(5))
+	// issue 6 has been opened by a non member of org 3, pull 12 by a member
+	assert.True(t, isFirstTime(6))
+	assert.False(t, isFirstTime(12))
+
+	// lists are converted in a batch with the same result
+	issues := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 12}),
+	}
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues, nil)
+	assert.True(t, apiIssues[0].PosterIsFirstTimeContributor)
+	assert.False(t, apiIssues[1].PosterIsFirstTimeContributor)
+	assert.True(t, apiIssues[2].PosterIsFirstTimeContributor)
+	assert.False(t, apiIssues[3].PosterIsFirstTimeContributor)
+
+	defer func(users []string, members bool) {
+		setting.Repository.Issue.FirstTimeContributorExcludedUsers = users
+		setting.Repository.Issue.FirstTimeContributorExcludeMembers = members
+	}(setting.Repository.Issue.FirstTimeContributorExcludedUsers, setting.Repository.Issue.FirstTimeContributorExcludeMembers)
+
+	setting.Repository.Issue.FirstTimeContributorExcludedUsers = []string{"User1"}
+	setting.Repository.Issue.FirstTimeContributorExcludeMembers = false
+	assert.False(t, isFirstTime(1))
+	assert.True(t, isFirstTime(12))
+}
+
+func TestToAPIIssueForViewer_Subscribed(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assertSubscription := func(issueID, viewerID int64, subscribed, muted bool) {
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issueID})
+		viewer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: viewerID})
+		apiIssue := ToAPIIssueForViewer(db.DefaultContext, issue, viewer, time.Time{})
+		assert.Equal(t, subscribed, apiIssue.Subscribed, "issue %d, viewer %d", issueID, viewerID)
+		assert.Equal(t, muted, apiIssue.SubscriptionMuted, "issue %d, viewer %d", issueID, viewerID)
+	}
+
+	// explicitly subscribed
+	assertSubscription(1, 9, true, false)
+	// implicitly subscribed by watching the repository
+	assertSubscription(1, 4, true, false)
+	// not watching the repository
+	assertSubscription(1, 8, false, false)
+	// explicitly unsubscribed
+	assertSubscription(2, 2, false, true)
+
+	// the viewer-less conversion does not report subscriptions
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.False(t, ToAPIIssue(db.DefaultContext, issue).Subscribed)
+}
+
+func TestToAPIIssueForViewer_AssigneesChangedSince(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	viewer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(&issues_model.Comment{
+		Type:        issues_model.CommentTypeAssignees,
+		IssueID:     issue.ID,
+		PosterID:    2,
+		AssigneeID:  1,
+		CreatedUnix: 1000,
+		UpdatedUnix: 1000,
+	})
+	assert.NoError(t, err)
+
+	changedSince := func(since time.Time) bool {
+		return ToAPIIssueForViewer(db.DefaultContext, issue, viewer, since).AssigneesChangedSince
+	}
+	assert.True(t, changedSince(time.Unix(999, 0)))
+	assert.False(t, changedSince(time.Unix(1000, 0)))
+	assert.False(t, changedSince(time.Unix(2000, 0)))
+	// without a since time nothing is reported
+	assert.False(t, changedSince(time.Time{}))
+
+	// other comments do not count as an assignee change
+	_, err = db.GetEngine(db.DefaultContext).NoAutoTime().Insert(&issues_model.Comment{
+		Type:        issues_model.CommentTypeComment,
+		IssueID:     issue.ID,
+		PosterID:    2,
+		CreatedUnix: 3000,
+		UpdatedUnix: 3000,
+	})
+	assert.NoError(t, err)
+	assert.False(t, changedSince(time.Unix(2000, 0)))
+
+	// unassigning is a change as well
+	_, err = db.GetEngine(db.DefaultContext).NoAutoTime().Insert(&issues_model.Comment{
+		Type:            issues_model.CommentTypeAssignees,
+		IssueID:         issue.ID,
+		PosterID:        2,
+		AssigneeID:      1,
+		RemovedAssignee: true,
+		CreatedUnix:     4000,
+		UpdatedUnix:     4000,
+	})
+	assert.NoError(t, err)
+	assert.True(t, changedSince(time.Unix(2000, 0)))
+	assert.False(t, changedSince(time.Unix(4000, 0)))
+}
+
+func TestToAPIIssue_WorkInProgress(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	apiIssue := ToAPIIssue(db.DefaultContext, issue)
+	if assert.NotNil(t, apiIssue.PullRequest) {
+		assert.False(t, apiIssue.PullRequest.IsWorkInProgress)
+		assert.Empty(t, apiIssue.PullRequest.WorkInProgressPrefix)
+	}
+
+	// prefixes are matched case insensitively, the prefix is reported as written in the title
+	for _, title := range []string{"WIP: issue2", "[wip] issue2"} {
+		issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+		issue.Title = title
+		apiIssue = ToAPIIssue(db.DefaultContext, issue)
+		if assert.NotNil(t, apiIssue.PullRequest) {
+			assert.True(t, apiIssue.PullRequest.IsWorkInProgress)
+			assert.Equal(t, title[:len(title)-len(" issue2")], apiIssue.PullRequest.WorkInProgressPrefix)
+		}
+	}
+
+	// the work in progress state is only reported for pull requests
+	apiIssue = ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}))
+	assert.Nil(t, apiIssue.PullRequest)
+}
+
+func TestToAPIIssue_ReviewState(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// pull request 2 has been approved by user 1, a review is requested from user 4 in addition
+	assert.NoError(t, db.Insert(db.DefaultContext, &issues_model.Review{Type: issues_model.ReviewTypeRequest, ReviewerID: 4, IssueID: 2}))
+
+	apiIssue := ToAPIIssue(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}))
+	if assert.NotNil(t, apiIssue.PullRequest) {
+		assert.Equal(t, api.ReviewStatePending, apiIssue.PullRequest.ReviewState)
+		if assert.Len(t, apiIssue.PullRequest.RequestedReviewers, 1) {
+			assert.EqualValues(t, 4, apiIssue.PullRequest.RequestedReviewers[0].ID)
+		}
+	}
+
+	// once the requested reviewer approved as well, the pull request is approved
+	assert.NoError(t, db.Insert(db.DefaultContext, &issues_model.Review{Type: issues_model.ReviewTypeApprove, ReviewerID: 4, IssueID: 2}))
+	issues := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 3}),
+	}
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues, nil)
+	assert.Nil(t, apiIssues[0].PullRequest)
+	if assert.NotNil(t, apiIssues[1].PullRequest) {
+		assert.Equal(t, api.ReviewStateApproved, apiIssues[1].PullRequest.ReviewState)
+		assert.Empty(t, apiIssues[1].PullRequest.RequestedReviewers)
+	}
+	// any request for changes wins
+	if assert.NotNil(t, apiIssues[2].PullRequest) {
+		assert.Equal(t, api.ReviewStateRequestChanges, apiIssues[2].PullRequest.ReviewState)
+	}
+}
+
+func TestToAPIIssue_Mergeable(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	clean, conflicted := true, false
+	for _, c := range []struct {
+		status    issues_model.PullRequestStatus
+		mergeable *bool
+	}{
+		{issues_model.PullRequestStatusMergeable, &clean},
+		{issues_model.PullRequestStatusConflict, &conflicted},
+		{issues_model.PullRequestStatusChecking, nil},
+		{issues_model.PullRequestStatusError, nil},
+	} {
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+		assert.NoError(t, issue.LoadPullRequest(db.DefaultContext))
+		issue.PullRequest.HasMerged = false
+		issue.PullRequest.Status = c.status
+		apiIssue := ToAPIIssue(db.DefaultContext, issue)
+		if assert.NotNil(t, apiIssue.PullRequest) {
+			assert.Equal(t, c.mergeable, apiIssue.PullRequest.Mergeable, "status %d", c.status)
+		}
+	}
+
+	// merged pull requests are not checked anymore
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.NoError(t, issue.LoadPullRequest(db.DefaultContext))
+	issue.PullRequest.Status = issues_model.PullRequestStatusMergeable
+	issue.PullRequest.HasMerged = true
+	apiIssue := ToAPIIssue(db.DefaultContext, issue)
+	if assert.NotNil(t, apiIssue.PullRequest) {
+		assert.Nil(t, apiIssue.PullRequest.Mergeable)
+	}
+}
+
+func TestToAPIIssue_DiffStats(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// the pull request of issue 2 has been checked, the one of issue 3 not
+	_, err := db.GetEngine(db.DefaultContext).Where("issue_id = ?", 2).
+		Cols("diff_stats_commit_id", "num_changed_files", "num_additions", "num_deletions").
+		Update(&issues_model.PullRequest{DiffStatsCommitID: "4a357436d925b5c974181ff12a994538ddc5a269", NumChangedFiles: 3, NumAdditions: 25, NumDeletions: 7})
+	assert.NoError(t, err)
+
+	il := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 3}),
+	}
+	apiIssues := ToAPIIssueList(db.DefaultContext, il, nil)
+	assert.Equal(t, 3, *apiIssues[0].PullRequest.ChangedFiles)
+	assert.Equal(t, 25, *apiIssues[0].PullRequest.Additions)
+	assert.Equal(t, 7, *apiIssues[0].PullRequest.Deletions)
+	assert.Nil(t, apiIssues[1].PullRequest.ChangedFiles)
+	assert.Nil(t, apiIssues[1].PullRequest.Additions)
+	assert.Nil(t, apiIssues[1].PullRequest.Deletions)
+
+	// the stats are not reported while the pull request is checked again
+	il[0].PullRequest.Status = issues_model.PullRequestStatusChecking
+	assert.Nil(t, ToAPIIssue(db.DefaultContext, il[0]).PullRequest.ChangedFiles)
+}
+
+func TestToAPIIssueList_LastCommitStatus(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// the head of the pull request of issue 2 got a status for two contexts, the one of issue 3 none
+	const sha = "4a357436d925b5c974181ff12a994538ddc5a269"
+	_, err := db.GetEngine(db.DefaultContext).Where("issue_id = ?", 2).Cols("diff_stats_commit_id").
+		Update(&issues_model.PullRequest{DiffStatsCommitID: sha})
+	assert.NoError(t, err)
+	_, err = db.GetEngine(db.DefaultContext).Where("issue_id = ?", 3).Cols("diff_stats_commit_id").
+		Update(&issues_model.PullRequest{DiffStatsCommitID: "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6"})
+	assert.NoError(t, err)
+	for i, status := range []*git_model.CommitStatus{
+		{State: api.CommitStatusPending, Context: "ci", ContextHash: "ci"},
+		{State: api.CommitStatusWarning, Context: "lint", ContextHash: "lint"},
+		{State: api.CommitStatusSuccess, Context: "ci", ContextHash: "ci"},
+	} {
+		status.Index = int64(100 + i)
+		status.RepoID = 1
+		status.SHA = sha
+		status.CreatorID = 2
+		assert.NoError(t, db.Insert(db.DefaultContext, status))
+	}
+
+	il := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 3}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}),
+	}
+	apiIssues := ToAPIIssueList(db.DefaultContext, il, nil)
+	combined := apiIssues[0].PullRequest.LastCommitStatus
+	if assert.NotNil(t, combined) {
+		// the pending status has been superseded, the warning is worse than the success
+		assert.Equal(t, api.CommitStatusWarning, combined.State)
+		assert.Equal(t, sha, combined.SHA)
+		assert.Equal(t, 2, combined.TotalCount)
+		assert.Equal(t, "ci", combined.Statuses[0].Context)
+		assert.Equal(t, api.CommitStatusSuccess, combined.Statuses[0].State)
+		assert.Equal(t, "user2", combined.Statuses[0].Creator.UserName)
+		assert.Equal(t, "lint", combined.Statuses[1].Context)
+	}
+	assert.Nil(t, apiIssues[1].PullRequest.LastCommitStatus)
+	assert.Nil(t, apiIssues[2].PullRequest)
+
+	assert.Equal(t, combined, ToAPIIssue(db.DefaultContext, il[0]).PullRequest.LastCommitStatus)
+}
+
+func TestToAPIIssue_NeedsRebase(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 3})
+	setBase := func(commitID string, changed timeutil.TimeStamp) {
+		_, err := db.GetEngine(db.DefaultContext).Where("issue_id = ?", issue.ID).Cols("base_commit_id", "base_changed_unix").
+			Update(&issues_model.PullRequest{BaseCommitID: commitID, BaseChangedUnix: changed})
+		assert.NoError(t, err)
+		issue.PullRequest = nil
+	}
+
+	// the base has not been stored by a check yet
+	apiIssue := ToAPIIssueList(db.DefaultContext, issues_model.IssueList{issue}, nil)[0]
+	assert.Nil(t, apiIssue.PullRequest.NeedsRebase)
+	assert.Nil(t, apiIssue.PullRequest.BaseChangedSinceReview)
+
+	// the base advanced past the merge base after the latest review
+	setBase("1032bbf17fbc0d9c95bb5418dabe8f8c99278700", 946684820)
+	apiIssue = ToAPIIssueList(db.DefaultContext, issues_model.IssueList{issue}, nil)[0]
+	assert.True(t, *apiIssue.PullRequest.NeedsRebase)
+	assert.True(t, *apiIssue.PullRequest.BaseChangedSinceReview)
+
+	// and before the latest review
+	setBase("1032bbf17fbc0d9c95bb5418dabe8f8c99278700", 946684800)
+	apiIssue, err := ToAPIIssueWithError(db.DefaultContext, issue)
+	assert.NoError(t, err)
+	assert.True(t, *apiIssue.PullRequest.NeedsRebase)
+	assert.False(t, *apiIssue.PullRequest.BaseChangedSinceReview)
+
+	// the base is the merge base
+	setBase("4a357436d925b5c974181ff12a994538ddc5a269", 946684800)
+	apiIssue, err = ToAPIIssueWithError(db.DefaultContext, issue)
+	assert.NoError(t, err)
+	assert.False(t, *apiIssue.PullRequest.NeedsRebase)
+
+	// nothing is reported while the pull request is checked again
+	issue.PullRequest.Status = issues_model.PullRequestStatusChecking
+	assert.Nil(t, ToAPIIssue(db.DefaultContext, issue).PullRequest.NeedsRebase)
+}
+
+func TestToAPIIssueWithError(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	apiIssue, err := ToAPIIssueWithError(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, apiIssue.ID)
+
+	// queries with a cancelled context fail like they would during a database outage
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	failingCtx := db.DefaultContext.(*db.Context).WithContext(cancelled)
+
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		issue   func() *issues_model.Issue
+		field   string
+		missing bool
+	}{
+		{
+			name: "labels",
+			ctx:  failingCtx,
+			issue: func() *issues_model.Issue {
+				return unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+			},
+			field: "labels",
+		},
+		{
+			name: "poster",
+			ctx:  failingCtx,
+			issue: func() *issues_model.Issue {
+				issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+				issue.Labels = []*issues_model.Label{}
+				return issue
+			},
+			field: "poster",
+		},
+		{
+			name: "missing repository",
+			ctx:  db.DefaultContext,
+			issue: func() *issues_model.Issue {
+				issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+				issue.RepoID = unittest.NonexistentID
+				return issue
+			},
+			field:   "repo",
+			missing: true,
+		},
+		{
+			name: "missing owner",
+			ctx:  db.DefaultContext,
+			issue: func() *issues_model.Issue {
+				issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+				issue.Repo = &repo_model.Repository{ID: issue.RepoID, OwnerID: unittest.NonexistentID}
+				return issue
+			},
+			field:   "owner",
+			missing: true,
+		},
+		{
+			name: "milestone",
+			ctx:  failingCtx,
+			issue: func() *issues_model.Issue {
+				issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+				issue.Labels = []*issues_model.Label{}
+				issue.Poster = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: issue.PosterID})
+				issue.Repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+				issue.Repo.Owner = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: issue.Repo.OwnerID})
+				issue.MilestoneID = 1
+				return issue
+			},
+			field: "milestone",
+		},
+		{
+			name: "missing pull request",
+			ctx:  db.DefaultContext,
+			issue: func() *issues_model.Issue {
+				issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+				issue.IsPull = true
+				return issue
+			},
+			field:   "pull_request",
+			missing: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			apiIssue, err := ToAPIIssueWithError(c.ctx, c.issue())
+			assert.Nil(t, apiIssue)
+			assert.True(t, IsErrLoadFailed(err))
+			var errLoadFailed ErrLoadFailed
+			if assert.ErrorAs(t, err, &errLoadFailed) {
+				assert.Equal(t, c.field, errLoadFailed.Field)
+			}
+			assert.Equal(t, c.missing, errors.Is(err, util.ErrNotExist))
+		})
+	}
+
+	// the error is swallowed by ToAPIIssue
+	assert.Equal(t, &api.Issue{}, ToAPIIssue(failingCtx, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})))
+}
+
+func TestToAPIIssueMinimal(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	apiIssue := ToAPIIssueMinimal(db.DefaultContext, issue)
+	assert.Equal(t, &api.Issue{
+		ID:     1,
+		Index:  1,
+		Title:  "issue1",
+		State:  api.StateOpen,
+		Poster: ToUser(unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1}), nil),
+	}, apiIssue)
+	// no other associations have been loaded
+	assert.Nil(t, issue.Labels)
+	assert.Nil(t, issue.Repo)
+
+	issues := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 10}),
+	}
+	apiIssues, err := ToAPIIssueMinimalList(db.DefaultContext, issues)
+	assert.NoError(t, err)
+	if assert.Len(t, apiIssues, 3) {
+		assert.Equal(t, apiIssue, apiIssues[0])
+		assert.Equal(t, api.StateClosed, apiIssues[1].State)
+		assert.Equal(t, "user2", apiIssues[1].Poster.UserName)
+		// the poster of issue 10 does not exist anymore
+		assert.Equal(t, user_model.NewGhostUser().Name, apiIssues[2].Poster.UserName)
+	}
+}
+
+// benchmarkIssues returns count unloaded copies of the fixture issues
+func benchmarkIssues(b *testing.B, count int) issues_model.IssueList {
+	fixtures := make([]*issues_model.Issue, 0, 20)
+	assert.NoError(b, db.GetEngine(db.DefaultContext).Find(&fixtures))
+	issues := make(issues_model.IssueList, count)
+	for i := range issues {
+		issue := *fixtures[i%len(fixtures)]
+		issues[i] = &issue
+	}
+	return issues
+}
+
+func BenchmarkToAPIIssue(b *testing.B) {
+	assert.NoError(b, unittest.PrepareTestDatabase())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		issues := benchmarkIssues(b, 100)
+		b.StartTimer()
+		for _, issue := range issues {
+			ToAPIIssue(db.DefaultContext, issue)
+		}
+	}
+}
+
+func BenchmarkToAPIIssueMinimalList(b *testing.B) {
+	assert.NoError(b, unittest.PrepareTestDatabase())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		issues := benchmarkIssues(b, 100)
+		b.StartTimer()
+		_, err := ToAPIIssueMinimalList(db.DefaultContext, issues)
+		assert.NoError(b, err)
+	}
+}
+
+func TestToAPIIssue_AuthorAssociation(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	association := func(issueID, posterID int64) string {
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issueID})
+		issue.PosterID = posterID
+		return ToAPIIssue(db.DefaultContext, issue).AuthorAssociation
+	}
+
+	// user2 owns repo2
+	assert.Equal(t, api.AuthorAssociationOwner, association(4, 2))
+	// user2 is in the owners team of org3, user4 only in team1
+	assert.Equal(t, api.AuthorAssociationOwner, association(6, 2))
+	assert.Equal(t, api.AuthorAssociationMember, association(6, 4))
+	assert.NoError(t, db.Insert(db.DefaultContext, &repo_model.Collaboration{RepoID: 1, UserID: 4, Mode: perm.AccessModeWrite}))
+	assert.Equal(t, api.AuthorAssociationCollaborator, association(1, 4))
+	// user1 had pull request 1 merged into repo1
+	assert.Equal(t, api.AuthorAssociationContributor, association(1, 1))
+	assert.Equal(t, api.AuthorAssociationNone, association(1, 5))
+
+	issues := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 4}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}),
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+	}
+	issues[1].PosterID = 4
+	issues[2].PosterID = 4
+	issues[3].PosterID = 1
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues, nil)
+	assert.Equal(t, api.AuthorAssociationOwner, apiIssues[0].AuthorAssociation)
+	assert.Equal(t, api.AuthorAssociationMember, apiIssues[1].AuthorAssociation)
+	assert.Equal(t, api.AuthorAssociationCollaborator, apiIssues[2].AuthorAssociation)
+	assert.Equal(t, api.AuthorAssociationContributor, apiIssues[3].AuthorAssociation)
+}
+
+func TestToMilestoneAssigneeWorkload(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// milestone 1 has the unassigned issue 2, issue 1 is assigned to user1 and the closed issue 5 is not counted
+	_, err := db.GetEngine(db.DefaultContext).In("id", 1, 3, 5).Cols("milestone_id").Update(&issues_model.Issue{MilestoneID: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Insert(db.DefaultContext, []*issues_model.IssueAssignees{
+		{IssueID: 1, AssigneeID: 2},
+		{IssueID: 3, AssigneeID: 2},
+		{IssueID: 5, AssigneeID: 1},
+	}))
+
+	milestone := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	assert.Nil(t, ToAPIMilestone(milestone).AssigneeWorkload)
+
+	workload, err := ToMilestoneAssigneeWorkload(db.DefaultContext, milestone, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, workload, 3) {
+		assert.Nil(t, workload[0].Assignee)
+		assert.Equal(t, 1, workload[0].OpenIssues)
+		assert.EqualValues(t, 1, workload[1].Assignee.ID)
+		assert.Equal(t, 1, workload[1].OpenIssues)
+		assert.EqualValues(t, 2, workload[2].Assignee.ID)
+		assert.Equal(t, 2, workload[2].OpenIssues)
+	}
+}
+
+func TestToMilestonePullReadiness(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// milestone 1 has the mergeable pull request of issue 2 and the conflicted one of issue 11,
+	// issue 1 is no pull request and the pull request of issue 3 got an official rejection
+	_, err := db.GetEngine(db.DefaultContext).In("id", 3, 11).Cols("milestone_id").Update(&issues_model.Issue{MilestoneID: 1})
+	assert.NoError(t, err)
+	_, err = db.GetEngine(db.DefaultContext).Where("issue_id = ?", 11).Cols("status").
+		Update(&issues_model.PullRequest{Status: issues_model.PullRequestStatusConflict})
+	assert.NoError(t, err)
+
+	milestone := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	assert.Nil(t, ToAPIMilestone(milestone).PullReadiness)
+
+	readiness, err := ToMilestonePullReadiness(db.DefaultContext, milestone)
+	assert.NoError(t, err)
+	assert.Equal(t, &api.MilestonePullReadiness{Mergeable: 1, Conflicted: 1, ChangesRequested: 1}, readiness)
+
+	// work in progress takes precedence over the requested changes
+	_, err = db.GetEngine(db.DefaultContext).ID(3).Cols("name").Update(&issues_model.Issue{Title: "WIP: issue3"})
+	assert.NoError(t, err)
+	readiness, err = ToMilestonePullReadiness(db.DefaultContext, milestone)
+	assert.NoError(t, err)
+	assert.Equal(t, &api.MilestonePullReadiness{Mergeable: 1, Conflicted: 1, WorkInProgress: 1}, readiness)
+
+	// milestones without pull requests report zeros
+	milestone = unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 2})
+	readiness, err = ToMilestonePullReadiness(db.DefaultContext, milestone)
+	assert.NoError(t, err)
+	assert.Equal(t, &api.MilestonePullReadiness{}, readiness)
+}
+
+func TestToAPIMilestoneWithLabelBreakdown(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	_, err := db.GetEngine(db.DefaultContext).ID(5).Cols("milestone_id").Update(&issues_model.Issue{MilestoneID: 1})
+	assert.NoError(t, err)
+
+	milestone := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	assert.Nil(t, ToAPIMilestone(milestone).LabelBreakdown)
+
+	apiMilestone, err := ToAPIMilestoneWithLabelBreakdown(db.DefaultContext, milestone)
+	assert.NoError(t, err)
+	assert.Equal(t, []api.MilestoneLabelCount{
+		{LabelID: 1, Name: "label1", OpenIssues: 1},
+		{LabelID: 2, Name: "label2", ClosedIssues: 1},
+		{LabelID: 4, Name: "orglabel4", OpenIssues: 1},
+	}, apiMilestone.LabelBreakdown)
+}
+
+func TestToIssueVoteSummary(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	oldReactions, oldReactionsLookup := setting.UI.Reactions, setting.UI.ReactionsLookup
+	defer func() {
+		setting.UI.Reactions, setting.UI.ReactionsLookup = oldReactions, oldReactionsLookup
+	}()
+	setting.UI.Reactions = []string{"+1", "-1", "zzz"}
+	setting.UI.ReactionsLookup = container.SetOf(setting.UI.Reactions...)
+
+	for _, vote := range []struct {
+		userID  int64
+		content string
+	}{{2, "+1"}, {4, "-1"}, {5, "+1"}, {5, "-1"}} {
+		_, err := issues_model.CreateIssueReaction(vote.userID, 1, vote.content)
+		assert.NoError(t, err)
+	}
+
+	for _, c := range []struct {
+		viewerID int64
+		expected string
+	}{
+		{2, "up"},
+		{4, "down"},
+		{5, "down"}, // the later vote counts
+		{1, ""},     // reacted, but not voted
+		{0, ""},
+	} {
+		var viewer *user_model.User
+		if c.viewerID != 0 {
+			viewer = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: c.viewerID})
+		}
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+		summary, err := ToIssueVoteSummary(db.DefaultContext, issue, viewer)
+		assert.NoError(t, err)
+		assert.Equal(t, &api.IssueVoteSummary{Up: 2, Down: 2, ViewerVote: c.expected}, summary, "viewer %d", c.viewerID)
+	}
+
+	// an issue without votes
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	summary, err := ToIssueVoteSummary(db.DefaultContext, issue, unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}))
+	assert.NoError(t, err)
+	assert.Equal(t, &api.IssueVoteSummary{}, summary)
+}
+
+func TestToAPIIssue_ExternalURL(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// repository 1 uses the internal tracker
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	apiIssue, err := ToAPIIssueWithError(db.DefaultContext, issue)
+	assert.NoError(t, err)
+	assert.Empty(t, apiIssue.ExternalURL)
+
+	config := &repo_model.ExternalTrackerConfig{
+		ExternalTrackerURL:    "https://tracker.example.com",
+		ExternalTrackerFormat: "https://tracker.example.com/{user}/{repo}/issues/{index}",
+		ExternalTrackerStyle:  markup.IssueNameStyleNumeric,
+	}
+	assert.NoError(t, db.Insert(db.DefaultContext, &repo_model.RepoUnit{RepoID: 1, Type: unit.TypeExternalTracker, Config: config}))
+
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	apiIssue, err = ToAPIIssueWithError(db.DefaultContext, issue)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://tracker.example.com/user2/repo1/issues/1", apiIssue.ExternalURL)
+
+	// pull requests are not tracked externally
+	pull := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	apiIssue, err = ToAPIIssueWithError(db.DefaultContext, pull)
+	assert.NoError(t, err)
+	assert.Empty(t, apiIssue.ExternalURL)
+
+	// alphanumeric issue names have no relation to the issue index
+	config.ExternalTrackerStyle = markup.IssueNameStyleAlphanumeric
+	_, err = db.GetEngine(db.DefaultContext).Where("repo_id = ? AND type = ?", 1, unit.TypeExternalTracker).Cols("config").Update(&repo_model.RepoUnit{Config: config})
+	assert.NoError(t, err)
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	apiIssue, err = ToAPIIssueWithError(db.DefaultContext, issue)
+	assert.NoError(t, err)
+	assert.Empty(t, apiIssue.ExternalURL)
+}
+
+func TestToAPIIssue_DuplicateOf(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	apiIssue, err := ToAPIIssueWithError(db.DefaultContext, issue)
+	assert.NoError(t, err)
+	assert.Nil(t, apiIssue.DuplicateOf)
+	assert.Nil(t, apiIssue.DuplicateOfRepo)
+
+	for _, c := range []struct {
+		name          string
+		duplicateOfID int64
+		number        int64
+		repo          string
+	}{
+		{name: "same repository", duplicateOfID: 5, number: 4},
+		{name: "public repository", duplicateOfID: 10, number: 1, repo: "user2/glob"},
+		{name: "private repository", duplicateOfID: 4},
+		{name: "deleted issue", duplicateOfID: 9999},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			issue.DuplicateOfID = c.duplicateOfID
+			apiIssue, err := ToAPIIssueWithError(db.DefaultContext, issue)
+			assert.NoError(t, err)
+			if c.number == 0 {
+				assert.Nil(t, apiIssue.DuplicateOf)
+			} else if assert.NotNil(t, apiIssue.DuplicateOf) {
+				assert.Equal(t, c.number, *apiIssue.DuplicateOf)
+			}
+			if c.repo == "" {
+				assert.Nil(t, apiIssue.DuplicateOfRepo)
+			} else if assert.NotNil(t, apiIssue.DuplicateOfRepo) {
+				assert.Equal(t, c.repo, apiIssue.DuplicateOfRepo.FullName)
+			}
+		})
+	}
+
+	// user2 owns the private repository of issue 4
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue.DuplicateOfID = 4
+	apiIssue = ToAPIIssueForViewer(db.DefaultContext, issue, owner, time.Time{})
+	if assert.NotNil(t, apiIssue.DuplicateOf) && assert.NotNil(t, apiIssue.DuplicateOfRepo) {
+		assert.EqualValues(t, 1, *apiIssue.DuplicateOf)
+		assert.Equal(t, "user2/repo2", apiIssue.DuplicateOfRepo.FullName)
+	}
+	apiIssues := ToAPIIssueList(db.DefaultContext, issues_model.IssueList{issue}, owner)
+	assert.NotNil(t, apiIssues[0].DuplicateOf)
+	apiIssues = ToAPIIssueList(db.DefaultContext, issues_model.IssueList{issue}, nil)
+	assert.Nil(t, apiIssues[0].DuplicateOf)
+}
+
+func TestToAPIIssueForViewer_PosterOrgRoles(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// user2 posted the issue and belongs to the teams "Owners" and "team1" of org3
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 12})
+	viewer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	_, err := db.GetEngine(db.DefaultContext).ID(2).Cols("is_public").Update(&organization.Team{IsPublic: true})
+	assert.NoError(t, err)
+
+	// only the public team is shown
+	apiIssue := ToAPIIssueForViewer(db.DefaultContext, issue, viewer, time.Time{})
+	assert.Equal(t, []string{"team1"}, apiIssue.PosterOrgRoles)
+	apiIssue = ToAPIIssueForViewer(db.DefaultContext, issue, nil, time.Time{})
+	assert.Equal(t, []string{"team1"}, apiIssue.PosterOrgRoles)
+
+	// the viewer-less conversion does not report roles
+	assert.Empty(t, ToAPIIssue(db.DefaultContext, issue).PosterOrgRoles)
+
+	// repositories of users have no teams
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.Empty(t, ToAPIIssueForViewer(db.DefaultContext, issue, viewer, time.Time{}).PosterOrgRoles)
+}
+
+func TestToAPIIssue_Estimate(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer func(enabled bool) { setting.Service.EnableTimetracking = enabled }(setting.Service.EnableTimetracking)
+	setting.Service.EnableTimetracking = true
+	// 3682 seconds have been tracked on issue 2, the deleted time does not count
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+
+	for _, c := range []struct {
+		name         string
+		estimate     int64
+		overEstimate bool
+	}{
+		{name: "under", estimate: 7200, overEstimate: false},
+		{name: "exact", estimate: 3682, overEstimate: false},
+		{name: "over", estimate: 3600, overEstimate: true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			issue.Estimate = c.estimate
+			apiIssue, err := ToAPIIssueWithError(db.DefaultContext, issue)
+			assert.NoError(t, err)
+			if assert.NotNil(t, apiIssue.Estimate) && assert.NotNil(t, apiIssue.TimeSpent) && assert.NotNil(t, apiIssue.OverEstimate) {
+				assert.Equal(t, c.estimate, *apiIssue.Estimate)
+				assert.EqualValues(t, 3682, *apiIssue.TimeSpent)
+				assert.Equal(t, c.overEstimate, *apiIssue.OverEstimate)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		other := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+		other.Estimate = 60
+		spent, err := issues_model.GetTrackedSeconds(db.DefaultContext, issues_model.FindTrackedTimesOptions{IssueID: other.ID})
+		assert.NoError(t, err)
+		issue.Estimate = 7200
+		apiIssues := ToAPIIssueList(db.DefaultContext, issues_model.IssueList{issue, other}, nil)
+		if assert.NotNil(t, apiIssues[0].TimeSpent) && assert.NotNil(t, apiIssues[1].TimeSpent) {
+			assert.EqualValues(t, 3682, *apiIssues[0].TimeSpent)
+			assert.Equal(t, spent, *apiIssues[1].TimeSpent)
+		}
+	})
+
+	assertNoEstimate := func(t *testing.T) {
+		apiIssue, err := ToAPIIssueWithError(db.DefaultContext, issue)
+		assert.NoError(t, err)
+		assert.Nil(t, apiIssue.Estimate)
+		assert.Nil(t, apiIssue.TimeSpent)
+		assert.Nil(t, apiIssue.OverEstimate)
+	}
+
+	t.Run("no estimate", func(t *testing.T) {
+		issue.Estimate = 0
+		assertNoEstimate(t)
+	})
+
+	t.Run("time tracker disabled", func(t *testing.T) {
+		setting.Service.EnableTimetracking = false
+		issue.Estimate = 3600
+		assertNoEstimate(t)
+	})
+}
+
+func TestToAPIIssueList_WatchersCount(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	// issue 7 is watched by user 2 and muted by user 1
+	assert.NoError(t, issues_model.CreateOrUpdateIssueWatch(4, 7, true))
+	il := issues_model.IssueList{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 7}),
+		// only muted by user 2
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2}),
+	}
+
+	apiIssues := ToAPIIssueList(db.DefaultContext, il, nil)
+	if assert.Len(t, apiIssues, 2) {
+		assert.Equal(t, 2, apiIssues[0].WatchersCount)
+		assert.Equal(t, 0, apiIssues[1].WatchersCount)
+	}
+	assert.Equal(t, 2, ToAPIIssue(db.DefaultContext, il[0]).WatchersCount)
+}
+
+func TestToUserTimeDashboard(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer timeutil.Unset()
+	now := time.Date(2022, 11, 15, 12, 0, 0, 0, setting.DefaultUILocation)
+	timeutil.Set(now)
+
+	// user 4 has no access to the private repo2 of issue 4
+	for _, bean := range []interface{}{
+		&issues_model.Stopwatch{UserID: 4, IssueID: 1, CreatedUnix: timeutil.TimeStamp(now.Add(-time.Minute).Unix())},
+		&issues_model.Stopwatch{UserID: 4, IssueID: 4, CreatedUnix: timeutil.TimeStamp(now.Add(-time.Hour).Unix())},
+		&issues_model.TrackedTime{UserID: 4, IssueID: 1, Time: 600, CreatedUnix: now.Add(-2 * time.Hour).Unix()},
+		&issues_model.TrackedTime{UserID: 4, IssueID: 4, Time: 1200, CreatedUnix: now.Add(-2 * time.Hour).Unix()},
+		// tracked yesterday
+		&issues_model.TrackedTime{UserID: 4, IssueID: 1, Time: 1800, CreatedUnix: now.Add(-24 * time.Hour).Unix()},
+	} {
+		_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(bean)
+		assert.NoError(t, err)
+	}
+
+	dashboard, err := ToUserTimeDashboard(db.DefaultContext, 4)
+	assert.NoError(t, err)
+	if assert.Len(t, dashboard.StopWatches, 1) {
+		assert.Equal(t, "repo1", dashboard.StopWatches[0].RepoName)
+		assert.EqualValues(t, 1, dashboard.StopWatches[0].IssueIndex)
+	}
+	if assert.Len(t, dashboard.TrackedToday, 1) {
+		assert.EqualValues(t, 600, dashboard.TrackedToday[0].Time)
+		assert.Equal(t, "user4", dashboard.TrackedToday[0].UserName)
+		assert.EqualValues(t, 1, dashboard.TrackedToday[0].Issue.Index)
+	}
+	assert.EqualValues(t, 60, dashboard.StopWatchSeconds)
+	assert.EqualValues(t, 600, dashboard.TrackedTodaySeconds)
+	assert.EqualValues(t, 660, dashboard.TotalSeconds)
+}
+
+func TestToAPIIssueForViewerWithAvatarSize(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// user1 and user2 are assigned to issue 6
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})
+	viewer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	poster := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: issue.PosterID})
+	user1 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	apiIssue := ToAPIIssueForViewerWithAvatarSize(db.DefaultContext, issue, viewer, time.Time{}, 64)
+	assert.Equal(t, poster.AvatarFullLinkWithSize(64), apiIssue.Poster.AvatarURL)
+	if assert.Len(t, apiIssue.Assignees, 2) {
+		assert.Equal(t, user1.AvatarFullLinkWithSize(64), apiIssue.Assignees[0].AvatarURL)
+		assert.Equal(t, user2.AvatarFullLinkWithSize(64), apiIssue.Assignees[1].AvatarURL)
+	}
+	assert.Equal(t, user1.AvatarFullLinkWithSize(64), apiIssue.Assignee.AvatarURL)
+	assert.Contains(t, apiIssue.Assignee.AvatarURL, "s=64")
+
+	// without a size the links stay unsized
+	apiIssue = ToAPIIssueForViewer(db.DefaultContext, issue, viewer, time.Time{})
+	assert.Equal(t, poster.AvatarLink(), apiIssue.Poster.AvatarURL)
+	assert.Equal(t, user1.AvatarLink(), apiIssue.Assignee.AvatarURL)
+	assert.NotEqual(t, user1.AvatarFullLinkWithSize(64), apiIssue.Assignee.AvatarURL)
+}
+
+func TestToAPIIssueForWebhook(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// user1 with a public email is assigned to issue 1
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	issue.Content = "ünïcode body of the issue"
+
+	apiIssue := ToAPIIssueForWebhook(db.DefaultContext, issue, webhook_model.IssuePayloadSettings{})
+	assert.Equal(t, issue.Content, apiIssue.Body)
+	assert.Equal(t, "user1@example.com", apiIssue.Assignee.Email)
+	assert.Equal(t, "user1@example.com", apiIssue.Assignees[0].Email)
+
+	apiIssue = ToAPIIssueForWebhook(db.DefaultContext, issue, webhook_model.IssuePayloadSettings{BodyMode: webhook_model.IssueBodyOmitted})
+	assert.Empty(t, apiIssue.Body)
+	assert.Equal(t, issue.Title, apiIssue.Title)
+
+	apiIssue = ToAPIIssueForWebhook(db.DefaultContext, issue, webhook_model.IssuePayloadSettings{BodyMode: webhook_model.IssueBodyTruncated, BodyMaxLength: 8})
+	assert.Equal(t, "ünïcode…", apiIssue.Body)
+
+	// bodies shorter than the limit are kept
+	apiIssue = ToAPIIssueForWebhook(db.DefaultContext, issue, webhook_model.IssuePayloadSettings{BodyMode: webhook_model.IssueBodyTruncated})
+	assert.Equal(t, issue.Content, apiIssue.Body)
+
+	apiIssue = ToAPIIssueForWebhook(db.DefaultContext, issue, webhook_model.IssuePayloadSettings{StripAssigneeEmails: true})
+	assert.Empty(t, apiIssue.Assignee.Email)
+	assert.Empty(t, apiIssue.Assignees[0].Email)
+	assert.Equal(t, "user1@example.com", apiIssue.Poster.Email)
+
+	// the standard conversion stays complete
+	assert.Equal(t, issue.Content, ToAPIIssue(db.DefaultContext, issue).Body)
+}
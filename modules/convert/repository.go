@@ -50,6 +50,7 @@ func innerToRepo(repo *repo_model.Repository, mode perm.AccessMode, isParent boo
 			EnableTimeTracker:                config.EnableTimetracker,
 			AllowOnlyContributorsToTrackTime: config.AllowOnlyContributorsToTrackTime,
 			EnableIssueDependencies:          config.EnableDependencies,
+			AutoCloseMilestones:              config.AutoCloseMilestones,
 		}
 	} else if unit, err := repo.GetUnit(unit_model.TypeExternalTracker); err == nil {
 		config := unit.ExternalTrackerConfig()
@@ -23,7 +23,10 @@ func ToCommitStatus(status *git_model.CommitStatus) *api.CommitStatus {
 	}
 
 	if status.CreatorID != 0 {
-		creator, _ := user_model.GetUserByID(status.CreatorID)
+		creator := status.Creator
+		if creator == nil {
+			creator, _ = user_model.GetUserByID(status.CreatorID)
+		}
 		apiStatus.Creator = ToUser(creator, nil)
 	}
 
@@ -0,0 +1,140 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// stopWatchCacheMaxEntries limits the number of issues and of repositories kept by a StopWatchCache
+const stopWatchCacheMaxEntries = 10000
+
+type stopWatchCacheEntry[T any] struct {
+	value   T
+	expires timeutil.TimeStamp
+}
+
+// StopWatchCache caches the issues and repositories looked up by ToStopWatchesWithCache, so that
+// converting the stopwatches of many users shares the lookups. It is safe for concurrent use.
+// Entries expire after the TTL, so renamed repositories and issues are picked up again, and
+// expired entries are dropped once the cache is full.
+type StopWatchCache struct {
+	ttl        int64
+	maxEntries int
+
+	mu     sync.Mutex
+	issues map[int64]stopWatchCacheEntry[*issues_model.Issue]
+	repos  map[int64]stopWatchCacheEntry[*repo_model.Repository]
+}
+
+// NewStopWatchCache creates a cache whose entries expire after the given TTL
+func NewStopWatchCache(ttl time.Duration) *StopWatchCache {
+	return &StopWatchCache{
+		ttl:        int64(ttl / time.Second),
+		maxEntries: stopWatchCacheMaxEntries,
+		issues:     make(map[int64]stopWatchCacheEntry[*issues_model.Issue]),
+		repos:      make(map[int64]stopWatchCacheEntry[*repo_model.Repository]),
+	}
+}
+
+// getCached returns the values of the IDs which are cached and not expired, and the IDs which are not
+func getCached[T any](entries map[int64]stopWatchCacheEntry[T], ids []int64, now timeutil.TimeStamp) (map[int64]T, []int64) {
+	found := make(map[int64]T, len(ids))
+	missing := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if entry, ok := entries[id]; ok && now < entry.expires {
+			found[id] = entry.value
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return found, missing
+}
+
+// putCached stores the values until expires. If there is no room for them, the expired entries are
+// dropped first and then arbitrary ones, so that no more than maxEntries entries are kept.
+func putCached[T any](entries map[int64]stopWatchCacheEntry[T], values map[int64]T, expires, now timeutil.TimeStamp, maxEntries int) {
+	if len(entries)+len(values) > maxEntries {
+		for id, entry := range entries {
+			if now >= entry.expires {
+				delete(entries, id)
+			}
+		}
+	}
+	for id := range entries {
+		if len(entries)+len(values) <= maxEntries {
+			break
+		}
+		if _, ok := values[id]; !ok {
+			delete(entries, id)
+		}
+	}
+	for id, value := range values {
+		if _, ok := entries[id]; !ok && len(entries) >= maxEntries {
+			continue
+		}
+		entries[id] = stopWatchCacheEntry[T]{value: value, expires: expires}
+	}
+}
+
+// getIssues returns the issues by their IDs, issues which do not exist are not contained.
+// The cached issues are shared, so their Repo is not set and they must not be modified.
+// A nil cache loads all of them without caching.
+func (c *StopWatchCache) getIssues(ctx context.Context, ids []int64) (map[int64]*issues_model.Issue, error) {
+	if c == nil {
+		return batchLoad(ctx, ids, func(issue *issues_model.Issue) int64 { return issue.ID })
+	}
+	now := timeutil.TimeStampNow()
+	c.mu.Lock()
+	issueMap, missing := getCached(c.issues, ids, now)
+	c.mu.Unlock()
+	if len(missing) == 0 {
+		return issueMap, nil
+	}
+
+	issues, err := batchLoad(ctx, missing, func(issue *issues_model.Issue) int64 { return issue.ID })
+	if err != nil {
+		return nil, err
+	}
+	for id, issue := range issues {
+		issueMap[id] = issue
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	putCached(c.issues, issues, now.Add(c.ttl), now, c.maxEntries)
+	return issueMap, nil
+}
+
+// getRepos returns the repositories by their IDs, repositories which do not exist are not contained.
+// A nil cache loads all of them without caching.
+func (c *StopWatchCache) getRepos(ctx context.Context, ids []int64) (map[int64]*repo_model.Repository, error) {
+	if c == nil {
+		return batchLoad(ctx, ids, func(repo *repo_model.Repository) int64 { return repo.ID })
+	}
+	now := timeutil.TimeStampNow()
+	c.mu.Lock()
+	repoMap, missing := getCached(c.repos, ids, now)
+	c.mu.Unlock()
+	if len(missing) == 0 {
+		return repoMap, nil
+	}
+
+	repos, err := batchLoad(ctx, missing, func(repo *repo_model.Repository) int64 { return repo.ID })
+	if err != nil {
+		return nil, err
+	}
+	for id, repo := range repos {
+		repoMap[id] = repo
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	putCached(c.repos, repos, now.Add(c.ttl), now, c.maxEntries)
+	return repoMap, nil
+}
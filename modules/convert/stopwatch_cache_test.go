@@ -0,0 +1,89 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToStopWatchesWithCache(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	sws := []*issues_model.Stopwatch{
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Stopwatch{ID: 1}),
+		{UserID: 1, IssueID: 4},
+	}
+
+	defer timeutil.Unset()
+	now := time.Now()
+	timeutil.Set(now)
+
+	cache := NewStopWatchCache(time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			apiSWs, err := ToStopWatchesWithCache(db.DefaultContext, sws, cache)
+			assert.NoError(t, err)
+			if assert.Len(t, apiSWs, 2) {
+				assert.Equal(t, "repo1", apiSWs[0].RepoName)
+				assert.Equal(t, "repo2", apiSWs[1].RepoName)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// a renamed repository is picked up once the cached entry has expired
+	_, err := db.GetEngine(db.DefaultContext).ID(1).Cols("name").Update(&repo_model.Repository{Name: "renamed"})
+	assert.NoError(t, err)
+	apiSWs, err := ToStopWatchesWithCache(db.DefaultContext, sws, cache)
+	assert.NoError(t, err)
+	assert.Equal(t, "repo1", apiSWs[0].RepoName)
+
+	timeutil.Set(now.Add(time.Minute))
+	apiSWs, err = ToStopWatchesWithCache(db.DefaultContext, sws, cache)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", apiSWs[0].RepoName)
+
+	// without a shared cache nothing is kept between calls
+	_, err = db.GetEngine(db.DefaultContext).ID(1).Cols("name").Update(&repo_model.Repository{Name: "repo1"})
+	assert.NoError(t, err)
+	apiSWs, err = ToStopWatches(db.DefaultContext, sws)
+	assert.NoError(t, err)
+	assert.Equal(t, "repo1", apiSWs[0].RepoName)
+}
+
+func TestPutCached(t *testing.T) {
+	now := timeutil.TimeStamp(100)
+	entries := map[int64]stopWatchCacheEntry[string]{
+		1: {value: "expired", expires: now},
+		2: {value: "valid", expires: now + 10},
+	}
+
+	// expired entries are dropped before valid ones once the cache is full
+	putCached(entries, map[int64]string{3: "new"}, now+10, now, 2)
+	assert.Len(t, entries, 2)
+	assert.NotContains(t, entries, int64(1))
+	assert.Equal(t, "valid", entries[2].value)
+	assert.Equal(t, "new", entries[3].value)
+
+	// the cache never grows beyond its limit
+	putCached(entries, map[int64]string{4: "a", 5: "b", 6: "c"}, now+10, now, 2)
+	assert.Len(t, entries, 2)
+
+	// refreshing a cached entry needs no room
+	entries = map[int64]stopWatchCacheEntry[string]{7: {value: "old", expires: now + 10}}
+	putCached(entries, map[int64]string{7: "refreshed"}, now+20, now, 1)
+	assert.Equal(t, stopWatchCacheEntry[string]{value: "refreshed", expires: now + 20}, entries[7])
+}
@@ -24,6 +24,15 @@ func ToUser(user, doer *user_model.User) *api.User {
 	return toUser(user, signed, authed)
 }
 
+// ToPoster converts the poster of an issue or a comment like ToUser. Posters which have been deleted or
+// which never existed locally, like the authors of migrated content, are reported as the ghost user.
+func ToPoster(poster, doer *user_model.User) *api.User {
+	if poster == nil || poster.ID <= 0 {
+		poster = user_model.NewGhostUser()
+	}
+	return ToUser(poster, doer)
+}
+
 // ToUsers convert list of user_model.User to list of api.User
 func ToUsers(doer *user_model.User, users []*user_model.User) []*api.User {
 	result := make([]*api.User, len(users))
@@ -90,8 +90,10 @@ loop:
 					return
 				}
 
+				// users often track time on the same issues, so the lookups are shared
+				swCache := convert.NewStopWatchCache(setting.UI.Notification.EventSourceUpdateTime)
 				for _, userStopwatches := range usersStopwatches {
-					apiSWs, err := convert.ToStopWatches(userStopwatches.StopWatches)
+					apiSWs, err := convert.ToStopWatchesWithCache(ctx, userStopwatches.StopWatches, swCache)
 					if err != nil {
 						if !issues_model.IsErrIssueNotExist(err) {
 							log.Error("Unable to APIFormat stopwatches: %v", err)
@@ -14,6 +14,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/util"
@@ -188,7 +189,36 @@ func (c *Commit) CommitsCount() (int64, error) {
 
 // CommitsByRange returns the specific page commits before current revision, every page's number default by CommitsRangeSize
 func (c *Commit) CommitsByRange(page, pageSize int) ([]*Commit, error) {
-	return c.repo.commitsByRange(c.ID, page, pageSize)
+	return c.repo.commitsByRange(c.ID, page, pageSize, time.Time{}, time.Time{})
+}
+
+// CommitsCountBetweenDates returns number of commits until current revision which were committed between since and until,
+// a zero time leaves the corresponding bound open.
+func (c *Commit) CommitsCountBetweenDates(since, until time.Time) (int64, error) {
+	cmd := NewCommand(c.repo.Ctx, "rev-list", "--count").AddArguments(dateRangeArguments(since, until)...).AddDynamicArguments(c.ID.String())
+	stdout, _, err := cmd.RunStdString(&RunOpts{Dir: c.repo.Path})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+}
+
+// CommitsByRangeBetweenDates returns the specific page of commits before current revision which were committed
+// between since and until, a zero time leaves the corresponding bound open.
+func (c *Commit) CommitsByRangeBetweenDates(page, pageSize int, since, until time.Time) ([]*Commit, error) {
+	return c.repo.commitsByRange(c.ID, page, pageSize, since, until)
+}
+
+// dateRangeArguments returns the git log arguments limiting the commits to the ones committed between since and until
+func dateRangeArguments(since, until time.Time) []CmdArg {
+	var args []CmdArg
+	if !since.IsZero() {
+		args = append(args, CmdArg("--since="+since.Format(time.RFC3339)))
+	}
+	if !until.IsZero() {
+		args = append(args, CmdArg("--until="+until.Format(time.RFC3339)))
+	}
+	return args
 }
 
 // CommitsBefore returns all the commits before current revision
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -19,6 +20,43 @@ func TestCommitsCount(t *testing.T) {
 	assert.Equal(t, int64(3), commitsCount)
 }
 
+func TestCommitsByRangeBetweenDates(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := openRepositoryWithDefaultContext(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	commit, err := bareRepo1.GetCommit("37991dec2c8e592043f47155ce4808d4580f9123")
+	assert.NoError(t, err)
+
+	since := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2018, time.April, 19, 0, 0, 0, 0, time.UTC)
+
+	count, err := commit.CommitsCountBetweenDates(since, time.Time{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	count, err = commit.CommitsCountBetweenDates(time.Time{}, until)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+	count, err = commit.CommitsCountBetweenDates(since, until)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	commits, err := commit.CommitsByRangeBetweenDates(1, 10, since, until)
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 2) {
+		assert.Equal(t, "6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1", commits[0].ID.String())
+		assert.Equal(t, "8006ff9adbf0cb94da7dad9e537e53817f9fa5c0", commits[1].ID.String())
+	}
+
+	// the window combines with the pagination
+	commits, err = commit.CommitsByRangeBetweenDates(2, 1, since, until)
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 1) {
+		assert.Equal(t, "8006ff9adbf0cb94da7dad9e537e53817f9fa5c0", commits[0].ID.String())
+	}
+}
+
 func TestGetFullCommitID(t *testing.T) {
 	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
 
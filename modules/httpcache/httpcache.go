@@ -83,6 +83,21 @@ func HandleGenericETagCache(req *http.Request, w http.ResponseWriter, etag strin
 	return false
 }
 
+// HandleGenericETagRevalidation handles ETag-based caching for a HTTP request of a frequently changing
+// resource, clients have to revalidate their cached copy on every use.
+// It returns true if the request was handled.
+func HandleGenericETagRevalidation(req *http.Request, w http.ResponseWriter, etag string) (handled bool) {
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if len(etag) > 0 {
+		w.Header().Set("Etag", etag)
+		if checkIfNoneMatchIsValid(req, etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
 // checkIfNoneMatchIsValid tests if the header If-None-Match matches the ETag
 func checkIfNoneMatchIsValid(req *http.Request, etag string) bool {
 	ifNoneMatch := req.Header.Get("If-None-Match")
@@ -152,3 +152,33 @@ func TestHandleGenericETagCache(t *testing.T) {
 		assert.Equal(t, http.StatusNotModified, w.Code)
 	})
 }
+
+func TestHandleGenericETagRevalidation(t *testing.T) {
+	etag := `"test"`
+
+	t.Run("Wrong_If-None-Match", func(t *testing.T) {
+		req := &http.Request{Header: make(http.Header)}
+		w := httptest.NewRecorder()
+
+		req.Header.Set("If-None-Match", `"wrong etag"`)
+
+		handled := HandleGenericETagRevalidation(req, w, etag)
+
+		assert.False(t, handled)
+		assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+		assert.Equal(t, etag, w.Header().Get("Etag"))
+	})
+	t.Run("Correct_If-None-Match", func(t *testing.T) {
+		req := &http.Request{Header: make(http.Header)}
+		w := httptest.NewRecorder()
+
+		req.Header.Set("If-None-Match", etag)
+
+		handled := HandleGenericETagRevalidation(req, w, etag)
+
+		assert.True(t, handled)
+		assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+		assert.Equal(t, etag, w.Header().Get("Etag"))
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+}
@@ -17,9 +17,10 @@ import (
 // Sanitizer is a protection wrapper of *bluemonday.Policy which does not allow
 // any modification to the underlying policies once it's been created.
 type Sanitizer struct {
-	defaultPolicy    *bluemonday.Policy
-	rendererPolicies map[string]*bluemonday.Policy
-	init             sync.Once
+	defaultPolicy     *bluemonday.Policy
+	descriptionPolicy *bluemonday.Policy
+	rendererPolicies  map[string]*bluemonday.Policy
+	init              sync.Once
 }
 
 var sanitizer = &Sanitizer{}
@@ -37,6 +38,7 @@ func NewSanitizer() {
 func InitializeSanitizer() {
 	sanitizer.rendererPolicies = map[string]*bluemonday.Policy{}
 	sanitizer.defaultPolicy = createDefaultPolicy()
+	sanitizer.descriptionPolicy = createDescriptionPolicy()
 
 	for name, renderer := range renderers {
 		sanitizerRules := renderer.SanitizerRules()
@@ -142,6 +144,16 @@ func createDefaultPolicy() *bluemonday.Policy {
 	return policy
 }
 
+// createDescriptionPolicy only allows inline formatting and links, which is enough for short descriptions shown e.g. in tooltips
+func createDescriptionPolicy() *bluemonday.Policy {
+	policy := bluemonday.NewPolicy()
+	policy.AllowStandardURLs()
+	policy.AllowAttrs("href").OnElements("a")
+	policy.RequireNoFollowOnLinks(true)
+	policy.AllowElements("p", "br", "em", "strong", "del", "code")
+	return policy
+}
+
 func addSanitizerRules(policy *bluemonday.Policy, rules []setting.MarkupSanitizerRule) {
 	for _, rule := range rules {
 		if rule.AllowDataURIImages {
@@ -163,6 +175,12 @@ func Sanitize(s string) string {
 	return sanitizer.defaultPolicy.Sanitize(s)
 }
 
+// SanitizeDescription applies a strict policy which only keeps inline formatting and links
+func SanitizeDescription(s string) string {
+	NewSanitizer()
+	return sanitizer.descriptionPolicy.Sanitize(s)
+}
+
 // SanitizeReader sanitizes a Reader
 func SanitizeReader(r io.Reader, renderer string, w io.Writer) error {
 	NewSanitizer()
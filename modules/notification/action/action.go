@@ -88,6 +88,35 @@ func (a *actionNotifier) NotifyIssueChangeStatus(ctx context.Context, doer *user
 	}
 }
 
+// NotifyMilestoneChangeStatus notifies watchers that a milestone has been closed or reopened
+func (a *actionNotifier) NotifyMilestoneChangeStatus(ctx context.Context, doer *user_model.User, milestone *issues_model.Milestone) {
+	if milestone.Repo == nil {
+		repo, err := repo_model.GetRepositoryByIDCtx(ctx, milestone.RepoID)
+		if err != nil {
+			log.Error("GetRepositoryByIDCtx: %v", err)
+			return
+		}
+		milestone.Repo = repo
+	}
+
+	opType := activities_model.ActionReopenMilestone
+	if milestone.IsClosed {
+		opType = activities_model.ActionCloseMilestone
+	}
+
+	if err := activities_model.NotifyWatchers(ctx, &activities_model.Action{
+		ActUserID: doer.ID,
+		ActUser:   doer,
+		OpType:    opType,
+		RepoID:    milestone.RepoID,
+		Repo:      milestone.Repo,
+		IsPrivate: milestone.Repo.IsPrivate,
+		Content:   fmt.Sprintf("%d|%s", milestone.ID, milestone.Name),
+	}); err != nil {
+		log.Error("NotifyWatchers: %v", err)
+	}
+}
+
 // NotifyCreateIssueComment notifies comment on an issue to notifiers
 func (a *actionNotifier) NotifyCreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository,
 	issue *issues_model.Issue, comment *issues_model.Comment, mentions []*user_model.User,
@@ -83,6 +83,14 @@ func (*NullNotifier) NotifyUpdateComment(ctx context.Context, doer *user_model.U
 func (*NullNotifier) NotifyDeleteComment(ctx context.Context, doer *user_model.User, c *issues_model.Comment) {
 }
 
+// NotifyIssueAddTrackedTime places a place holder function
+func (*NullNotifier) NotifyIssueAddTrackedTime(ctx context.Context, doer *user_model.User, t *issues_model.TrackedTime) {
+}
+
+// NotifyIssueDeleteTrackedTime places a place holder function
+func (*NullNotifier) NotifyIssueDeleteTrackedTime(ctx context.Context, doer *user_model.User, t *issues_model.TrackedTime) {
+}
+
 // NotifyNewWikiPage places a place holder function
 func (*NullNotifier) NotifyNewWikiPage(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, page, comment string) {
 }
@@ -111,6 +119,10 @@ func (*NullNotifier) NotifyDeleteRelease(ctx context.Context, doer *user_model.U
 func (*NullNotifier) NotifyIssueChangeMilestone(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldMilestoneID int64) {
 }
 
+// NotifyMilestoneChangeStatus places a place holder function
+func (*NullNotifier) NotifyMilestoneChangeStatus(ctx context.Context, doer *user_model.User, milestone *issues_model.Milestone) {
+}
+
 // NotifyIssueChangeContent places a place holder function
 func (*NullNotifier) NotifyIssueChangeContent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldContent string) {
 }
@@ -169,6 +169,20 @@ func NotifyDeleteComment(ctx context.Context, doer *user_model.User, c *issues_m
 	}
 }
 
+// NotifyIssueAddTrackedTime notifies time added to an issue to notifiers
+func NotifyIssueAddTrackedTime(ctx context.Context, doer *user_model.User, t *issues_model.TrackedTime) {
+	for _, notifier := range notifiers {
+		notifier.NotifyIssueAddTrackedTime(ctx, doer, t)
+	}
+}
+
+// NotifyIssueDeleteTrackedTime notifies time deleted from an issue to notifiers
+func NotifyIssueDeleteTrackedTime(ctx context.Context, doer *user_model.User, t *issues_model.TrackedTime) {
+	for _, notifier := range notifiers {
+		notifier.NotifyIssueDeleteTrackedTime(ctx, doer, t)
+	}
+}
+
 // NotifyNewRelease notifies new release to notifiers
 func NotifyNewRelease(ctx context.Context, rel *repo_model.Release) {
 	for _, notifier := range notifiers {
@@ -197,6 +211,13 @@ func NotifyIssueChangeMilestone(ctx context.Context, doer *user_model.User, issu
 	}
 }
 
+// NotifyMilestoneChangeStatus notifies milestone closed or reopened to notifiers
+func NotifyMilestoneChangeStatus(ctx context.Context, doer *user_model.User, milestone *issues_model.Milestone) {
+	for _, notifier := range notifiers {
+		notifier.NotifyMilestoneChangeStatus(ctx, doer, milestone)
+	}
+}
+
 // NotifyIssueChangeContent notifies change content to notifiers
 func NotifyIssueChangeContent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldContent string) {
 	for _, notifier := range notifiers {
@@ -449,6 +449,63 @@ func (m *webhookNotifier) NotifyDeleteComment(ctx context.Context, doer *user_mo
 	}
 }
 
+func (m *webhookNotifier) NotifyIssueAddTrackedTime(ctx context.Context, doer *user_model.User, t *issues_model.TrackedTime) {
+	notifyIssueTrackedTime(ctx, doer, t, api.HookIssueTrackedTimeAdded)
+}
+
+func (m *webhookNotifier) NotifyIssueDeleteTrackedTime(ctx context.Context, doer *user_model.User, t *issues_model.TrackedTime) {
+	notifyIssueTrackedTime(ctx, doer, t, api.HookIssueTrackedTimeDeleted)
+}
+
+func notifyIssueTrackedTime(ctx context.Context, doer *user_model.User, t *issues_model.TrackedTime, action api.HookIssueTrackedTimeAction) {
+	if err := t.LoadAttributes(); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+	if err := t.Issue.LoadAttributes(ctx); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+
+	mode, _ := access_model.AccessLevel(ctx, doer, t.Issue.Repo)
+	if err := webhook_services.PrepareWebhooks(ctx, webhook_services.EventSource{Repository: t.Issue.Repo}, webhook.HookEventIssueTimeTracked, &api.IssueTrackedTimePayload{
+		Action:      action,
+		Index:       t.Issue.Index,
+		Issue:       convert.ToAPIIssue(ctx, t.Issue),
+		TrackedTime: convert.ToTrackedTime(ctx, t),
+		Repository:  convert.ToRepo(t.Issue.Repo, mode),
+		Sender:      convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks [tracked_time_id: %d]: %v", t.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyMilestoneChangeStatus(ctx context.Context, doer *user_model.User, milestone *issues_model.Milestone) {
+	if milestone.Repo == nil {
+		repo, err := repo_model.GetRepositoryByIDCtx(ctx, milestone.RepoID)
+		if err != nil {
+			log.Error("GetRepositoryByIDCtx: %v", err)
+			return
+		}
+		milestone.Repo = repo
+	}
+
+	action := api.HookMilestoneReopened
+	if milestone.IsClosed {
+		action = api.HookMilestoneClosed
+	}
+
+	mode, _ := access_model.AccessLevel(ctx, doer, milestone.Repo)
+	if err := webhook_services.PrepareWebhooks(ctx, webhook_services.EventSource{Repository: milestone.Repo}, webhook.HookEventMilestone, &api.MilestonePayload{
+		Action:     action,
+		Milestone:  convert.ToAPIMilestone(milestone),
+		Repository: convert.ToRepo(milestone.Repo, mode),
+		Sender:     convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks [milestone_id: %d]: %v", milestone.ID, err)
+	}
+}
+
 func (m *webhookNotifier) NotifyNewWikiPage(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, page, comment string) {
 	// Add to hook queue for created wiki page.
 	if err := webhook_services.PrepareWebhooks(ctx, webhook_services.EventSource{Repository: repo}, webhook.HookEventWiki, &api.WikiPayload{
@@ -350,6 +350,25 @@ func FindRenderizableReferenceNumeric(content string, prOnly bool) (bool, *Rende
 	}
 }
 
+// FindAllRenderizableReferencesNumeric returns all unvalidated numeric references found in a string,
+// together with their location in the content.
+func FindAllRenderizableReferencesNumeric(content string) []*RenderizableReference {
+	raws := findAllIssueReferencesBytes([]byte(content), nil)
+	refs := make([]*RenderizableReference, 0, len(raws))
+	for _, r := range raws {
+		refs = append(refs, &RenderizableReference{
+			Issue:          r.issue,
+			Owner:          r.owner,
+			Name:           r.name,
+			IsPull:         r.isPull,
+			RefLocation:    r.refLocation,
+			Action:         r.action,
+			ActionLocation: r.actionLocation,
+		})
+	}
+	return refs
+}
+
 // FindRenderizableReferenceRegexp returns the first regexp unvalidated references found in a string.
 func FindRenderizableReferenceRegexp(content string, pattern *regexp.Regexp) (bool, *RenderizableReference) {
 	match := pattern.FindStringSubmatchIndex(content)
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -142,6 +143,31 @@ func loadLabels(labelTemplate string) ([]string, error) {
 	return labels, nil
 }
 
+// DefaultLabelTemplate is the label template file of the instance whose labels count as default labels
+const DefaultLabelTemplate = "Default"
+
+var (
+	defaultLabelsOnce sync.Once
+	defaultLabels     map[string]string
+)
+
+// IsDefaultLabel returns true if a label with the given name and color is part of the default label template.
+// Names are compared case-insensitively, colors may be given with or without a leading "#".
+func IsDefaultLabel(name, color string) bool {
+	defaultLabelsOnce.Do(func() {
+		list, err := GetLabelTemplateFile(DefaultLabelTemplate)
+		if err != nil {
+			log.Error("Failed to load default labels: %v", err)
+		}
+		defaultLabels = make(map[string]string, len(list))
+		for _, label := range list {
+			defaultLabels[strings.ToLower(label[0])] = strings.ToLower(strings.TrimPrefix(label[1], "#"))
+		}
+	})
+	defaultColor, ok := defaultLabels[strings.ToLower(name)]
+	return ok && defaultColor == strings.ToLower(strings.TrimPrefix(color, "#"))
+}
+
 // LoadLabelsFormatted loads the labels' list of a template file as a string separated by comma
 func LoadLabelsFormatted(labelTemplate string) (string, error) {
 	labels, err := loadLabels(labelTemplate)
@@ -3,6 +3,14 @@
 
 package setting
 
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
 // settings
 var (
 	// Picture settings
@@ -11,15 +19,32 @@ var (
 
 		MaxWidth           int
 		MaxHeight          int
+		MaxOriginalWidth   int
+		MaxOriginalHeight  int
+		MaxOriginalPixels  int
 		MaxFileSize        int64
 		RenderedSizeFactor int
+		AllowAnimated      bool
+		MaxAnimatedFrames  int
+		MaxStoreAttempts   int
+		StoreRetryBackoff  time.Duration
+		// foreground colors of generated random avatars as "#rrggbb", the built-in colors are used if empty
+		Palette []string
 	}{
 		MaxWidth:           4096,
 		MaxHeight:          3072,
+		MaxOriginalWidth:   8192,
+		MaxOriginalHeight:  8192,
+		MaxOriginalPixels:  4096 * 3072,
 		MaxFileSize:        1048576,
 		RenderedSizeFactor: 3,
+		MaxAnimatedFrames:  100,
+		MaxStoreAttempts:   3,
+		StoreRetryBackoff:  100 * time.Millisecond,
 	}
 
+	avatarPaletteColorPattern = regexp.MustCompile("^#?[0-9a-fA-F]{6}$")
+
 	GravatarSource        string
 	DisableGravatar       bool // Depreciated: migrated to database
 	EnableFederatedAvatar bool // Depreciated: migrated to database
@@ -45,8 +70,22 @@ func newPictureService() {
 
 	Avatar.MaxWidth = sec.Key("AVATAR_MAX_WIDTH").MustInt(4096)
 	Avatar.MaxHeight = sec.Key("AVATAR_MAX_HEIGHT").MustInt(3072)
+	Avatar.MaxOriginalWidth = sec.Key("AVATAR_MAX_ORIGINAL_WIDTH").MustInt(8192)
+	Avatar.MaxOriginalHeight = sec.Key("AVATAR_MAX_ORIGINAL_HEIGHT").MustInt(8192)
+	Avatar.MaxOriginalPixels = sec.Key("AVATAR_MAX_ORIGINAL_PIXELS").MustInt(4096 * 3072)
 	Avatar.MaxFileSize = sec.Key("AVATAR_MAX_FILE_SIZE").MustInt64(1048576)
 	Avatar.RenderedSizeFactor = sec.Key("AVATAR_RENDERED_SIZE_FACTOR").MustInt(3)
+	Avatar.AllowAnimated = sec.Key("AVATAR_ALLOW_ANIMATED").MustBool(false)
+	Avatar.MaxAnimatedFrames = sec.Key("AVATAR_MAX_ANIMATED_FRAMES").MustInt(100)
+	Avatar.MaxStoreAttempts = sec.Key("AVATAR_MAX_STORE_ATTEMPTS").MustInt(3)
+	Avatar.StoreRetryBackoff = sec.Key("AVATAR_STORE_RETRY_BACKOFF").MustDuration(100 * time.Millisecond)
+	Avatar.Palette = nil
+	for _, c := range sec.Key("AVATAR_PALETTE").Strings(",") {
+		if !avatarPaletteColorPattern.MatchString(c) {
+			log.Fatal("Invalid color %q in [picture] AVATAR_PALETTE, colors must be given as #rrggbb", c)
+		}
+		Avatar.Palette = append(Avatar.Palette, "#"+strings.ToLower(strings.TrimPrefix(c, "#")))
+	}
 
 	switch source := sec.Key("GRAVATAR_SOURCE").MustString("gravatar"); source {
 	case "duoshuo":
@@ -86,7 +86,10 @@ var (
 
 		// Issue Setting
 		Issue struct {
-			LockReasons []string
+			LockReasons                        []string
+			FirstTimeContributorExcludedUsers  []string
+			FirstTimeContributorExcludeMembers bool
+			ParticipantsExcludedUsers          []string
 		} `ini:"repository.issue"`
 
 		Release struct {
@@ -222,9 +225,15 @@ var (
 
 		// Issue settings
 		Issue: struct {
-			LockReasons []string
+			LockReasons                        []string
+			FirstTimeContributorExcludedUsers  []string
+			FirstTimeContributorExcludeMembers bool
+			ParticipantsExcludedUsers          []string
 		}{
-			LockReasons: strings.Split("Too heated,Off-topic,Spam,Resolved", ","),
+			LockReasons:                        strings.Split("Too heated,Off-topic,Spam,Resolved", ","),
+			FirstTimeContributorExcludedUsers:  []string{},
+			FirstTimeContributorExcludeMembers: true,
+			ParticipantsExcludedUsers:          []string{},
 		},
 
 		Release: struct {
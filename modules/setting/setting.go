@@ -388,6 +388,7 @@ var (
 		DefaultPagingNum       int
 		DefaultGitTreesPerPage int
 		DefaultMaxBlobSize     int64
+		RenderLabelEmoji       bool
 	}{
 		EnableSwagger:          true,
 		SwaggerURL:             "",
@@ -395,6 +396,7 @@ var (
 		DefaultPagingNum:       30,
 		DefaultGitTreesPerPage: 1000,
 		DefaultMaxBlobSize:     10485760,
+		RenderLabelEmoji:       false,
 	}
 
 	OAuth2 = struct {
@@ -24,6 +24,12 @@ type Hook struct {
 	Events              []string          `json:"events"`
 	AuthorizationHeader string            `json:"authorization_header"`
 	Active              bool              `json:"active"`
+	// how the body of issues is sent to the hook
+	// enum: full,truncated,omitted
+	IssueBodyMode string `json:"issue_body_mode"`
+	// number of characters the body of issues is truncated to
+	IssueBodyMaxLength  int  `json:"issue_body_max_length"`
+	StripAssigneeEmails bool `json:"strip_assignee_emails"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
 	// swagger:strfmt date-time
@@ -49,6 +55,13 @@ type CreateHookOption struct {
 	AuthorizationHeader string                 `json:"authorization_header"`
 	// default: false
 	Active bool `json:"active"`
+	// how the body of issues is sent to the hook
+	// enum: full,truncated,omitted
+	// default: full
+	IssueBodyMode string `json:"issue_body_mode" binding:"In(,full,truncated,omitted)"`
+	// number of characters the body of issues is truncated to, 0 uses the default length
+	IssueBodyMaxLength  int  `json:"issue_body_max_length"`
+	StripAssigneeEmails bool `json:"strip_assignee_emails"`
 }
 
 // EditHookOption options when modify one hook
@@ -58,6 +71,12 @@ type EditHookOption struct {
 	BranchFilter        string            `json:"branch_filter" binding:"GlobPattern"`
 	AuthorizationHeader string            `json:"authorization_header"`
 	Active              *bool             `json:"active"`
+	// how the body of issues is sent to the hook
+	// enum: full,truncated,omitted
+	IssueBodyMode string `json:"issue_body_mode" binding:"In(,full,truncated,omitted)"`
+	// number of characters the body of issues is truncated to, 0 uses the default length
+	IssueBodyMaxLength  *int  `json:"issue_body_max_length"`
+	StripAssigneeEmails *bool `json:"strip_assignee_emails"`
 }
 
 // Payloader payload is some part of one hook
@@ -93,8 +112,10 @@ type PayloadCommit struct {
 	Modified  []string  `json:"modified"`
 }
 
-// PayloadCommitVerification represents the GPG verification of a commit
+// PayloadCommitVerification represents the GPG or SSH signature verification of a commit
 type PayloadCommitVerification struct {
+	// whether the commit carries a signature at all
+	Signed    bool         `json:"signed"`
 	Verified  bool         `json:"verified"`
 	Reason    string       `json:"reason"`
 	Signature string       `json:"signature"`
@@ -109,6 +130,8 @@ var (
 	_ Payloader = &PushPayload{}
 	_ Payloader = &IssuePayload{}
 	_ Payloader = &IssueCommentPayload{}
+	_ Payloader = &IssueTrackedTimePayload{}
+	_ Payloader = &MilestonePayload{}
 	_ Payloader = &PullRequestPayload{}
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
@@ -230,6 +253,52 @@ func (p *IssueCommentPayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
 
+// HookIssueTrackedTimeAction defines hook issue tracked time action
+type HookIssueTrackedTimeAction string
+
+// all issue tracked time actions
+const (
+	HookIssueTrackedTimeAdded   HookIssueTrackedTimeAction = "added"
+	HookIssueTrackedTimeDeleted HookIssueTrackedTimeAction = "deleted"
+)
+
+// IssueTrackedTimePayload represents a payload information of issue tracked time event.
+type IssueTrackedTimePayload struct {
+	Action      HookIssueTrackedTimeAction `json:"action"`
+	Index       int64                      `json:"number"`
+	Issue       *Issue                     `json:"issue"`
+	TrackedTime *TrackedTime               `json:"tracked_time"`
+	Repository  *Repository                `json:"repository"`
+	Sender      *User                      `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *IssueTrackedTimePayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookMilestoneAction defines hook milestone action
+type HookMilestoneAction string
+
+// all milestone actions
+const (
+	HookMilestoneClosed   HookMilestoneAction = "closed"
+	HookMilestoneReopened HookMilestoneAction = "reopened"
+)
+
+// MilestonePayload represents a payload information of milestone event.
+type MilestonePayload struct {
+	Action     HookMilestoneAction `json:"action"`
+	Milestone  *Milestone          `json:"milestone"`
+	Repository *Repository         `json:"repository"`
+	Sender     *User               `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *MilestonePayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
 // __________       .__
 // \______   \ ____ |  |   ____ _____    ______ ____
 //  |       _// __ \|  | _/ __ \\__  \  /  ___// __ \
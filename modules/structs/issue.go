@@ -24,10 +24,49 @@ const (
 	StateAll StateType = "all"
 )
 
+const (
+	// AuthorAssociationOwner the author owns the repository
+	AuthorAssociationOwner = "OWNER"
+	// AuthorAssociationMember the author is a member of the organization owning the repository
+	AuthorAssociationMember = "MEMBER"
+	// AuthorAssociationCollaborator the author is a collaborator of the repository
+	AuthorAssociationCollaborator = "COLLABORATOR"
+	// AuthorAssociationContributor the author has had a pull request merged into the repository
+	AuthorAssociationContributor = "CONTRIBUTOR"
+	// AuthorAssociationNone the author has no association with the repository
+	AuthorAssociationNone = "NONE"
+)
+
 // PullRequestMeta PR info if an issue is a PR
 type PullRequestMeta struct {
 	HasMerged bool       `json:"merged"`
 	Merged    *time.Time `json:"merged_at"`
+	// whether the title starts with one of the configured work in progress prefixes
+	IsWorkInProgress bool `json:"is_work_in_progress"`
+	// the work in progress prefix the title starts with, empty if it is not a work in progress
+	WorkInProgressPrefix string `json:"work_in_progress_prefix,omitempty"`
+	// users who have been requested to review and did not review since
+	RequestedReviewers []*User `json:"requested_reviewers"`
+	// aggregate state of the latest reviews: REQUEST_CHANGES if any reviewer requested changes,
+	// PENDING while reviews are requested or nobody approved yet, APPROVED otherwise
+	ReviewState ReviewStateType `json:"review_state"`
+	// whether the pull request can be merged without conflicts according to its last check,
+	// empty if it has not been checked yet or has already been merged
+	Mergeable *bool `json:"mergeable"`
+	// number of changed files, added and deleted lines according to the last check,
+	// empty if they are not known without computing the diff
+	ChangedFiles *int `json:"changed_files,omitempty"`
+	Additions    *int `json:"additions,omitempty"`
+	Deletions    *int `json:"deletions,omitempty"`
+	// whether the base branch advanced past the merge base according to the last check,
+	// empty if it is not known without running git
+	NeedsRebase *bool `json:"needs_rebase,omitempty"`
+	// whether the base branch changed after the latest approval or request for changes,
+	// empty if it is not known without running git
+	BaseChangedSinceReview *bool `json:"base_changed_since_review,omitempty"`
+	// combined latest commit statuses of the head commit seen by the last check,
+	// null if it has no status or has not been stored
+	LastCommitStatus *CombinedStatus `json:"last_commit_status"`
 }
 
 // RepositoryMeta basic repository information
@@ -38,21 +77,37 @@ type RepositoryMeta struct {
 	FullName string `json:"full_name"`
 }
 
+// ProjectMeta basic project information
+type ProjectMeta struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+// ProjectColumnMeta basic information of a project column
+type ProjectColumnMeta struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
 // Issue represents an issue in a repository
 // swagger:model
 type Issue struct {
-	ID               int64      `json:"id"`
-	URL              string     `json:"url"`
-	HTMLURL          string     `json:"html_url"`
-	Index            int64      `json:"number"`
-	Poster           *User      `json:"user"`
-	OriginalAuthor   string     `json:"original_author"`
-	OriginalAuthorID int64      `json:"original_author_id"`
-	Title            string     `json:"title"`
-	Body             string     `json:"body"`
-	Ref              string     `json:"ref"`
-	Labels           []*Label   `json:"labels"`
-	Milestone        *Milestone `json:"milestone"`
+	ID               int64  `json:"id"`
+	URL              string `json:"url"`
+	HTMLURL          string `json:"html_url"`
+	Index            int64  `json:"number"`
+	Poster           *User  `json:"user"`
+	OriginalAuthor   string `json:"original_author"`
+	OriginalAuthorID int64  `json:"original_author_id"`
+	// Relationship of the poster to the repository
+	//
+	// enum: OWNER,MEMBER,COLLABORATOR,CONTRIBUTOR,NONE
+	AuthorAssociation string     `json:"author_association"`
+	Title             string     `json:"title"`
+	Body              string     `json:"body"`
+	Ref               string     `json:"ref"`
+	Labels            []*Label   `json:"labels"`
+	Milestone         *Milestone `json:"milestone"`
 	// deprecated
 	Assignee  *User   `json:"assignee"`
 	Assignees []*User `json:"assignees"`
@@ -62,18 +117,78 @@ type Issue struct {
 	// enum: open,closed
 	State    StateType `json:"state"`
 	IsLocked bool      `json:"is_locked"`
-	Comments int       `json:"comments"`
+	// reason given when the issue was locked, empty if it is not locked
+	LockReason string `json:"lock_reason"`
+	Comments   int    `json:"comments"`
+	// URL of the issue in the external issue tracker of the repository, empty if it uses the internal one
+	ExternalURL string `json:"external_url"`
+	// number of the issue this one is a duplicate of, omitted if it is none or if it is in another repository which cannot be seen publicly
+	DuplicateOf *int64 `json:"duplicate_of,omitempty"`
+	// repository of the issue this one is a duplicate of, omitted if it is the same repository
+	DuplicateOfRepo *RepositoryMeta `json:"duplicate_of_repo,omitempty"`
+	// estimated time to resolve the issue in seconds, omitted if there is none or the time tracker is disabled
+	Estimate *int64 `json:"estimate,omitempty"`
+	// seconds tracked on the issue, only included together with the estimate
+	TimeSpent *int64 `json:"time_spent,omitempty"`
+	// whether more time has been tracked than estimated, only included together with the estimate
+	OverEstimate *bool `json:"over_estimate,omitempty"`
+	// number of other issues and pull requests referencing this issue which are visible to the requesting user
+	ReferencedBy int `json:"referenced_by"`
+	// user who locked the issue, the ghost user if the account has been deleted, omitted if the issue is not locked
+	LockedBy *User `json:"locked_by,omitempty"`
+	// users who took part in the issue: the poster, the users who commented and the assignees
+	Participants []*User `json:"participants"`
+	// number of users explicitly watching the issue, users who have unsubscribed are not counted
+	WatchersCount int `json:"watchers_count"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
 	// swagger:strfmt date-time
 	Closed *time.Time `json:"closed_at"`
+	// reason the issue was closed for, either "completed" or "not_planned", empty if it is open
+	ClosedReason string `json:"closed_reason"`
+	// seconds from the creation of the issue until it was closed, or until now if it is open
+	AgeSeconds int64 `json:"age_seconds"`
+	// seconds since the latest comment or review by somebody else than the poster, 0 if there is none
+	SecondsSinceLastResponse int64 `json:"seconds_since_last_response"`
+	// whether a target of the service level agreement has been missed, only included when an agreement applies
+	SLABreached bool `json:"sla_breached,omitempty"`
+	// time the next target of the service level agreement is due, empty if none is pending
+	// swagger:strfmt date-time
+	SLADueAt *time.Time `json:"sla_due_at,omitempty"`
 	// swagger:strfmt date-time
 	Deadline *time.Time `json:"due_date"`
+	// user who last set or changed the due date, empty if it has never been set
+	DeadlineSetBy *User `json:"due_date_set_by,omitempty"`
+	// time the due date was last set or changed, empty if it has never been set
+	// swagger:strfmt date-time
+	DeadlineSetAt *time.Time `json:"due_date_set_at,omitempty"`
+	// time of the latest comment or review, unlike updated_at it does not change on metadata updates
+	// swagger:strfmt date-time
+	LastCommented *time.Time `json:"last_commented_at"`
+	// whether this is the first issue or pull request of the poster in the repository
+	PosterIsFirstTimeContributor bool `json:"poster_is_first_time_contributor"`
+	// file name of the issue form template the issue was created from, empty if no template was used
+	Template string `json:"template"`
 
-	PullRequest *PullRequestMeta `json:"pull_request"`
-	Repo        *RepositoryMeta  `json:"repository"`
+	PullRequest   *PullRequestMeta   `json:"pull_request"`
+	Repo          *RepositoryMeta    `json:"repository"`
+	Project       *ProjectMeta       `json:"project"`
+	ProjectColumn *ProjectColumnMeta `json:"project_column"`
+
+	// whether the requesting user may edit the issue, only set when converted for a viewer
+	CanEdit bool `json:"can_edit,omitempty"`
+	// whether the requesting user may comment on the issue, only set when converted for a viewer
+	CanComment bool `json:"can_comment,omitempty"`
+	// whether the requesting user gets notified about the issue, only set when converted for a viewer
+	Subscribed bool `json:"subscribed,omitempty"`
+	// whether the requesting user has explicitly unsubscribed from the issue, only set when converted for a viewer
+	SubscriptionMuted bool `json:"subscription_muted,omitempty"`
+	// whether users have been assigned or unassigned after the time given as since, only set when converted for a viewer with such a time
+	AssigneesChangedSince bool `json:"assignees_changed_since,omitempty"`
+	// names of the public teams of the organization owning the repository which the poster belongs to, only set when converted for a viewer
+	PosterOrgRoles []string `json:"poster_org_roles,omitempty"`
 }
 
 // CreateIssueOption options to create one issue
@@ -92,6 +207,8 @@ type CreateIssueOption struct {
 	// list of label ids
 	Labels []int64 `json:"labels"`
 	Closed bool    `json:"closed"`
+	// estimated time to resolve the issue in seconds
+	Estimate int64 `json:"estimate"`
 }
 
 // EditIssueOption options for editing an issue
@@ -107,6 +224,10 @@ type EditIssueOption struct {
 	// swagger:strfmt date-time
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
+	// index of the issue of the same repository to close this one as a duplicate of, 0 unsets it
+	DuplicateOf *int64 `json:"duplicate_of"`
+	// estimated time to resolve the issue in seconds, 0 unsets it
+	Estimate *int64 `json:"estimate"`
 }
 
 // EditDeadlineOption options for creating a deadline
@@ -116,6 +237,16 @@ type EditDeadlineOption struct {
 	Deadline *time.Time `json:"due_date"`
 }
 
+// IssuePermissions represents what a user may do with an issue
+type IssuePermissions struct {
+	CanClose  bool `json:"can_close"`
+	CanReopen bool `json:"can_reopen"`
+	CanAssign bool `json:"can_assign"`
+	CanLabel  bool `json:"can_label"`
+	CanLock   bool `json:"can_lock"`
+	CanDelete bool `json:"can_delete"`
+}
+
 // IssueDeadline represents an issue deadline
 // swagger:model
 type IssueDeadline struct {
@@ -21,12 +21,16 @@ type Comment struct {
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
+	// ID of the saved reply the body was created from, omitted if it was written by hand
+	SavedReplyID int64 `json:"saved_reply_id,omitempty"`
 }
 
 // CreateIssueCommentOption options for creating a comment on an issue
 type CreateIssueCommentOption struct {
 	// required:true
 	Body string `json:"body" binding:"Required"`
+	// ID of the saved reply the body was created from
+	SavedReplyID int64 `json:"saved_reply_id"`
 }
 
 // EditIssueCommentOption options for editing a comment
@@ -79,3 +83,9 @@ type TimelineComment struct {
 
 	DependentIssue *Issue `json:"dependent_issue"`
 }
+
+// IssueTimeline represents an issue together with its comments and events in chronological order
+type IssueTimeline struct {
+	Issue  *Issue             `json:"issue"`
+	Events []*TimelineComment `json:"events"`
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// IssueGraphEdgeType is the kind of relation between two issues
+type IssueGraphEdgeType string
+
+const (
+	// IssueGraphEdgeReferences the issue mentions the other one
+	IssueGraphEdgeReferences IssueGraphEdgeType = "references"
+	// IssueGraphEdgeBlocks the issue has to be closed before the other one can be
+	IssueGraphEdgeBlocks IssueGraphEdgeType = "blocks"
+	// IssueGraphEdgeDuplicates the issue has been closed as a duplicate of the other one
+	IssueGraphEdgeDuplicates IssueGraphEdgeType = "duplicates"
+)
+
+// IssueGraph represents issues and the relations between them
+type IssueGraph struct {
+	Nodes []*Issue          `json:"nodes"`
+	Edges []*IssueGraphEdge `json:"edges"`
+}
+
+// IssueGraphEdge represents a relation between two issues of an IssueGraph
+type IssueGraphEdge struct {
+	// ID of the issue the relation originates from
+	From int64 `json:"from"`
+	// ID of the issue the relation points to
+	To   int64              `json:"to"`
+	Type IssueGraphEdgeType `json:"type"`
+}
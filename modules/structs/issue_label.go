@@ -9,10 +9,44 @@ package structs
 type Label struct {
 	ID   int64  `json:"id"`
 	Name string `json:"name"`
+	// name of the label as stored, differs from name when emoji shortcodes are rendered
+	RawName string `json:"raw_name"`
 	// example: 00aabb
-	Color       string `json:"color"`
+	Color string `json:"color"`
+	// text color with the best contrast on the label color, either black or white
+	// example: ffffff
+	TextColor   string `json:"text_color"`
 	Description string `json:"description"`
 	URL         string `json:"url"`
+	// id of the organization the label is defined in, unset for repository labels
+	OrgID int64 `json:"org_id,omitempty"`
+	// whether the label is named like "scope/name", an issue carries at most one label of a scope
+	Exclusive bool `json:"exclusive"`
+	// the part of the name before the last slash of an exclusive label
+	Scope string `json:"scope,omitempty"`
+	// position of the label in the custom order of the labels
+	Order int `json:"order"`
+	// number of open issues carrying the label, only set when explicitly requested
+	OpenIssuesCount int `json:"open_issues_count,omitempty"`
+	// number of closed issues carrying the label, only set when explicitly requested
+	ClosedIssuesCount int `json:"closed_issues_count,omitempty"`
+	// whether the label matches a label of the default label template of the instance by name and color
+	IsDefault bool `json:"is_default"`
+	// id of the project board column which is backed by the label, unset if there is none
+	BoardColumnID int64 `json:"board_column_id,omitempty"`
+	// unix time of the latest update of an issue carrying the label which the user can see, only set when
+	// explicitly requested and 0 if it has never been used
+	LastUsedUnix int64 `json:"last_used_unix,omitempty"`
+	// the description rendered as markdown to sanitized HTML, only set when explicitly requested
+	RenderedDescription string `json:"rendered_description,omitempty"`
+}
+
+// LabelTemplate is the portable definition of a label used to copy labels between repositories
+type LabelTemplate struct {
+	Name string `json:"name"`
+	// example: 00aabb
+	Color       string `json:"color"`
+	Description string `json:"description"`
 }
 
 // CreateLabelOption options for creating a label
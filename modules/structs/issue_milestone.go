@@ -23,6 +23,62 @@ type Milestone struct {
 	Closed *time.Time `json:"closed_at"`
 	// swagger:strfmt date-time
 	Deadline *time.Time `json:"due_on"`
+	// whether the milestone is still open although its deadline has passed
+	IsOverdue bool `json:"is_overdue"`
+	// key to sort milestones lexically by: open before closed ones, then by deadline with milestones without one last, then by title
+	SortKey string `json:"sort_key"`
+	// Number of open and closed issues per label, only included when requested
+	LabelBreakdown []MilestoneLabelCount `json:"label_breakdown,omitempty"`
+	// Number of open issues per assignee, only included when requested
+	AssigneeWorkload []AssigneeCount `json:"assignee_workload,omitempty"`
+	// Number of open pull requests by whether they can be merged, only included when requested
+	PullReadiness *MilestonePullReadiness `json:"pull_readiness,omitempty"`
+}
+
+// MilestoneLabelCount represents the number of issues of a milestone carrying a label
+type MilestoneLabelCount struct {
+	LabelID      int64  `json:"label_id"`
+	Name         string `json:"name"`
+	OpenIssues   int    `json:"open_issues"`
+	ClosedIssues int    `json:"closed_issues"`
+}
+
+// AssigneeCount represents the number of open issues of a milestone assigned to a user
+type AssigneeCount struct {
+	// the assignee, null for the issues without an assignee
+	Assignee   *User `json:"assignee"`
+	OpenIssues int   `json:"open_issues"`
+}
+
+// MilestonePullReadiness represents the number of open pull requests of a milestone by whether they can be merged.
+// Every pull request is counted once, by the first of conflicted, checking, work in progress and changes requested
+// which applies, or else as mergeable.
+type MilestonePullReadiness struct {
+	Mergeable int `json:"mergeable"`
+	// pull requests whose changes conflict with the base branch
+	Conflicted int `json:"conflicted"`
+	// pull requests whose mergeability is still being checked or could not be checked
+	Checking int `json:"checking"`
+	// pull requests whose title marks them as work in progress
+	WorkInProgress int `json:"work_in_progress"`
+	// pull requests on which an official reviewer has requested changes
+	ChangesRequested int `json:"changes_requested"`
+}
+
+// MilestoneBurndown represents a milestone together with the number of its open and closed issues on each day
+type MilestoneBurndown struct {
+	Milestone *Milestone              `json:"milestone"`
+	Days      []*MilestoneBurndownDay `json:"days"`
+}
+
+// MilestoneBurndownDay represents the issues of a milestone on one day
+type MilestoneBurndownDay struct {
+	// the day in the default timezone of the instance, formatted as YYYY-MM-DD
+	Date string `json:"date"`
+	// number of issues closed during the day
+	ClosedIssues int `json:"closed_issues"`
+	// number of issues still open at the end of the day
+	OpenIssues int `json:"open_issues"`
 }
 
 // CreateMilestoneOption options for creating a milestone
@@ -19,3 +19,11 @@ type Reaction struct {
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 }
+
+// IssueVoteSummary counts the 👍 and 👎 reactions of an issue, which are used to vote on it
+type IssueVoteSummary struct {
+	Up   int `json:"up"`
+	Down int `json:"down"`
+	// the vote of the viewer, either "up", "down" or empty if they have not voted
+	ViewerVote string `json:"viewer_vote"`
+}
@@ -13,6 +13,7 @@ type StopWatch struct {
 	Created       time.Time `json:"created"`
 	Seconds       int64     `json:"seconds"`
 	Duration      string    `json:"duration"`
+	Running       bool      `json:"running"`
 	IssueIndex    int64     `json:"issue_index"`
 	IssueTitle    string    `json:"issue_title"`
 	RepoOwnerName string    `json:"repo_owner_name"`
@@ -21,3 +22,23 @@ type StopWatch struct {
 
 // StopWatches represent a list of stopwatches
 type StopWatches []StopWatch
+
+// StopWatchSummary represents the running stopwatches of a user and their total
+type StopWatchSummary struct {
+	TotalSeconds  int64       `json:"total_seconds"`
+	TotalDuration string      `json:"total_duration"`
+	StopWatches   StopWatches `json:"stopwatches"`
+}
+
+// UserTimeDashboard represents the running stopwatches of a user together with the times they tracked today
+type UserTimeDashboard struct {
+	StopWatches StopWatches `json:"stopwatches"`
+	// seconds the stopwatches have been running in total
+	StopWatchSeconds int64 `json:"stopwatch_seconds"`
+	// times tracked since the start of the day
+	TrackedToday TrackedTimeList `json:"tracked_today"`
+	// seconds tracked since the start of the day in total
+	TrackedTodaySeconds int64 `json:"tracked_today_seconds"`
+	// seconds tracked today and of the running stopwatches in total
+	TotalSeconds int64 `json:"total_seconds"`
+}
@@ -9,13 +9,16 @@ import (
 
 // AddTimeOption options for adding time to an issue
 type AddTimeOption struct {
-	// time in seconds
-	// required: true
-	Time int64 `json:"time" binding:"Required"`
+	// time in seconds, ignored if time_ms is set
+	Time int64 `json:"time"`
+	// time in milliseconds
+	TimeMs int64 `json:"time_ms"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created"`
 	// User who spent the time (optional)
 	User string `json:"user_name"`
+	// whether the time is billable
+	Billable bool `json:"billable"`
 }
 
 // TrackedTime worked time for an issue / pr
@@ -25,13 +28,57 @@ type TrackedTime struct {
 	Created time.Time `json:"created"`
 	// Time in seconds
 	Time int64 `json:"time"`
+	// Time in milliseconds
+	TimeMs int64 `json:"time_ms"`
+	// Time as ISO 8601 duration, e.g. PT1H30M
+	ISODuration string `json:"iso_duration"`
 	// deprecated (only for backwards compatibility)
 	UserID   int64  `json:"user_id"`
 	UserName string `json:"user_name"`
 	// deprecated (only for backwards compatibility)
 	IssueID int64  `json:"issue_id"`
 	Issue   *Issue `json:"issue"`
+	// Set when the entry has been removed
+	Deleted bool `json:"deleted,omitempty"`
+	// whether the time is billable
+	Billable bool `json:"billable"`
+	// ID of the comment created when the time was added manually
+	CommentID int64 `json:"comment_id,omitempty"`
+	// Body of the comment created when the time was added manually
+	CommentBody string `json:"comment_body,omitempty"`
 }
 
 // TrackedTimeList represents a list of tracked times
 type TrackedTimeList []*TrackedTime
+
+// TrackedTimeSummary represents tracked times with their totals per issue and per user
+type TrackedTimeSummary struct {
+	// Total time in seconds
+	Total int64 `json:"total"`
+	// Total billable time in seconds
+	BillableTotal int64 `json:"billable_total"`
+
+	ByIssue []*TrackedTimeIssueTotal `json:"by_issue"`
+	ByUser  []*TrackedTimeUserTotal  `json:"by_user"`
+	Times   TrackedTimeList          `json:"times"`
+}
+
+// TrackedTimeIssueTotal represents the time tracked on an issue
+type TrackedTimeIssueTotal struct {
+	IssueID int64 `json:"issue_id"`
+	Index   int64 `json:"number"`
+	// Time in seconds
+	Time int64 `json:"time"`
+	// Time in milliseconds
+	TimeMs int64 `json:"time_ms"`
+}
+
+// TrackedTimeUserTotal represents the time tracked by a user
+type TrackedTimeUserTotal struct {
+	UserID   int64  `json:"user_id"`
+	UserName string `json:"user_name"`
+	// Time in seconds
+	Time int64 `json:"time"`
+	// Time in milliseconds
+	TimeMs int64 `json:"time_ms"`
+}
@@ -19,6 +19,8 @@ type Team struct {
 	// example: {"repo.code":"read","repo.issues":"write","repo.ext_issues":"none","repo.wiki":"admin","repo.pulls":"owner","repo.releases":"none","repo.projects":"none","repo.ext_wiki":"none"}
 	UnitsMap         map[string]string `json:"units_map"`
 	CanCreateOrgRepo bool              `json:"can_create_org_repo"`
+	// whether the membership in the team is shown publicly, e.g. as role of issue posters
+	IsPublic bool `json:"is_public"`
 }
 
 // CreateTeamOption options for creating a team
@@ -35,6 +37,8 @@ type CreateTeamOption struct {
 	// example: {"repo.code":"read","repo.issues":"write","repo.ext_issues":"none","repo.wiki":"admin","repo.pulls":"owner","repo.releases":"none","repo.projects":"none","repo.ext_wiki":"none"}
 	UnitsMap         map[string]string `json:"units_map"`
 	CanCreateOrgRepo bool              `json:"can_create_org_repo"`
+	// whether the membership in the team is shown publicly, e.g. as role of issue posters
+	IsPublic bool `json:"is_public"`
 }
 
 // EditTeamOption options for editing a team
@@ -51,4 +55,6 @@ type EditTeamOption struct {
 	// example: {"repo.code":"read","repo.issues":"write","repo.ext_issues":"none","repo.wiki":"admin","repo.pulls":"owner","repo.releases":"none","repo.projects":"none","repo.ext_wiki":"none"}
 	UnitsMap         map[string]string `json:"units_map"`
 	CanCreateOrgRepo *bool             `json:"can_create_org_repo"`
+	// whether the membership in the team is shown publicly, e.g. as role of issue posters
+	IsPublic *bool `json:"is_public"`
 }
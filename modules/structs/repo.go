@@ -24,6 +24,8 @@ type InternalTracker struct {
 	AllowOnlyContributorsToTrackTime bool `json:"allow_only_contributors_to_track_time"`
 	// Enable dependencies for issues and pull requests (Built-in issue tracker)
 	EnableIssueDependencies bool `json:"enable_issue_dependencies"`
+	// Close milestones when their last open issue is closed and reopen them along with their issues (Built-in issue tracker)
+	AutoCloseMilestones bool `json:"auto_close_milestones"`
 }
 
 // ExternalTracker represents settings for external tracker
@@ -349,6 +349,7 @@ func NewFuncMap() []template.FuncMap {
 		},
 		"svg":            svg.RenderHTML,
 		"avatar":         Avatar,
+		"avatarInOrg":    AvatarInOrg,
 		"avatarHTML":     AvatarHTML,
 		"avatarByAction": AvatarByAction,
 		"avatarByEmail":  AvatarByEmail,
@@ -610,17 +611,17 @@ func Avatar(item interface{}, others ...interface{}) template.HTML {
 
 	switch t := item.(type) {
 	case *user_model.User:
-		src := t.AvatarLinkWithSize(size * setting.Avatar.RenderedSizeFactor)
+		src := t.AvatarLinkWithSizeAndScale(size, setting.Avatar.RenderedSizeFactor)
 		if src != "" {
 			return AvatarHTML(src, size, class, t.DisplayName())
 		}
 	case *repo_model.Collaborator:
-		src := t.AvatarLinkWithSize(size * setting.Avatar.RenderedSizeFactor)
+		src := t.AvatarLinkWithSizeAndScale(size, setting.Avatar.RenderedSizeFactor)
 		if src != "" {
 			return AvatarHTML(src, size, class, t.DisplayName())
 		}
 	case *organization.Organization:
-		src := t.AsUser().AvatarLinkWithSize(size * setting.Avatar.RenderedSizeFactor)
+		src := t.AsUser().AvatarLinkWithSizeAndScale(size, setting.Avatar.RenderedSizeFactor)
 		if src != "" {
 			return AvatarHTML(src, size, class, t.AsUser().DisplayName())
 		}
@@ -629,6 +630,22 @@ func Avatar(item interface{}, others ...interface{}) template.HTML {
 	return template.HTML("")
 }
 
+// AvatarInOrg renders user avatars like Avatar, but users without an avatar get the default member
+// avatar of the organization. args: user, default member avatar (string), size (int), class (string)
+func AvatarInOrg(u *user_model.User, defaultMemberAvatar string, others ...interface{}) template.HTML {
+	size, class := gitea_html.ParseSizeAndClass(avatars.DefaultAvatarPixelSize, avatars.DefaultAvatarClass, others...)
+
+	scaledSize := size
+	if size > 0 && setting.Avatar.RenderedSizeFactor > 1 {
+		scaledSize *= setting.Avatar.RenderedSizeFactor
+	}
+	src := u.AvatarLinkWithSizeInOrg(scaledSize, defaultMemberAvatar)
+	if src != "" {
+		return AvatarHTML(src, size, class, u.DisplayName())
+	}
+	return template.HTML("")
+}
+
 // AvatarByAction renders user avatars from action. args: action, size (int), class (string)
 func AvatarByAction(action *activities_model.Action, others ...interface{}) template.HTML {
 	action.LoadActUser()
@@ -880,6 +897,8 @@ func ActionIcon(opType activities_model.ActionType) string {
 		return "tag"
 	case activities_model.ActionPullReviewDismissed:
 		return "x"
+	case activities_model.ActionCloseMilestone, activities_model.ActionReopenMilestone:
+		return "milestone"
 	default:
 		return "question"
 	}
@@ -77,3 +77,33 @@ func formatTime(value int64, name, formattedTime string) string {
 
 	return formattedTime
 }
+
+// SecToISODuration converts an amount of seconds to an ISO 8601 duration. Hours are not
+// grouped into days, as days are not always 24 hours long. E.g.
+// 0s		-> PT0S
+// 66s		-> PT1M6S
+// 5400s	-> PT1H30M
+// 90061s	-> PT25H1M1S
+func SecToISODuration(duration int64) string {
+	if duration == 0 {
+		return "PT0S"
+	}
+	sign := ""
+	if duration < 0 {
+		sign = "-"
+		duration = -duration
+	}
+
+	var sb strings.Builder
+	sb.WriteString(sign + "PT")
+	if hours := duration / 3600; hours > 0 {
+		fmt.Fprintf(&sb, "%dH", hours)
+	}
+	if minutes := (duration / 60) % 60; minutes > 0 {
+		fmt.Fprintf(&sb, "%dM", minutes)
+	}
+	if seconds := duration % 60; seconds > 0 {
+		fmt.Fprintf(&sb, "%dS", seconds)
+	}
+	return sb.String()
+}
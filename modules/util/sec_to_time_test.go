@@ -28,3 +28,21 @@ func TestSecToTime(t *testing.T) {
 	assert.Equal(t, "11 months", SecToTime(year-25*day))
 	assert.Equal(t, "1 year 5 months", SecToTime(year+163*day+10*hour+11*minute+5*second))
 }
+
+func TestSecToISODuration(t *testing.T) {
+	for _, c := range []struct {
+		seconds  int64
+		expected string
+	}{
+		{0, "PT0S"},
+		{1, "PT1S"},
+		{66, "PT1M6S"},
+		{3600, "PT1H"},
+		{5400, "PT1H30M"},
+		{3601, "PT1H1S"},
+		{90061, "PT25H1M1S"},
+		{-90, "-PT1M30S"},
+	} {
+		assert.Equal(t, c.expected, SecToISODuration(c.seconds), "%d seconds", c.seconds)
+	}
+}
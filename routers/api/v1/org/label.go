@@ -38,6 +38,18 @@ func ListLabels(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: last_used
+	//   in: query
+	//   description: include the time the labels have last been used on an issue or pull request the user can see
+	//   type: boolean
+	// - name: issue_counts
+	//   in: query
+	//   description: include the number of open and closed issues and pull requests carrying the labels
+	//   type: boolean
+	// - name: render_description
+	//   in: query
+	//   description: include the description of the labels rendered as markdown
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/LabelList"
@@ -55,7 +67,17 @@ func ListLabels(ctx *context.APIContext) {
 	}
 
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, convert.ToLabelList(labels, nil, ctx.Org.Organization.AsUser()))
+	apiLabels, err := convert.ToLabelListWithOptions(ctx, labels, nil, ctx.Org.Organization.AsUser(), convert.LabelListOptions{
+		LastUsed:          ctx.FormBool("last_used"),
+		Viewer:            ctx.Doer,
+		IssueCounts:       ctx.FormBool("issue_counts"),
+		RenderDescription: ctx.FormBool("render_description"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToLabelListWithOptions", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiLabels)
 }
 
 // CreateLabel create a label for a repository
@@ -103,7 +125,7 @@ func CreateLabel(ctx *context.APIContext) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, convert.ToLabel(label, nil, ctx.Org.Organization.AsUser()))
+	ctx.JSON(http.StatusCreated, convert.ToLabel(ctx, label, nil, ctx.Org.Organization.AsUser()))
 }
 
 // GetLabel get label by organization and label id
@@ -125,6 +147,14 @@ func GetLabel(ctx *context.APIContext) {
 	//   type: integer
 	//   format: int64
 	//   required: true
+	// - name: issue_counts
+	//   in: query
+	//   description: include the number of open and closed issues and pull requests carrying the label
+	//   type: boolean
+	// - name: render_description
+	//   in: query
+	//   description: include the description of the label rendered as markdown
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Label"
@@ -148,7 +178,15 @@ func GetLabel(ctx *context.APIContext) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, convert.ToLabel(label, nil, ctx.Org.Organization.AsUser()))
+	apiLabel, err := convert.ToLabelWithOptions(ctx, label, nil, ctx.Org.Organization.AsUser(), convert.LabelListOptions{
+		IssueCounts:       ctx.FormBool("issue_counts"),
+		RenderDescription: ctx.FormBool("render_description"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToLabelWithOptions", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiLabel)
 }
 
 // EditLabel modify a label for an Organization
@@ -208,12 +246,12 @@ func EditLabel(ctx *context.APIContext) {
 	if form.Description != nil {
 		label.Description = *form.Description
 	}
-	if err := issues_model.UpdateLabel(label); err != nil {
+	if err := issues_model.UpdateLabel(ctx, label); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateLabel", err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, convert.ToLabel(label, nil, ctx.Org.Organization.AsUser()))
+	ctx.JSON(http.StatusOK, convert.ToLabel(ctx, label, nil, ctx.Org.Organization.AsUser()))
 }
 
 // DeleteLabel delete a label for an organization
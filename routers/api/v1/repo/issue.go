@@ -20,7 +20,9 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/httpcache"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -29,6 +31,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	issue_service "code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
 )
 
 // SearchIssues searches for issues across the repositories that the user has access to
@@ -283,7 +286,7 @@ func SearchIssues(ctx *context.APIContext) {
 
 	ctx.SetLinkHeader(int(filteredCount), limit)
 	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues, ctx.Doer))
 }
 
 // ListIssues list the issues of a repository
@@ -492,7 +495,7 @@ func ListIssues(ctx *context.APIContext) {
 
 	ctx.SetLinkHeader(int(filteredCount), listOptions.PageSize)
 	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues, ctx.Doer))
 }
 
 func getUserIDForFilter(ctx *context.APIContext, queryName string) int64 {
@@ -539,11 +542,30 @@ func GetIssue(ctx *context.APIContext) {
 	//   type: integer
 	//   format: int64
 	//   required: true
+	// - name: check_mergeable
+	//   in: query
+	//   description: if the issue is an open pull request, check again whether it can be merged. The check runs in the background, mergeable is unset until it has finished
+	//   type: boolean
+	// - name: since
+	//   in: query
+	//   description: if provided, report whether users have been assigned or unassigned after this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Issue"
+	//   "304":
+	//     description: The issue did not change since the request of the ETag given in If-None-Match
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	since, _, err := context.GetQuerySinceUntil(ctx.Context)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQuerySinceUntil", err)
+		return
+	}
 
 	issue, err := issues_model.GetIssueWithAttrsByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
@@ -554,7 +576,23 @@ func GetIssue(ctx *context.APIContext) {
 		}
 		return
 	}
-	ctx.JSON(http.StatusOK, convert.ToAPIIssue(ctx, issue))
+	if err := issue.LoadProjectBoard(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadProjectBoard", err)
+		return
+	}
+	if issue.IsPull && !issue.IsClosed && ctx.FormBool("check_mergeable") {
+		if err := issue.LoadPullRequest(ctx); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadPullRequest", err)
+			return
+		}
+		if !issue.PullRequest.HasMerged && !issue.PullRequest.IsChecking() {
+			pull_service.AddToTaskQueue(issue.PullRequest)
+		}
+	}
+	if httpcache.HandleGenericETagRevalidation(ctx.Req, ctx.Resp, convert.IssueETag(issue, ctx.Doer, since)) {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueForViewer(ctx, issue, ctx.Doer, since))
 }
 
 // CreateIssue create an issue of a repository
@@ -591,6 +629,10 @@ func CreateIssue(ctx *context.APIContext) {
 	//   "422":
 	//     "$ref": "#/responses/validationError"
 	form := web.GetForm(ctx).(*api.CreateIssueOption)
+	if form.Estimate < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "Estimate", "estimate must not be negative")
+		return
+	}
 	var deadlineUnix timeutil.TimeStamp
 	if form.Deadline != nil && ctx.Repo.CanWrite(unit.TypeIssues) {
 		deadlineUnix = timeutil.TimeStamp(form.Deadline.Unix())
@@ -611,6 +653,7 @@ func CreateIssue(ctx *context.APIContext) {
 	var err error
 	if ctx.Repo.CanWrite(unit.TypeIssues) {
 		issue.MilestoneID = form.Milestone
+		issue.Estimate = form.Estimate
 		assigneeIDs, err = issues_model.MakeIDsFromAPIAssigneesToAdd(ctx, form.Assignee, form.Assignees)
 		if err != nil {
 			if user_model.IsErrUserNotExist(err) {
@@ -811,6 +854,34 @@ func EditIssue(ctx *context.APIContext) {
 		}
 		issue.IsClosed = api.StateClosed == api.StateType(*form.State)
 	}
+	if canWrite && form.Estimate != nil {
+		if *form.Estimate < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "Estimate", "estimate must not be negative")
+			return
+		}
+		issue.Estimate = *form.Estimate
+	}
+	if canWrite && form.DuplicateOf != nil {
+		if *form.DuplicateOf == 0 {
+			issue.DuplicateOfID = 0
+		} else {
+			duplicateOf, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, *form.DuplicateOf)
+			if err != nil {
+				if issues_model.IsErrIssueNotExist(err) {
+					ctx.Error(http.StatusUnprocessableEntity, "GetIssueByIndex", err)
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+				}
+				return
+			}
+			if duplicateOf.ID == issue.ID {
+				ctx.Error(http.StatusUnprocessableEntity, "DuplicateOf", "an issue cannot be a duplicate of itself")
+				return
+			}
+			issue.DuplicateOfID = duplicateOf.ID
+			issue.IsClosed = true
+		}
+	}
 	statusChangeComment, titleChanged, err := issues_model.UpdateIssueByAPI(issue, ctx.Doer)
 	if err != nil {
 		if issues_model.IsErrDependenciesLeft(err) {
@@ -827,6 +898,10 @@ func EditIssue(ctx *context.APIContext) {
 
 	if statusChangeComment != nil {
 		notification.NotifyIssueChangeStatus(ctx, ctx.Doer, issue, statusChangeComment, issue.IsClosed)
+
+		if err := issue_service.SyncMilestoneStatus(ctx, ctx.Doer, issue); err != nil {
+			log.Error("Unable to sync the status of milestone %d with issue[%d]#%d: %v", issue.MilestoneID, issue.ID, issue.Index, err)
+		}
 	}
 
 	// Refetch from database to assign some automatic values
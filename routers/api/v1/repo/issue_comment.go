@@ -182,13 +182,14 @@ func ListIssueCommentsAndTimeline(ctx *context.APIContext) {
 		return
 	}
 
-	var apiComments []*api.TimelineComment
+	visible := make([]*issues_model.Comment, 0, len(comments))
 	for _, comment := range comments {
 		if comment.Type != issues_model.CommentTypeCode && isXRefCommentAccessible(ctx, ctx.Doer, comment, issue.RepoID) {
 			comment.Issue = issue
-			apiComments = append(apiComments, convert.ToTimelineComment(ctx, comment, ctx.Doer))
+			visible = append(visible, comment)
 		}
 	}
+	apiComments := convert.ToTimelineCommentList(ctx, visible, ctx.Doer)
 
 	ctx.SetTotalCountHeader(int64(len(apiComments)))
 	ctx.JSON(http.StatusOK, &apiComments)
@@ -353,7 +354,7 @@ func CreateIssueComment(ctx *context.APIContext) {
 		return
 	}
 
-	comment, err := comment_service.CreateIssueComment(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Body, nil)
+	comment, err := comment_service.CreateIssueCommentFromSavedReply(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Body, nil, form.SavedReplyID)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "CreateIssueComment", err)
 		return
@@ -77,16 +77,7 @@ func GetIssueCommentReactions(ctx *context.APIContext) {
 		return
 	}
 
-	var result []api.Reaction
-	for _, r := range reactions {
-		result = append(result, api.Reaction{
-			User:     convert.ToUser(r.User, ctx.Doer),
-			Reaction: r.Type,
-			Created:  r.CreatedUnix.AsTime(),
-		})
-	}
-
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, convert.ToReactionList(reactions, ctx.Doer))
 }
 
 // PostIssueCommentReaction add a reaction to a comment of an issue
@@ -201,22 +192,16 @@ func changeIssueCommentReaction(ctx *context.APIContext, form api.EditReactionOp
 			if issues_model.IsErrForbiddenIssueReaction(err) {
 				ctx.Error(http.StatusForbidden, err.Error(), err)
 			} else if issues_model.IsErrReactionAlreadyExist(err) {
-				ctx.JSON(http.StatusOK, api.Reaction{
-					User:     convert.ToUser(ctx.Doer, ctx.Doer),
-					Reaction: reaction.Type,
-					Created:  reaction.CreatedUnix.AsTime(),
-				})
+				reaction.User = ctx.Doer
+				ctx.JSON(http.StatusOK, convert.ToReaction(reaction, ctx.Doer))
 			} else {
 				ctx.Error(http.StatusInternalServerError, "CreateCommentReaction", err)
 			}
 			return
 		}
 
-		ctx.JSON(http.StatusCreated, api.Reaction{
-			User:     convert.ToUser(ctx.Doer, ctx.Doer),
-			Reaction: reaction.Type,
-			Created:  reaction.CreatedUnix.AsTime(),
-		})
+		reaction.User = ctx.Doer
+		ctx.JSON(http.StatusCreated, convert.ToReaction(reaction, ctx.Doer))
 	} else {
 		// DeleteIssueCommentReaction part
 		err = issues_model.DeleteCommentReaction(ctx.Doer.ID, comment.Issue.ID, comment.ID, form.Reaction)
@@ -295,17 +280,8 @@ func GetIssueReactions(ctx *context.APIContext) {
 		return
 	}
 
-	var result []api.Reaction
-	for _, r := range reactions {
-		result = append(result, api.Reaction{
-			User:     convert.ToUser(r.User, ctx.Doer),
-			Reaction: r.Type,
-			Created:  r.CreatedUnix.AsTime(),
-		})
-	}
-
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, convert.ToReactionList(reactions, ctx.Doer))
 }
 
 // PostIssueReaction add a reaction to an issue
@@ -411,22 +387,16 @@ func changeIssueReaction(ctx *context.APIContext, form api.EditReactionOption, i
 			if issues_model.IsErrForbiddenIssueReaction(err) {
 				ctx.Error(http.StatusForbidden, err.Error(), err)
 			} else if issues_model.IsErrReactionAlreadyExist(err) {
-				ctx.JSON(http.StatusOK, api.Reaction{
-					User:     convert.ToUser(ctx.Doer, ctx.Doer),
-					Reaction: reaction.Type,
-					Created:  reaction.CreatedUnix.AsTime(),
-				})
+				reaction.User = ctx.Doer
+				ctx.JSON(http.StatusOK, convert.ToReaction(reaction, ctx.Doer))
 			} else {
 				ctx.Error(http.StatusInternalServerError, "CreateCommentReaction", err)
 			}
 			return
 		}
 
-		ctx.JSON(http.StatusCreated, api.Reaction{
-			User:     convert.ToUser(ctx.Doer, ctx.Doer),
-			Reaction: reaction.Type,
-			Created:  reaction.CreatedUnix.AsTime(),
-		})
+		reaction.User = ctx.Doer
+		ctx.JSON(http.StatusCreated, convert.ToReaction(reaction, ctx.Doer))
 	} else {
 		// DeleteIssueReaction part
 		err = issues_model.DeleteIssueReaction(ctx.Doer.ID, issue.ID, form.Reaction)
@@ -17,8 +17,18 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
+// writeTrackedTimeList responds with the tracked times, the sum of their billable times in seconds
+// is sent in the X-Billable-Total header
+func writeTrackedTimeList(ctx *context.APIContext, trackedTimes issues_model.TrackedTimeList) {
+	apiTimes, billableTotal := convert.ToTrackedTimeList(ctx, trackedTimes)
+	ctx.RespHeader().Set("X-Billable-Total", fmt.Sprint(billableTotal))
+	ctx.AppendAccessControlExposeHeaders("X-Billable-Total")
+	ctx.JSON(http.StatusOK, apiTimes)
+}
+
 // ListTrackedTimes list all the tracked times of an issue
 func ListTrackedTimes(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/times issue issueTrackedTimes
@@ -138,7 +148,7 @@ func ListTrackedTimes(ctx *context.APIContext) {
 	}
 
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, convert.ToTrackedTimeList(ctx, trackedTimes))
+	writeTrackedTimeList(ctx, trackedTimes)
 }
 
 // AddTime add time manual to the given issue
@@ -178,6 +188,8 @@ func AddTime(ctx *context.APIContext) {
 	//     "$ref": "#/responses/error"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	form := web.GetForm(ctx).(*api.AddTimeOption)
 	issue, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
@@ -214,7 +226,16 @@ func AddTime(ctx *context.APIContext) {
 		created = form.Created
 	}
 
-	trackedTime, err := issues_model.AddTime(user, issue, form.Time, created)
+	amountMs := form.TimeMs
+	if amountMs == 0 {
+		amountMs = form.Time * 1000
+	}
+	if amountMs == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "time or time_ms is required")
+		return
+	}
+
+	trackedTime, err := issue_service.AddTimeMs(ctx, ctx.Doer, user, issue, amountMs, created, form.Billable)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "AddTime", err)
 		return
@@ -279,7 +300,7 @@ func ResetIssueTime(ctx *context.APIContext) {
 		return
 	}
 
-	err = issues_model.DeleteIssueUserTimes(issue, ctx.Doer)
+	err = issue_service.DeleteIssueUserTimes(ctx, ctx.Doer, issue)
 	if err != nil {
 		if db.IsErrNotExist(err) {
 			ctx.Error(http.StatusNotFound, "DeleteIssueUserTimes", err)
@@ -370,7 +391,7 @@ func DeleteTime(ctx *context.APIContext) {
 		return
 	}
 
-	err = issues_model.DeleteTime(time)
+	err = issue_service.DeleteTime(ctx, ctx.Doer, time)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "DeleteTime", err)
 		return
@@ -447,7 +468,7 @@ func ListTrackedTimesByUser(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
 		return
 	}
-	ctx.JSON(http.StatusOK, convert.ToTrackedTimeList(ctx, trackedTimes))
+	writeTrackedTimeList(ctx, trackedTimes)
 }
 
 // ListTrackedTimesByRepository lists all tracked times of the repository
@@ -557,7 +578,7 @@ func ListTrackedTimesByRepository(ctx *context.APIContext) {
 	}
 
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, convert.ToTrackedTimeList(ctx, trackedTimes))
+	writeTrackedTimeList(ctx, trackedTimes)
 }
 
 // ListMyTrackedTimes lists all tracked times of the current user
@@ -619,5 +640,5 @@ func ListMyTrackedTimes(ctx *context.APIContext) {
 	}
 
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, convert.ToTrackedTimeList(ctx, trackedTimes))
+	writeTrackedTimeList(ctx, trackedTimes)
 }
@@ -44,6 +44,18 @@ func ListLabels(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: last_used
+	//   in: query
+	//   description: include the time the labels have last been used on an issue or pull request the user can see
+	//   type: boolean
+	// - name: issue_counts
+	//   in: query
+	//   description: include the number of open and closed issues and pull requests carrying the labels
+	//   type: boolean
+	// - name: render_description
+	//   in: query
+	//   description: include the description of the labels rendered as markdown
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/LabelList"
@@ -61,7 +73,17 @@ func ListLabels(ctx *context.APIContext) {
 	}
 
 	ctx.SetTotalCountHeader(count)
-	ctx.JSON(http.StatusOK, convert.ToLabelList(labels, ctx.Repo.Repository, nil))
+	apiLabels, err := convert.ToLabelListWithOptions(ctx, labels, ctx.Repo.Repository, nil, convert.LabelListOptions{
+		LastUsed:          ctx.FormBool("last_used"),
+		Viewer:            ctx.Doer,
+		IssueCounts:       ctx.FormBool("issue_counts"),
+		RenderDescription: ctx.FormBool("render_description"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToLabelListWithOptions", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiLabels)
 }
 
 // GetLabel get label by repository and label id
@@ -88,6 +110,14 @@ func GetLabel(ctx *context.APIContext) {
 	//   type: integer
 	//   format: int64
 	//   required: true
+	// - name: issue_counts
+	//   in: query
+	//   description: include the number of open and closed issues and pull requests carrying the label
+	//   type: boolean
+	// - name: render_description
+	//   in: query
+	//   description: include the description of the label rendered as markdown
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Label"
@@ -111,7 +141,15 @@ func GetLabel(ctx *context.APIContext) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, convert.ToLabel(label, ctx.Repo.Repository, nil))
+	apiLabel, err := convert.ToLabelWithOptions(ctx, label, ctx.Repo.Repository, nil, convert.LabelListOptions{
+		IssueCounts:       ctx.FormBool("issue_counts"),
+		RenderDescription: ctx.FormBool("render_description"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToLabelWithOptions", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiLabel)
 }
 
 // CreateLabel create a label for a repository
@@ -165,7 +203,7 @@ func CreateLabel(ctx *context.APIContext) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, convert.ToLabel(label, ctx.Repo.Repository, nil))
+	ctx.JSON(http.StatusCreated, convert.ToLabel(ctx, label, ctx.Repo.Repository, nil))
 }
 
 // EditLabel modify a label for a repository
@@ -231,12 +269,12 @@ func EditLabel(ctx *context.APIContext) {
 	if form.Description != nil {
 		label.Description = *form.Description
 	}
-	if err := issues_model.UpdateLabel(label); err != nil {
+	if err := issues_model.UpdateLabel(ctx, label); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateLabel", err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, convert.ToLabel(label, ctx.Repo.Repository, nil))
+	ctx.JSON(http.StatusOK, convert.ToLabel(ctx, label, ctx.Repo.Repository, nil))
 }
 
 // DeleteLabel delete a label for a repository
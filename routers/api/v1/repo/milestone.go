@@ -99,6 +99,18 @@ func GetMilestone(ctx *context.APIContext) {
 	//   description: the milestone to get, identified by ID and if not available by name
 	//   type: string
 	//   required: true
+	// - name: label_breakdown
+	//   in: query
+	//   description: include the number of open and closed issues per label
+	//   type: boolean
+	// - name: assignee_workload
+	//   in: query
+	//   description: include the number of open issues per assignee
+	//   type: boolean
+	// - name: pull_readiness
+	//   in: query
+	//   description: include the number of open pull requests by whether they can be merged
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Milestone"
@@ -108,7 +120,32 @@ func GetMilestone(ctx *context.APIContext) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, convert.ToAPIMilestone(milestone))
+	apiMilestone := convert.ToAPIMilestone(milestone)
+	if ctx.FormBool("label_breakdown") {
+		var err error
+		apiMilestone, err = convert.ToAPIMilestoneWithLabelBreakdown(ctx, milestone)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToAPIMilestoneWithLabelBreakdown", err)
+			return
+		}
+	}
+	if ctx.FormBool("assignee_workload") {
+		workload, err := convert.ToMilestoneAssigneeWorkload(ctx, milestone, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToMilestoneAssigneeWorkload", err)
+			return
+		}
+		apiMilestone.AssigneeWorkload = workload
+	}
+	if ctx.FormBool("pull_readiness") {
+		readiness, err := convert.ToMilestonePullReadiness(ctx, milestone)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToMilestonePullReadiness", err)
+			return
+		}
+		apiMilestone.PullReadiness = readiness
+	}
+	ctx.JSON(http.StatusOK, apiMilestone)
 }
 
 // CreateMilestone create a milestone for a repository
@@ -770,6 +770,7 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 					EnableTimetracker:                opts.InternalTracker.EnableTimeTracker,
 					AllowOnlyContributorsToTrackTime: opts.InternalTracker.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               opts.InternalTracker.EnableIssueDependencies,
+					AutoCloseMilestones:              opts.InternalTracker.AutoCloseMilestones,
 				}
 			} else if unit, err := repo.GetUnit(unit_model.TypeIssues); err != nil {
 				// Unit type doesn't exist so we make a new config file with default values
@@ -138,6 +138,8 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 				IssueLabel:           issuesHook(form.Events, string(webhook.HookEventIssueLabel)),
 				IssueMilestone:       issuesHook(form.Events, string(webhook.HookEventIssueMilestone)),
 				IssueComment:         issuesHook(form.Events, string(webhook.HookEventIssueComment)),
+				IssueTimeTracked:     issuesHook(form.Events, string(webhook.HookEventIssueTimeTracked)),
+				Milestone:            util.IsStringInSlice(string(webhook.HookEventMilestone), form.Events, true),
 				Push:                 util.IsStringInSlice(string(webhook.HookEventPush), form.Events, true),
 				PullRequest:          pullHook(form.Events, "pull_request_only"),
 				PullRequestAssign:    pullHook(form.Events, string(webhook.HookEventPullRequestAssign)),
@@ -151,6 +153,11 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 				Release:              util.IsStringInSlice(string(webhook.HookEventRelease), form.Events, true),
 			},
 			BranchFilter: form.BranchFilter,
+			IssuePayload: webhook.IssuePayloadSettings{
+				BodyMode:            webhook.ToIssueBodyMode(form.IssueBodyMode),
+				BodyMaxLength:       form.IssueBodyMaxLength,
+				StripAssigneeEmails: form.StripAssigneeEmails,
+			},
 		},
 		IsActive: form.Active,
 		Type:     form.Type,
@@ -285,6 +292,7 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.Repository = util.IsStringInSlice(string(webhook.HookEventRepository), form.Events, true)
 	w.Wiki = util.IsStringInSlice(string(webhook.HookEventWiki), form.Events, true)
 	w.Release = util.IsStringInSlice(string(webhook.HookEventRelease), form.Events, true)
+	w.Milestone = util.IsStringInSlice(string(webhook.HookEventMilestone), form.Events, true)
 	w.BranchFilter = form.BranchFilter
 
 	err := w.SetHeaderAuthorization(form.AuthorizationHeader)
@@ -299,6 +307,7 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.IssueLabel = issuesHook(form.Events, string(webhook.HookEventIssueLabel))
 	w.IssueMilestone = issuesHook(form.Events, string(webhook.HookEventIssueMilestone))
 	w.IssueComment = issuesHook(form.Events, string(webhook.HookEventIssueComment))
+	w.IssueTimeTracked = issuesHook(form.Events, string(webhook.HookEventIssueTimeTracked))
 
 	// Pull requests
 	w.PullRequest = pullHook(form.Events, "pull_request_only")
@@ -309,6 +318,17 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.PullRequestReview = pullHook(form.Events, "pull_request_review")
 	w.PullRequestSync = pullHook(form.Events, string(webhook.HookEventPullRequestSync))
 
+	// Issue payload
+	if form.IssueBodyMode != "" {
+		w.IssuePayload.BodyMode = webhook.ToIssueBodyMode(form.IssueBodyMode)
+	}
+	if form.IssueBodyMaxLength != nil {
+		w.IssuePayload.BodyMaxLength = *form.IssueBodyMaxLength
+	}
+	if form.StripAssigneeEmails != nil {
+		w.IssuePayload.StripAssigneeEmails = *form.StripAssigneeEmails
+	}
+
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateEvent", err)
 		return false
@@ -42,6 +42,10 @@ func toSrcLink(act *activities_model.Action) string {
 	return act.GetRepoAbsoluteLink() + "/src/" + util.PathEscapeSegments(act.GetBranch())
 }
 
+func toMilestoneLink(act *activities_model.Action) string {
+	return act.GetRepoAbsoluteLink() + "/milestone/" + url.PathEscape(act.GetMilestoneInfos()[0])
+}
+
 func toReleaseLink(act *activities_model.Action) string {
 	return act.GetRepoAbsoluteLink() + "/releases/tag/" + util.PathEscapeSegments(act.GetBranch())
 }
@@ -183,6 +187,18 @@ func feedActionsToFeedItems(ctx *context.Context, actions activities_model.Actio
 		case activities_model.ActionPullReviewDismissed:
 			pullLink := toPullLink(act)
 			title += ctx.TrHTMLEscapeArgs("action.review_dismissed", pullLink, act.GetIssueInfos()[0], act.ShortRepoPath(), act.GetIssueInfos()[1])
+		case activities_model.ActionCloseMilestone:
+			milestoneLink := toMilestoneLink(act)
+			if link.Href == "#" {
+				link.Href = milestoneLink
+			}
+			title += ctx.TrHTMLEscapeArgs("action.close_milestone", act.GetRepoAbsoluteLink(), milestoneLink, act.ShortRepoPath(), act.GetMilestoneInfos()[1])
+		case activities_model.ActionReopenMilestone:
+			milestoneLink := toMilestoneLink(act)
+			if link.Href == "#" {
+				link.Href = milestoneLink
+			}
+			title += ctx.TrHTMLEscapeArgs("action.reopen_milestone", act.GetRepoAbsoluteLink(), milestoneLink, act.ShortRepoPath(), act.GetMilestoneInfos()[1])
 		case activities_model.ActionStarRepo:
 			link.Href = act.GetRepoAbsoluteLink()
 			title += ctx.TrHTMLEscapeArgs("action.starred_repo", act.GetRepoAbsoluteLink(), act.GetRepoPath())
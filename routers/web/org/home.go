@@ -142,6 +142,9 @@ func Home(ctx *context.Context) {
 		ctx.ServerError("CountOrgMembers", err)
 		return
 	}
+	if !loadDefaultMemberAvatar(ctx) {
+		return
+	}
 
 	ctx.Data["Owner"] = org
 	ctx.Data["Repos"] = repos
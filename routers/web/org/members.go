@@ -9,6 +9,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
@@ -65,10 +66,25 @@ func Members(ctx *context.Context) {
 	ctx.Data["MembersIsPublicMember"] = membersIsPublic
 	ctx.Data["MembersIsUserOrgOwner"] = organization.IsUserOrgOwner(members, org.ID)
 	ctx.Data["MembersTwoFaStatus"] = members.GetTwoFaStatus()
+	if !loadDefaultMemberAvatar(ctx) {
+		return
+	}
 
 	ctx.HTML(http.StatusOK, tplMembers)
 }
 
+// loadDefaultMemberAvatar loads the default member avatar of the organization once for all
+// member avatars rendered by the page
+func loadDefaultMemberAvatar(ctx *context.Context) bool {
+	memberAvatar, err := user_model.GetUserSetting(ctx.Org.Organization.ID, user_model.SettingsKeyDefaultMemberAvatar)
+	if err != nil {
+		ctx.ServerError("GetUserSetting", err)
+		return false
+	}
+	ctx.Data["DefaultMemberAvatar"] = memberAvatar
+	return true
+}
+
 // MembersAction response for operation to a member of organization
 func MembersAction(ctx *context.Context) {
 	uid := ctx.FormInt64("uid")
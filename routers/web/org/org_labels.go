@@ -72,7 +72,7 @@ func UpdateLabel(ctx *context.Context) {
 	l.Name = form.Title
 	l.Description = form.Description
 	l.Color = form.Color
-	if err := issues_model.UpdateLabel(l); err != nil {
+	if err := issues_model.UpdateLabel(ctx, l); err != nil {
 		ctx.ServerError("UpdateLabel", err)
 		return
 	}
@@ -5,6 +5,9 @@
 package org
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -19,6 +22,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/typesniffer"
 	"code.gitea.io/gitea/modules/web"
 	user_setting "code.gitea.io/gitea/routers/web/user/setting"
 	"code.gitea.io/gitea/services/forms"
@@ -46,6 +50,14 @@ func Settings(ctx *context.Context) {
 	ctx.Data["PageIsSettingsOptions"] = true
 	ctx.Data["CurrentVisibility"] = ctx.Org.Organization.Visibility
 	ctx.Data["RepoAdminChangeTeamAccess"] = ctx.Org.Organization.RepoAdminChangeTeamAccess
+
+	memberAvatar, err := user_model.GetUserSetting(ctx.Org.Organization.ID, user_model.SettingsKeyDefaultMemberAvatar)
+	if err != nil {
+		ctx.ServerError("GetUserSetting", err)
+		return
+	}
+	ctx.Data["HasDefaultMemberAvatar"] = memberAvatar != ""
+
 	ctx.HTML(http.StatusOK, tplSettingsOptions)
 }
 
@@ -172,6 +184,56 @@ func SettingsDeleteAvatar(ctx *context.Context) {
 	ctx.Redirect(ctx.Org.OrgLink + "/settings")
 }
 
+// SettingsMemberAvatar response for change the default member avatar on settings page
+func SettingsMemberAvatar(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.AvatarForm)
+	if err := updateDefaultMemberAvatar(ctx, form); err != nil {
+		ctx.Flash.Error(err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("org.settings.update_member_avatar_success"))
+	}
+
+	ctx.Redirect(ctx.Org.OrgLink + "/settings")
+}
+
+func updateDefaultMemberAvatar(ctx *context.Context, form *forms.AvatarForm) error {
+	if form.Avatar == nil || form.Avatar.Filename == "" {
+		return errors.New(ctx.Tr("org.settings.member_avatar_required"))
+	}
+	if form.Avatar.Size > setting.Avatar.MaxFileSize {
+		return errors.New(ctx.Tr("settings.uploaded_avatar_is_too_big"))
+	}
+
+	fr, err := form.Avatar.Open()
+	if err != nil {
+		return fmt.Errorf("Avatar.Open: %w", err)
+	}
+	defer fr.Close()
+
+	data, err := io.ReadAll(fr)
+	if err != nil {
+		return fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	st := typesniffer.DetectContentType(data)
+	if !(st.IsImage() && !st.IsSvgImage()) {
+		return errors.New(ctx.Tr("settings.uploaded_avatar_not_a_image"))
+	}
+	if err := user_service.UploadDefaultMemberAvatar(ctx.Org.Organization.AsUser(), data); err != nil {
+		return fmt.Errorf("UploadDefaultMemberAvatar: %w", err)
+	}
+	return nil
+}
+
+// SettingsDeleteMemberAvatar response for delete the default member avatar on settings page
+func SettingsDeleteMemberAvatar(ctx *context.Context) {
+	if err := user_service.DeleteDefaultMemberAvatar(ctx.Org.Organization.AsUser()); err != nil {
+		ctx.Flash.Error(err.Error())
+	}
+
+	ctx.Redirect(ctx.Org.OrgLink + "/settings")
+}
+
 // SettingsDelete response for deleting an organization
 func SettingsDelete(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("org.settings")
@@ -297,6 +297,7 @@ func NewTeamPost(ctx *context.Context) {
 		AccessMode:              p,
 		IncludesAllRepositories: includesAllRepositories,
 		CanCreateOrgRepo:        form.CanCreateOrgRepo,
+		IsPublic:                form.IsPublic,
 	}
 
 	if t.AccessMode < perm.AccessModeAdmin {
@@ -359,6 +360,9 @@ func TeamMembers(ctx *context.Context) {
 	}
 	ctx.Data["Invites"] = invites
 	ctx.Data["IsEmailInviteEnabled"] = setting.MailService != nil
+	if !loadDefaultMemberAvatar(ctx) {
+		return
+	}
 
 	ctx.HTML(http.StatusOK, tplTeamMembers)
 }
@@ -467,6 +471,7 @@ func EditTeamPost(ctx *context.Context) {
 	}
 
 	t.Description = form.Description
+	t.IsPublic = form.IsPublic
 	if t.AccessMode < perm.AccessModeAdmin {
 		units := make([]org_model.TeamUnit, 0, len(unitPerms))
 		for tp, perm := range unitPerms {
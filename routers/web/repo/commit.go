@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/db"
@@ -53,7 +54,19 @@ func Commits(ctx *context.Context) {
 	}
 	ctx.Data["PageIsViewCode"] = true
 
-	commitsCount, err := ctx.Repo.GetCommitsCount()
+	since, until, err := context.GetQuerySinceUntil(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	filterByDate := !since.IsZero() || !until.IsZero()
+
+	var commitsCount int64
+	if filterByDate {
+		commitsCount, err = ctx.Repo.Commit.CommitsCountBetweenDates(since, until)
+	} else {
+		commitsCount, err = ctx.Repo.GetCommitsCount()
+	}
 	if err != nil {
 		ctx.ServerError("GetCommitsCount", err)
 		return
@@ -70,7 +83,12 @@ func Commits(ctx *context.Context) {
 	}
 
 	// Both `git log branchName` and `git log commitId` work.
-	commits, err := ctx.Repo.Commit.CommitsByRange(page, pageSize)
+	var commits []*git.Commit
+	if filterByDate {
+		commits, err = ctx.Repo.Commit.CommitsByRangeBetweenDates(page, pageSize, since, until)
+	} else {
+		commits, err = ctx.Repo.Commit.CommitsByRange(page, pageSize)
+	}
 	if err != nil {
 		ctx.ServerError("CommitsByRange", err)
 		return
@@ -84,6 +102,12 @@ func Commits(ctx *context.Context) {
 
 	pager := context.NewPagination(int(commitsCount), pageSize, page, 5)
 	pager.SetDefaultParams(ctx)
+	if !since.IsZero() {
+		pager.AddParamString("since", since.Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		pager.AddParamString("until", until.Format(time.RFC3339))
+	}
 	ctx.Data["Page"] = pager
 
 	ctx.HTML(http.StatusOK, tplCommits)
@@ -1069,9 +1069,11 @@ func NewIssuePost(ctx *context.Context) {
 	}
 
 	content := form.Content
+	var templateFile string
 	if filename := ctx.Req.Form.Get("template-file"); filename != "" {
 		if template, err := issue_template.UnmarshalFromRepo(ctx.Repo.GitRepo, ctx.Repo.Repository.DefaultBranch, filename); err == nil {
 			content = issue_template.RenderToMarkdown(template, ctx.Req.Form)
+			templateFile = template.FileName
 		}
 	}
 
@@ -1084,6 +1086,7 @@ func NewIssuePost(ctx *context.Context) {
 		MilestoneID: milestoneID,
 		Content:     content,
 		Ref:         form.Ref,
+		Template:    templateFile,
 	}
 
 	if err := issue_service.NewIssue(repo, issue, labelIDs, attachments, assigneeIDs); err != nil {
@@ -2387,7 +2390,7 @@ func SearchIssues(ctx *context.Context) {
 	}
 
 	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues, ctx.Doer))
 }
 
 func getUserIDForFilter(ctx *context.Context, queryName string) int64 {
@@ -2545,7 +2548,7 @@ func ListIssues(ctx *context.Context) {
 	}
 
 	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues, ctx.Doer))
 }
 
 // UpdateIssueStatus change issue's status
@@ -2668,7 +2671,28 @@ func NewComment(ctx *context.Context) {
 				ctx.Flash.Info(ctx.Tr("repo.pulls.open_unmerged_pull_exists", pr.Index))
 			} else {
 				isClosed := form.Status == "close"
-				if err := issue_service.ChangeStatus(issue, ctx.Doer, isClosed); err != nil {
+				var err error
+				if isClosed && form.DuplicateOf > 0 && ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+					var duplicateOf *issues_model.Issue
+					duplicateOf, err = issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, form.DuplicateOf)
+					if issues_model.IsErrIssueNotExist(err) {
+						ctx.Flash.Error(ctx.Tr("repo.issues.duplicate_of.not_exist", form.DuplicateOf))
+						ctx.Redirect(issue.Link())
+						return
+					} else if err != nil {
+						ctx.ServerError("GetIssueByIndex", err)
+						return
+					}
+					err = issue_service.CloseAsDuplicate(issue, ctx.Doer, duplicateOf)
+					if issues_model.IsErrIssueInvalidDuplicate(err) {
+						ctx.Flash.Error(ctx.Tr("repo.issues.duplicate_of.self"))
+						ctx.Redirect(issue.Link())
+						return
+					}
+				} else {
+					err = issue_service.ChangeStatus(issue, ctx.Doer, isClosed)
+				}
+				if err != nil {
 					log.Error("ChangeStatus: %v", err)
 
 					if issues_model.IsErrDependenciesLeft(err) {
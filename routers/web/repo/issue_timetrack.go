@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 // AddTimeManually tracks time manually
@@ -42,7 +43,7 @@ func AddTimeManually(c *context.Context) {
 		return
 	}
 
-	if _, err := issues_model.AddTime(c.Doer, issue, int64(total.Seconds()), time.Now()); err != nil {
+	if _, err := issue_service.AddTime(c, c.Doer, c.Doer, issue, int64(total.Seconds()), time.Now(), form.Billable); err != nil {
 		c.ServerError("AddTime", err)
 		return
 	}
@@ -77,7 +78,7 @@ func DeleteTime(c *context.Context) {
 		return
 	}
 
-	if err = issues_model.DeleteTime(t); err != nil {
+	if err = issue_service.DeleteTime(c, c.Doer, t); err != nil {
 		c.ServerError("DeleteTime", err)
 		return
 	}
@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
+	issue_service "code.gitea.io/gitea/services/issue"
 
 	"xorm.io/builder"
 )
@@ -236,14 +237,25 @@ func ChangeMilestoneStatus(ctx *context.Context) {
 	}
 	id := ctx.ParamsInt64(":id")
 
-	if err := issues_model.ChangeMilestoneStatusByRepoIDAndID(ctx.Repo.Repository.ID, id, toClose); err != nil {
+	m, err := issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, id)
+	if err != nil {
 		if issues_model.IsErrMilestoneNotExist(err) {
 			ctx.NotFound("", err)
 		} else {
-			ctx.ServerError("ChangeMilestoneStatusByIDAndRepoID", err)
+			ctx.ServerError("GetMilestoneByRepoID", err)
 		}
 		return
 	}
+
+	if toClose {
+		err = issue_service.CloseMilestone(ctx, ctx.Doer, m)
+	} else {
+		err = issue_service.ReopenMilestone(ctx, ctx.Doer, m)
+	}
+	if err != nil {
+		ctx.ServerError("ChangeMilestoneStatus", err)
+		return
+	}
 	ctx.Redirect(ctx.Repo.RepoLink + "/milestones?state=" + url.QueryEscape(ctx.Params(":action")))
 }
 
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strings"
 
+	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/perm"
 	project_model "code.gitea.io/gitea/models/project"
@@ -296,6 +297,21 @@ func ViewProject(ctx *context.Context) {
 		boards[0].Title = ctx.Tr("repo.projects.type.uncategorized")
 	}
 
+	labels, err := issues_model.GetLabelsByRepoID(ctx, ctx.Repo.Repository.ID, "", db.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetLabelsByRepoID", err)
+		return
+	}
+	if ctx.Repo.Owner.IsOrganization() {
+		orgLabels, err := issues_model.GetLabelsByOrgID(ctx, ctx.Repo.Owner.ID, "", db.ListOptions{})
+		if err != nil {
+			ctx.ServerError("GetLabelsByOrgID", err)
+			return
+		}
+		labels = append(labels, orgLabels...)
+	}
+	ctx.Data["Labels"] = labels
+
 	issuesMap, err := issues_model.LoadIssuesFromBoardList(ctx, boards)
 	if err != nil {
 		ctx.ServerError("LoadIssuesOfBoards", err)
@@ -444,11 +460,20 @@ func AddBoardToProjectPost(ctx *context.Context) {
 		return
 	}
 
+	var labelID int64
+	if form.LabelID != nil {
+		labelID = *form.LabelID
+		if !checkProjectBoardLabel(ctx, labelID) {
+			return
+		}
+	}
+
 	if err := project_model.NewBoard(&project_model.Board{
 		ProjectID: project.ID,
 		Title:     form.Title,
 		Color:     form.Color,
 		CreatorID: ctx.Doer.ID,
+		LabelID:   labelID,
 	}); err != nil {
 		ctx.ServerError("NewProjectBoard", err)
 		return
@@ -459,6 +484,27 @@ func AddBoardToProjectPost(ctx *context.Context) {
 	})
 }
 
+// checkProjectBoardLabel checks whether the label may back a board of the repository, which is the case
+// for its own labels and the labels of its organization. 0 is allowed as it stands for no label.
+func checkProjectBoardLabel(ctx *context.Context, labelID int64) bool {
+	if labelID == 0 {
+		return true
+	}
+
+	label, err := issues_model.GetLabelByID(ctx, labelID)
+	if err != nil && !issues_model.IsErrLabelNotExist(err) {
+		ctx.ServerError("GetLabelByID", err)
+		return false
+	}
+	if err != nil || (label.RepoID != ctx.Repo.Repository.ID && (!label.BelongsToOrg() || label.OrgID != ctx.Repo.Repository.OwnerID)) {
+		ctx.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"message": fmt.Sprintf("Label[%d] does not belong to Repository[%d]", labelID, ctx.Repo.Repository.ID),
+		})
+		return false
+	}
+	return true
+}
+
 func checkProjectBoardChangePermissions(ctx *context.Context) (*project_model.Project, *project_model.Board) {
 	if ctx.Doer == nil {
 		ctx.JSON(http.StatusForbidden, map[string]string{
@@ -523,11 +569,23 @@ func EditProjectBoard(ctx *context.Context) {
 		board.Sorting = form.Sorting
 	}
 
+	labelChanged := form.LabelID != nil && *form.LabelID != board.LabelID
+	if labelChanged && !checkProjectBoardLabel(ctx, *form.LabelID) {
+		return
+	}
+
 	if err := project_model.UpdateBoard(ctx, board); err != nil {
 		ctx.ServerError("UpdateProjectBoard", err)
 		return
 	}
 
+	if labelChanged {
+		if err := project_model.SetBoardLabel(ctx, board, *form.LabelID); err != nil {
+			ctx.ServerError("SetBoardLabel", err)
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusOK, map[string]interface{}{
 		"ok": true,
 	})
@@ -458,6 +458,7 @@ func SettingsPost(ctx *context.Context) {
 					EnableTimetracker:                form.EnableTimetracker,
 					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               form.EnableIssueDependencies,
+					AutoCloseMilestones:              form.AutoCloseMilestones,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeExternalTracker)
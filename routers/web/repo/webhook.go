@@ -168,6 +168,8 @@ func ParseHookEvent(form forms.WebhookForm) *webhook.HookEvent {
 			IssueLabel:           form.IssueLabel,
 			IssueMilestone:       form.IssueMilestone,
 			IssueComment:         form.IssueComment,
+			IssueTimeTracked:     form.IssueTimeTracked,
+			Milestone:            form.Milestone,
 			Release:              form.Release,
 			Push:                 form.Push,
 			PullRequest:          form.PullRequest,
@@ -182,6 +184,11 @@ func ParseHookEvent(form forms.WebhookForm) *webhook.HookEvent {
 			Package:              form.Package,
 		},
 		BranchFilter: form.BranchFilter,
+		IssuePayload: webhook.IssuePayloadSettings{
+			BodyMode:            webhook.IssueBodyMode(form.IssueBodyMode),
+			BodyMaxLength:       form.IssueBodyMaxLength,
+			StripAssigneeEmails: form.StripAssigneeEmails,
+		},
 	}
 }
 
@@ -7,6 +7,7 @@ package setting
 import (
 	"errors"
 	"fmt"
+	"image"
 	"io"
 	"math/big"
 	"net/http"
@@ -18,6 +19,7 @@ import (
 	"code.gitea.io/gitea/models/organization"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/avatar"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
@@ -178,7 +180,20 @@ func UpdateAvatarSetting(ctx *context.Context, form *forms.AvatarForm, ctxUser *
 		if !(st.IsImage() && !st.IsSvgImage()) {
 			return errors.New(ctx.Tr("settings.uploaded_avatar_not_a_image"))
 		}
-		if err = user_service.UploadAvatar(ctxUser, data); err != nil {
+		if form.CropWidth > 0 || form.CropHeight > 0 {
+			crop := image.Rect(form.CropX, form.CropY, form.CropX+form.CropWidth, form.CropY+form.CropHeight)
+			if err = user_service.UploadAvatarWithCrop(ctxUser, data, crop); err != nil {
+				if avatar.IsErrAvatarCropOutOfBounds(err) {
+					return errors.New(ctx.Tr("settings.uploaded_avatar_crop_out_of_bounds"))
+				} else if avatar.IsErrAvatarUnsupportedType(err) {
+					return errors.New(ctx.Tr("settings.uploaded_avatar_unsupported_type"))
+				}
+				return fmt.Errorf("UploadAvatarWithCrop: %w", err)
+			}
+		} else if err = user_service.UploadAvatar(ctxUser, data); err != nil {
+			if avatar.IsErrAvatarUnsupportedType(err) {
+				return errors.New(ctx.Tr("settings.uploaded_avatar_unsupported_type"))
+			}
 			return fmt.Errorf("UploadAvatar: %w", err)
 		}
 	} else if ctxUser.UseCustomAvatar && ctxUser.Avatar == "" {
@@ -724,6 +724,8 @@ func RegisterRoutes(m *web.Route) {
 					Post(bindIgnErr(forms.UpdateOrgSettingForm{}), org.SettingsPost)
 				m.Post("/avatar", bindIgnErr(forms.AvatarForm{}), org.SettingsAvatar)
 				m.Post("/avatar/delete", org.SettingsDeleteAvatar)
+				m.Post("/member_avatar", bindIgnErr(forms.AvatarForm{}), org.SettingsMemberAvatar)
+				m.Post("/member_avatar/delete", org.SettingsDeleteMemberAvatar)
 				m.Group("/applications", func() {
 					m.Get("", org.Applications)
 					m.Post("/oauth2", bindIgnErr(forms.EditOAuth2ApplicationForm{}), org.OAuthApplicationsPost)
@@ -165,9 +165,9 @@ func (source *Source) Sync(ctx context.Context, updateExisting bool) error {
 				}
 			}
 
-			if usr.IsUploadAvatarChanged(su.Avatar) {
-				if err == nil && len(source.AttributeAvatar) > 0 {
-					_ = user_service.UploadAvatar(usr, su.Avatar)
+			if err == nil && len(source.AttributeAvatar) > 0 {
+				if err := user_service.UploadAvatarIfChanged(usr, su.Avatar); err != nil {
+					log.Error("SyncExternalUsers[%s]: Error updating avatar of user %s: %v", source.authSource.Name, usr.Name, err)
 				}
 			}
 		}
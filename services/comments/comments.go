@@ -16,13 +16,20 @@ import (
 
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, content string, attachments []string) (*issues_model.Comment, error) {
+	return CreateIssueCommentFromSavedReply(ctx, doer, repo, issue, content, attachments, 0)
+}
+
+// CreateIssueCommentFromSavedReply creates a plain issue comment whose content was created from the saved reply with
+// the given ID, 0 if it was written by hand.
+func CreateIssueCommentFromSavedReply(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, content string, attachments []string, savedReplyID int64) (*issues_model.Comment, error) {
 	comment, err := issues_model.CreateComment(&issues_model.CreateCommentOptions{
-		Type:        issues_model.CommentTypeComment,
-		Doer:        doer,
-		Repo:        repo,
-		Issue:       issue,
-		Content:     content,
-		Attachments: attachments,
+		Type:         issues_model.CommentTypeComment,
+		Doer:         doer,
+		Repo:         repo,
+		Issue:        issue,
+		Content:      content,
+		Attachments:  attachments,
+		SavedReplyID: savedReplyID,
 	})
 	if err != nil {
 		return nil, err
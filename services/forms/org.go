@@ -66,6 +66,7 @@ type CreateTeamForm struct {
 	Permission       string
 	RepoAccess       string
 	CanCreateOrgRepo bool
+	IsPublic         bool
 }
 
 // Validate validates the fields
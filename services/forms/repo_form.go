@@ -160,6 +160,7 @@ type RepoSettingForm struct {
 	EnableTimetracker                     bool
 	AllowOnlyContributorsToTrackTime      bool
 	EnableIssueDependencies               bool
+	AutoCloseMilestones                   bool
 	IsArchived                            bool
 
 	// Signing Settings
@@ -232,6 +233,8 @@ type WebhookForm struct {
 	IssueLabel           bool
 	IssueMilestone       bool
 	IssueComment         bool
+	IssueTimeTracked     bool
+	Milestone            bool
 	Release              bool
 	Push                 bool
 	PullRequest          bool
@@ -247,6 +250,9 @@ type WebhookForm struct {
 	Active               bool
 	BranchFilter         string `binding:"GlobPattern"`
 	AuthorizationHeader  string
+	IssueBodyMode        string `binding:"In(,truncated,omitted)"`
+	IssueBodyMaxLength   int
+	StripAssigneeEmails  bool
 }
 
 // PushOnly if the hook will be triggered when push
@@ -448,9 +454,10 @@ func (f *CreateIssueForm) Validate(req *http.Request, errs binding.Errors) bindi
 
 // CreateCommentForm form for creating comment
 type CreateCommentForm struct {
-	Content string
-	Status  string `binding:"OmitEmpty;In(reopen,close)"`
-	Files   []string
+	Content     string
+	Status      string `binding:"OmitEmpty;In(reopen,close)"`
+	DuplicateOf int64
+	Files       []string
 }
 
 // Validate validates the fields
@@ -525,6 +532,8 @@ type EditProjectBoardForm struct {
 	Title   string `binding:"Required;MaxSize(100)"`
 	Sorting int8
 	Color   string `binding:"MaxSize(7)"`
+	// the label which backs the board, 0 unsets it, nil keeps it
+	LabelID *int64 `json:"label_id"`
 }
 
 //    _____  .__.__                   __
@@ -864,8 +873,9 @@ func (f *DeleteRepoFileForm) Validate(req *http.Request, errs binding.Errors) bi
 
 // AddTimeManuallyForm form that adds spent time manually.
 type AddTimeManuallyForm struct {
-	Hours   int `binding:"Range(0,1000)"`
-	Minutes int `binding:"Range(0,1000)"`
+	Hours    int `binding:"Range(0,1000)"`
+	Minutes  int `binding:"Range(0,1000)"`
+	Billable bool
 }
 
 // Validate validates the fields
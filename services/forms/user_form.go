@@ -278,6 +278,11 @@ type AvatarForm struct {
 	Avatar      *multipart.FileHeader
 	Gravatar    string `binding:"OmitEmpty;Email;MaxSize(254)"`
 	Federavatar bool
+	// region of the uploaded image to use, the center is cropped if no width and height are given
+	CropX      int
+	CropY      int
+	CropWidth  int
+	CropHeight int
 }
 
 // Validate validates the fields
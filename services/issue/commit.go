@@ -83,7 +83,7 @@ func issueAddTime(issue *issues_model.Issue, doer *user_model.User, time time.Ti
 		return nil
 	}
 
-	_, err := issues_model.AddTime(doer, issue, amount, time)
+	_, err := AddTime(db.DefaultContext, doer, doer, issue, amount, time, false)
 	return err
 }
 
@@ -4,6 +4,7 @@
 package issue
 
 import (
+	"context"
 	"fmt"
 
 	activities_model "code.gitea.io/gitea/models/activities"
@@ -205,6 +206,20 @@ func GetRefEndNamesAndURLs(issues []*issues_model.Issue, repoLink string) (map[i
 	return issueRefEndNames, issueRefURLs
 }
 
+// RecalculateIssueCommentCount recounts the comments of the issue, repairing its counter if it has drifted
+// from the actual comments, and updates issue.NumComments
+func RecalculateIssueCommentCount(ctx context.Context, issue *issues_model.Issue) error {
+	if err := issues_model.UpdateIssueNumComments(ctx, issue.ID); err != nil {
+		return err
+	}
+	counter := new(issues_model.Issue)
+	if _, err := db.GetEngine(ctx).ID(issue.ID).Cols("num_comments").Get(counter); err != nil {
+		return err
+	}
+	issue.NumComments = counter.NumComments
+	return nil
+}
+
 // deleteIssue deletes the issue
 func deleteIssue(issue *issues_model.Issue) error {
 	ctx, committer, err := db.TxContext(db.DefaultContext)
@@ -4,6 +4,7 @@
 package issue
 
 import (
+	"context"
 	"testing"
 
 	"code.gitea.io/gitea/models/db"
@@ -84,3 +85,61 @@ func TestIssue_DeleteIssue(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, left)
 }
+
+func TestRecalculateIssueCommentCount(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.EqualValues(t, 2, issue.NumComments)
+	comments := func() int64 {
+		count, err := db.GetEngine(db.DefaultContext).Where("issue_id = ? AND type = ?", issue.ID, issues_model.CommentTypeComment).
+			Count(new(issues_model.Comment))
+		assert.NoError(t, err)
+		return count
+	}
+
+	// bulk deletions bypass the counter
+	var ids []int64
+	assert.NoError(t, db.GetEngine(db.DefaultContext).Table("comment").
+		Where("issue_id = ? AND type = ?", issue.ID, issues_model.CommentTypeComment).Cols("id").Find(&ids))
+	_, err := db.GetEngine(db.DefaultContext).In("id", ids[0]).Delete(new(issues_model.Comment))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, comments())
+	assert.EqualValues(t, 2, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}).NumComments)
+
+	assert.NoError(t, RecalculateIssueCommentCount(db.DefaultContext, issue))
+	assert.EqualValues(t, 1, issue.NumComments)
+	assert.EqualValues(t, 1, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}).NumComments)
+
+	// deleting a comment keeps the counter in sync
+	comment := new(issues_model.Comment)
+	has, err := db.GetEngine(db.DefaultContext).Where("issue_id = ? AND type = ?", issue.ID, issues_model.CommentTypeComment).Get(comment)
+	assert.NoError(t, err)
+	assert.True(t, has)
+	assert.NoError(t, db.WithTx(db.DefaultContext, func(ctx context.Context) error {
+		return issues_model.DeleteComment(ctx, comment)
+	}))
+	assert.EqualValues(t, 0, comments())
+	assert.EqualValues(t, 0, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}).NumComments)
+}
+
+func TestCloseAsDuplicate(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	duplicateOf := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5})
+
+	err := CloseAsDuplicate(issue, doer, issue)
+	assert.True(t, issues_model.IsErrIssueInvalidDuplicate(err))
+	// issue 4 belongs to another repository
+	err = CloseAsDuplicate(issue, doer, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 4}))
+	assert.True(t, issues_model.IsErrIssueInvalidDuplicate(err))
+
+	assert.NoError(t, CloseAsDuplicate(issue, doer, duplicateOf))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1, IsClosed: true, DuplicateOfID: 5})
+
+	// reopening the issue unsets the duplicate
+	assert.NoError(t, ChangeStatus(issue, doer, false))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.False(t, issue.IsClosed)
+	assert.Zero(t, issue.DuplicateOfID)
+}
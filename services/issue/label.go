@@ -4,11 +4,18 @@
 package issue
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/notification"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // ClearLabels clears all of an issue's labels
@@ -91,3 +98,187 @@ func ReplaceLabels(issue *issues_model.Issue, doer *user_model.User, labels []*i
 	notification.NotifyIssueChangeLabels(db.DefaultContext, doer, issue, labels, old)
 	return nil
 }
+
+// SetIssueLabels changes the labels of the issue to the labels with the given ids in one transaction.
+// Of several labels of the same exclusive scope only the last one is applied. A label which does not belong
+// to the repository or its owner is rejected with an ErrLabelNotExist.
+// Only the difference to the current labels is applied and a single notification is sent for it.
+func SetIssueLabels(ctx context.Context, issue *issues_model.Issue, doer *user_model.User, labelIDs []int64) error {
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+
+	labelsByID := make(map[int64]*issues_model.Label, len(labelIDs))
+	lastOfScope := make(map[string]int64)
+	for _, id := range labelIDs {
+		label, ok := labelsByID[id]
+		if !ok {
+			var err error
+			label, err = issues_model.GetLabelByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if label.RepoID != issue.RepoID && label.OrgID != issue.Repo.OwnerID {
+				return issues_model.ErrLabelNotExist{LabelID: id}
+			}
+			labelsByID[id] = label
+		}
+		if scope := label.ExclusiveScope(); scope != "" {
+			lastOfScope[scope] = id
+		}
+	}
+
+	labels := make([]*issues_model.Label, 0, len(labelsByID))
+	for _, id := range labelIDs {
+		label, ok := labelsByID[id]
+		if !ok {
+			continue
+		}
+		delete(labelsByID, id)
+		if scope := label.ExclusiveScope(); scope == "" || lastOfScope[scope] == id {
+			labels = append(labels, label)
+		}
+	}
+
+	var added, removed []*issues_model.Label
+	if err := db.WithTx(ctx, func(ctx context.Context) (err error) {
+		added, removed, err = issues_model.SetIssueLabels(ctx, issue, labels, doer)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if len(added) > 0 || len(removed) > 0 {
+		notification.NotifyIssueChangeLabels(ctx, doer, issue, added, removed)
+	}
+	return nil
+}
+
+// MergeLabels moves all issues of the repository label fromLabelID to the label intoLabelID and deletes
+// the former, the updated target label is returned.
+func MergeLabels(ctx context.Context, repo *repo_model.Repository, fromLabelID, intoLabelID int64) (*issues_model.Label, error) {
+	if fromLabelID == intoLabelID {
+		return nil, fmt.Errorf("cannot merge label %d into itself: %w", fromLabelID, util.ErrInvalidArgument)
+	}
+
+	from, err := issues_model.GetLabelInRepoByID(ctx, repo.ID, fromLabelID)
+	if err != nil {
+		return nil, err
+	}
+	into, err := issues_model.GetLabelInRepoByID(ctx, repo.ID, intoLabelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := issues_model.MergeLabels(ctx, from, into); err != nil {
+		return nil, err
+	}
+
+	// reload the label to get the recalculated issue counters
+	return issues_model.GetLabelByID(ctx, into.ID)
+}
+
+// ImportLabels creates the labels defined by the templates in the repository. Labels which already exist with
+// the same name get the color and description of the template, so importing the same templates again changes nothing.
+func ImportLabels(ctx context.Context, repo *repo_model.Repository, templates []*api.LabelTemplate) error {
+	labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	labelsByName := make(map[string]*issues_model.Label, len(labels))
+	for _, label := range labels {
+		labelsByName[label.Name] = label
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		for _, template := range templates {
+			name := strings.TrimSpace(template.Name)
+			if name == "" {
+				return fmt.Errorf("label name cannot be empty: %w", util.ErrInvalidArgument)
+			}
+			color, err := issues_model.NormalizeLabelColor(template.Color)
+			if err != nil {
+				return fmt.Errorf("label %q: %v: %w", name, err, util.ErrInvalidArgument)
+			}
+
+			if label, ok := labelsByName[name]; ok {
+				if label.Color == color && label.Description == template.Description {
+					continue
+				}
+				label.Color = color
+				label.Description = template.Description
+				if err := issues_model.UpdateLabel(ctx, label); err != nil {
+					return err
+				}
+				continue
+			}
+
+			label := &issues_model.Label{
+				RepoID:      repo.ID,
+				Name:        name,
+				Color:       color,
+				Description: template.Description,
+			}
+			if err := issues_model.NewLabel(ctx, label); err != nil {
+				return err
+			}
+			labelsByName[name] = label
+		}
+		return nil
+	})
+}
+
+// RenameLabel changes the name of a label, surrounding whitespace is removed from the new name.
+// It fails with an ErrLabelExist if the repository or organization already has a label of that name,
+// and with an ErrLabelScopeConflict if the label moves into a scope of which one of its issues already
+// carries a label. Renaming the label within its scope is always possible.
+func RenameLabel(ctx context.Context, label *issues_model.Label, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return fmt.Errorf("label name cannot be empty: %w", util.ErrInvalidArgument)
+	}
+	if newName == label.Name {
+		return nil
+	}
+	oldName := label.Name
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		return issues_model.RenameLabel(ctx, label, newName)
+	}); err != nil {
+		label.Name = oldName
+		return err
+	}
+	return nil
+}
+
+// ReorderLabels stores a custom order for the labels of the repository. The labels with the given ids are
+// moved to the front in the given order, all other labels keep their order behind them.
+func ReorderLabels(ctx context.Context, repo *repo_model.Repository, labelIDs []int64) error {
+	labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "order", db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	labelsMap := make(map[int64]*issues_model.Label, len(labels))
+	for _, label := range labels {
+		labelsMap[label.ID] = label
+	}
+
+	ordered := make([]*issues_model.Label, 0, len(labels))
+	for _, id := range labelIDs {
+		label, ok := labelsMap[id]
+		if !ok {
+			return issues_model.ErrRepoLabelNotExist{LabelID: id, RepoID: repo.ID}
+		}
+		delete(labelsMap, id)
+		ordered = append(ordered, label)
+	}
+	for _, label := range labels {
+		if _, ok := labelsMap[label.ID]; ok {
+			ordered = append(ordered, label)
+		}
+	}
+
+	for i, label := range ordered {
+		label.Order = i + 1
+	}
+	return issues_model.UpdateLabelOrders(ctx, ordered)
+}
@@ -6,9 +6,15 @@ package issue
 import (
 	"testing"
 
+	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
+	project_model "code.gitea.io/gitea/models/project"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -59,3 +65,197 @@ func TestIssue_AddLabel(t *testing.T) {
 		unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: test.issueID, LabelID: test.labelID})
 	}
 }
+
+func TestMergeLabels(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	// issue 1 already carries label 1, label 2 is additionally added to get an overlap
+	assert.NoError(t, db.Insert(db.DefaultContext, &issues_model.IssueLabel{IssueID: 1, LabelID: 2}))
+	board := unittest.AssertExistsAndLoadBean(t, &project_model.Board{ID: 1})
+	assert.NoError(t, project_model.SetBoardLabel(db.DefaultContext, board, 2))
+
+	into, err := MergeLabels(db.DefaultContext, repo, 2, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, into.ID)
+	assert.EqualValues(t, 3, into.NumIssues)
+	assert.EqualValues(t, 1, into.NumClosedIssues)
+
+	unittest.AssertNotExistsBean(t, &issues_model.Label{ID: 2})
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{LabelID: 2})
+	unittest.AssertCount(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 1}, 1)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: 5, LabelID: 1})
+	unittest.AssertExistsAndLoadBean(t, &project_model.Board{ID: 1, LabelID: 1})
+	unittest.CheckConsistencyFor(t, &issues_model.Label{})
+
+	labels, err := issues_model.GetLabelsByRepoID(db.DefaultContext, repo.ID, "", db.ListOptions{})
+	assert.NoError(t, err)
+	apiLabels := convert.ToLabelList(db.DefaultContext, labels, repo, nil)
+	if assert.Len(t, apiLabels, 1) {
+		assert.EqualValues(t, 1, apiLabels[0].ID)
+	}
+
+	_, err = MergeLabels(db.DefaultContext, repo, 1, 1)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = MergeLabels(db.DefaultContext, repo, 3, 1)
+	assert.True(t, issues_model.IsErrRepoLabelNotExist(err))
+}
+
+func TestRenameLabel(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+
+	assert.NoError(t, RenameLabel(db.DefaultContext, label, " priority/high "))
+	assert.Equal(t, "priority/high", label.Name)
+	label = unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+	assert.Equal(t, "priority/high", label.Name)
+	apiLabel := convert.ToLabel(db.DefaultContext, label, repo, nil)
+	assert.Equal(t, "priority/high", apiLabel.Name)
+	assert.True(t, apiLabel.Exclusive)
+	assert.Equal(t, "priority", apiLabel.Scope)
+	// the issue counters are kept
+	assert.EqualValues(t, 2, label.NumIssues)
+	unittest.CheckConsistencyFor(t, &issues_model.Label{})
+
+	err := RenameLabel(db.DefaultContext, label, "  ")
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1, Name: "priority/high"})
+
+	// issue 1 carries both labels from now on
+	other := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 2})
+	assert.NoError(t, issues_model.NewIssueLabel(unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}), other, doer))
+
+	// moving the other label into the scope would give issue 1 two labels of it
+	err = RenameLabel(db.DefaultContext, other, "priority/low")
+	assert.True(t, issues_model.IsErrLabelScopeConflict(err))
+	assert.Equal(t, issues_model.ErrLabelScopeConflict{IssueID: 1, Scope: "priority"}, err)
+	assert.Equal(t, "label2", other.Name)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 2, Name: "label2"})
+
+	// renaming within the scope keeps the label exclusive
+	assert.NoError(t, RenameLabel(db.DefaultContext, label, "priority/urgent"))
+	assert.Equal(t, "priority", convert.ToLabel(db.DefaultContext, label, repo, nil).Scope)
+
+	// another scope, or none, does not conflict
+	assert.NoError(t, RenameLabel(db.DefaultContext, other, "severity/low"))
+	assert.Equal(t, "severity", convert.ToLabel(db.DefaultContext, other, repo, nil).Scope)
+	assert.NoError(t, RenameLabel(db.DefaultContext, label, "urgent"))
+	apiLabel = convert.ToLabel(db.DefaultContext, label, repo, nil)
+	assert.False(t, apiLabel.Exclusive)
+	assert.Empty(t, apiLabel.Scope)
+
+	// the name of another label of the repository cannot be taken
+	err = RenameLabel(db.DefaultContext, other, "urgent")
+	assert.True(t, issues_model.IsErrLabelExist(err))
+	assert.ErrorIs(t, err, util.ErrAlreadyExist)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 2, Name: "severity/low"})
+}
+
+func TestReorderLabels(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	// a new label is appended to the order
+	label := &issues_model.Label{RepoID: repo.ID, Name: "label0", Color: "#000000"}
+	assert.NoError(t, issues_model.NewLabel(db.DefaultContext, label))
+	assert.EqualValues(t, 1, label.Order)
+
+	assert.NoError(t, ReorderLabels(db.DefaultContext, repo, []int64{label.ID, 2}))
+
+	labels, err := issues_model.GetLabelsByRepoID(db.DefaultContext, repo.ID, "order", db.ListOptions{})
+	assert.NoError(t, err)
+	apiLabels := convert.ToLabelList(db.DefaultContext, labels, repo, nil)
+	if assert.Len(t, apiLabels, 3) {
+		assert.Equal(t, []int64{label.ID, 2, 1}, []int64{apiLabels[0].ID, apiLabels[1].ID, apiLabels[2].ID})
+		assert.Equal(t, []int{1, 2, 3}, []int{apiLabels[0].Order, apiLabels[1].Order, apiLabels[2].Order})
+	}
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1, Order: 3})
+
+	err = ReorderLabels(db.DefaultContext, repo, []int64{1, 3})
+	assert.True(t, issues_model.IsErrRepoLabelNotExist(err))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1, Order: 3})
+}
+
+func TestSetIssueLabels(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	comments := unittest.GetCount(t, &issues_model.Comment{IssueID: 1, Type: issues_model.CommentTypeLabel})
+
+	// issue 1 carries label 1, label 3 belongs to another repository
+	err := SetIssueLabels(db.DefaultContext, issue, doer, []int64{2, 3})
+	assert.True(t, issues_model.IsErrLabelNotExist(err))
+	assert.Equal(t, issues_model.ErrLabelNotExist{LabelID: 3}, err)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 1})
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 2})
+
+	assert.NoError(t, SetIssueLabels(db.DefaultContext, issue, doer, []int64{2, 2}))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 2})
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 1})
+	unittest.AssertCount(t, &issues_model.Comment{IssueID: 1, Type: issues_model.CommentTypeLabel}, comments+2)
+	unittest.CheckConsistencyFor(t, &issues_model.Label{})
+
+	apiIssue := convert.ToAPIIssue(db.DefaultContext, issue)
+	if assert.Len(t, apiIssue.Labels, 1) {
+		assert.EqualValues(t, 2, apiIssue.Labels[0].ID)
+	}
+
+	// applying the same labels again changes nothing
+	assert.NoError(t, SetIssueLabels(db.DefaultContext, issue, doer, []int64{2}))
+	unittest.AssertCount(t, &issues_model.Comment{IssueID: 1, Type: issues_model.CommentTypeLabel}, comments+2)
+
+	err = SetIssueLabels(db.DefaultContext, issue, doer, []int64{1, 999})
+	assert.True(t, issues_model.IsErrLabelNotExist(err))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 2})
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 1})
+
+	// of two labels of the same exclusive scope only the last one is applied
+	high := &issues_model.Label{RepoID: issue.RepoID, Name: "priority/high", Color: "#ff0000"}
+	low := &issues_model.Label{RepoID: issue.RepoID, Name: "priority/low", Color: "#00ff00"}
+	assert.NoError(t, issues_model.NewLabels(high, low))
+	assert.NoError(t, SetIssueLabels(db.DefaultContext, issue, doer, []int64{high.ID, 2, low.ID}))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: low.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 2})
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: high.ID})
+
+	// the label applied last displaces the current one of its scope
+	assert.NoError(t, SetIssueLabels(db.DefaultContext, issue, doer, []int64{low.ID, high.ID}))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: high.ID})
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: low.ID})
+	unittest.CheckConsistencyFor(t, &issues_model.Label{})
+
+	apiIssue = convert.ToAPIIssue(db.DefaultContext, issue)
+	if assert.Len(t, apiIssue.Labels, 1) {
+		assert.Equal(t, high.ID, apiIssue.Labels[0].ID)
+	}
+}
+
+func TestImportLabels(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	from := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	to := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+	unittest.AssertCount(t, &issues_model.Label{RepoID: to.ID}, 0)
+
+	labels, err := issues_model.GetLabelsByRepoID(db.DefaultContext, from.ID, "order", db.ListOptions{})
+	assert.NoError(t, err)
+	templates := convert.ToLabelTemplate(labels)
+	assert.Len(t, templates, 2)
+
+	assert.NoError(t, ImportLabels(db.DefaultContext, to, templates))
+	imported, err := issues_model.GetLabelsByRepoID(db.DefaultContext, to.ID, "order", db.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, templates, convert.ToLabelTemplate(imported))
+
+	// importing again does not duplicate the labels but updates changed ones
+	templates[0].Color = "F0F"
+	templates[0].Description = "changed"
+	assert.NoError(t, ImportLabels(db.DefaultContext, to, templates))
+	unittest.AssertCount(t, &issues_model.Label{RepoID: to.ID}, 2)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Label{RepoID: to.ID, Name: templates[0].Name, Color: "#ff00ff", Description: "changed"})
+
+	err = ImportLabels(db.DefaultContext, to, []*api.LabelTemplate{{Name: "new", Color: "not a color"}})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	unittest.AssertCount(t, &issues_model.Label{RepoID: to.ID}, 2)
+}
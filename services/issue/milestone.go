@@ -6,9 +6,11 @@ package issue
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/notification"
 )
@@ -82,3 +84,74 @@ func ChangeMilestoneAssign(issue *issues_model.Issue, doer *user_model.User, old
 
 	return nil
 }
+
+// CloneMilestone creates an open milestone in the target repository with the name, description and deadline of the given one.
+// Its issues are not copied. It returns an ErrMilestoneAlreadyExist if the target repository has a milestone with the same name.
+func CloneMilestone(ctx context.Context, src *issues_model.Milestone, targetRepo *repo_model.Repository) (*issues_model.Milestone, error) {
+	name := strings.TrimSpace(src.Name)
+	if _, err := issues_model.GetMilestoneByRepoIDANDName(targetRepo.ID, name); err == nil {
+		return nil, issues_model.ErrMilestoneAlreadyExist{RepoID: targetRepo.ID, Name: name}
+	} else if !issues_model.IsErrMilestoneNotExist(err) {
+		return nil, err
+	}
+
+	clone := &issues_model.Milestone{
+		RepoID:       targetRepo.ID,
+		Repo:         targetRepo,
+		Name:         name,
+		Content:      src.Content,
+		DeadlineUnix: src.DeadlineUnix,
+	}
+	if err := issues_model.NewMilestone(clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// CloseMilestone closes a milestone, as the given user.
+func CloseMilestone(ctx context.Context, doer *user_model.User, m *issues_model.Milestone) error {
+	return changeMilestoneStatus(ctx, doer, m, true)
+}
+
+// ReopenMilestone reopens a milestone, as the given user.
+func ReopenMilestone(ctx context.Context, doer *user_model.User, m *issues_model.Milestone) error {
+	return changeMilestoneStatus(ctx, doer, m, false)
+}
+
+// SyncMilestoneStatus closes the milestone of the issue once its last open issue has been closed and reopens it
+// when one of its issues is reopened, if the repository is configured to do so.
+// It has to be called after every change of the status of an issue, including the merge of a pull request.
+func SyncMilestoneStatus(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) error {
+	if issue.MilestoneID == 0 {
+		return nil
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if !issue.Repo.IsAutoCloseMilestonesEnabled(ctx) {
+		return nil
+	}
+
+	m, err := issues_model.GetMilestoneByRepoID(ctx, issue.RepoID, issue.MilestoneID)
+	if err != nil {
+		return err
+	}
+	if issue.IsClosed && m.NumOpenIssues > 0 {
+		return nil
+	}
+	return changeMilestoneStatus(ctx, doer, m, issue.IsClosed)
+}
+
+func changeMilestoneStatus(ctx context.Context, doer *user_model.User, m *issues_model.Milestone, isClosed bool) error {
+	if m.IsClosed == isClosed {
+		return nil
+	}
+
+	if err := issues_model.ChangeMilestoneStatus(m, isClosed); err != nil {
+		return err
+	}
+
+	notification.NotifyMilestoneChangeStatus(ctx, doer, m)
+
+	return nil
+}
@@ -5,10 +5,17 @@ package issue
 
 import (
 	"testing"
+	"time"
 
+	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,3 +38,111 @@ func TestChangeMilestoneAssign(t *testing.T) {
 	})
 	unittest.CheckConsistencyFor(t, &issues_model.Milestone{}, &issues_model.Issue{})
 }
+
+func TestCloseReopenMilestone(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	milestone := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	deadline := milestone.DeadlineUnix
+	assert.False(t, milestone.IsClosed)
+	assert.Nil(t, convert.ToAPIMilestone(milestone).Closed)
+
+	timeutil.Set(time.Date(2022, time.November, 1, 12, 0, 0, 0, time.UTC))
+	defer timeutil.Unset()
+
+	assert.NoError(t, CloseMilestone(db.DefaultContext, doer, milestone))
+	milestone = unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	assert.True(t, milestone.IsClosed)
+	assert.Equal(t, deadline, milestone.DeadlineUnix)
+	apiMilestone := convert.ToAPIMilestone(milestone)
+	assert.Equal(t, api.StateClosed, apiMilestone.State)
+	if assert.NotNil(t, apiMilestone.Closed) {
+		assert.EqualValues(t, time.Date(2022, time.November, 1, 12, 0, 0, 0, time.UTC).Unix(), apiMilestone.Closed.Unix())
+	}
+
+	assert.NoError(t, ReopenMilestone(db.DefaultContext, doer, milestone))
+	milestone = unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	assert.False(t, milestone.IsClosed)
+	assert.Zero(t, milestone.ClosedDateUnix)
+	assert.Equal(t, deadline, milestone.DeadlineUnix)
+	apiMilestone = convert.ToAPIMilestone(milestone)
+	assert.Equal(t, api.StateOpen, apiMilestone.State)
+	assert.Nil(t, apiMilestone.Closed)
+
+	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: milestone.RepoID})
+}
+
+func TestSyncMilestoneStatus(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	issue1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	issue2 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+
+	timeutil.Set(time.Date(2022, time.November, 1, 12, 0, 0, 0, time.UTC))
+	defer timeutil.Unset()
+
+	// milestone 1 only has issue 2 and the repository does not close milestones
+	assert.NoError(t, ChangeStatus(issue2, doer, true))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1, IsClosed: false})
+	assert.NoError(t, ChangeStatus(issue2, doer, false))
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	issuesUnit, err := repo.GetUnit(unit.TypeIssues)
+	assert.NoError(t, err)
+	config := issuesUnit.IssuesConfig()
+	config.AutoCloseMilestones = true
+	_, err = db.GetEngine(db.DefaultContext).ID(issuesUnit.ID).Cols("config").Update(&repo_model.RepoUnit{Config: config})
+	assert.NoError(t, err)
+	// reload the issue, its repository caches the units
+	issue2 = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+
+	issue1.MilestoneID = 1
+	assert.NoError(t, ChangeMilestoneAssign(issue1, doer, 0))
+
+	// issue 1 is still open
+	assert.NoError(t, ChangeStatus(issue2, doer, true))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1, IsClosed: false})
+
+	assert.NoError(t, ChangeStatus(issue1, doer, true))
+	milestone := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	assert.True(t, milestone.IsClosed)
+	if closed := convert.ToAPIMilestone(milestone).Closed; assert.NotNil(t, closed) {
+		assert.EqualValues(t, time.Date(2022, time.November, 1, 12, 0, 0, 0, time.UTC).Unix(), closed.Unix())
+	}
+
+	// reopening any issue reopens the milestone
+	assert.NoError(t, ChangeStatus(issue2, doer, false))
+	milestone = unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1})
+	assert.False(t, milestone.IsClosed)
+	assert.Nil(t, convert.ToAPIMilestone(milestone).Closed)
+
+	unittest.CheckConsistencyFor(t, &issues_model.Milestone{}, &repo_model.Repository{ID: 1})
+}
+
+func TestCloneMilestone(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	src := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 3})
+	target := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 42})
+
+	// the clone is open and has no issues, even though the milestone is closed and has one
+	clone, err := CloneMilestone(db.DefaultContext, src, target)
+	assert.NoError(t, err)
+	apiMilestone := convert.ToAPIMilestone(clone)
+	assert.Equal(t, "milestone3", apiMilestone.Title)
+	assert.Equal(t, "content3", apiMilestone.Description)
+	assert.Equal(t, api.StateOpen, apiMilestone.State)
+	assert.Zero(t, apiMilestone.OpenIssues)
+	assert.Zero(t, apiMilestone.ClosedIssues)
+	assert.Equal(t, src.DeadlineUnix, clone.DeadlineUnix)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: clone.ID, RepoID: 42, Name: "milestone3"})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: src.ID, IsClosed: true, NumIssues: 1})
+
+	// the target repository already has a milestone of the same name
+	_, err = CloneMilestone(db.DefaultContext, src, target)
+	assert.True(t, issues_model.IsErrMilestoneAlreadyExist(err))
+	_, err = CloneMilestone(db.DefaultContext, unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 4}), unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 42}))
+	assert.True(t, issues_model.IsErrMilestoneAlreadyExist(err))
+	unittest.AssertCount(t, &issues_model.Milestone{RepoID: 42}, 2)
+
+	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: 42})
+}
@@ -18,6 +18,19 @@ func ChangeStatus(issue *issues_model.Issue, doer *user_model.User, closed bool)
 	return changeStatusCtx(db.DefaultContext, issue, doer, closed)
 }
 
+// CloseAsDuplicate closes the issue as a duplicate of another issue of its repository.
+// An issue which is already closed is only marked as duplicate.
+func CloseAsDuplicate(issue *issues_model.Issue, doer *user_model.User, duplicateOf *issues_model.Issue) error {
+	if duplicateOf.ID == issue.ID || duplicateOf.RepoID != issue.RepoID {
+		return issues_model.ErrIssueInvalidDuplicate{ID: issue.ID, DuplicateOfID: duplicateOf.ID}
+	}
+	issue.DuplicateOfID = duplicateOf.ID
+	if issue.IsClosed {
+		return issues_model.UpdateIssueCols(db.DefaultContext, issue, "duplicate_of_id")
+	}
+	return ChangeStatus(issue, doer, true)
+}
+
 // changeStatusCtx changes issue status to open or closed.
 // TODO: if context is not db.DefaultContext we get a deadlock!!!
 func changeStatusCtx(ctx context.Context, issue *issues_model.Issue, doer *user_model.User, closed bool) error {
@@ -39,5 +52,9 @@ func changeStatusCtx(ctx context.Context, issue *issues_model.Issue, doer *user_
 
 	notification.NotifyIssueChangeStatus(ctx, doer, issue, comment, closed)
 
+	if err := SyncMilestoneStatus(ctx, doer, issue); err != nil {
+		log.Error("Unable to sync the status of milestone %d with issue[%d]#%d: %v", issue.MilestoneID, issue.ID, issue.Index, err)
+	}
+
 	return nil
 }
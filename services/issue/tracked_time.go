@@ -0,0 +1,110 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// AddTime adds time spent by user on an issue, as the given doer.
+func AddTime(ctx context.Context, doer, user *user_model.User, issue *issues_model.Issue, amount int64, created time.Time, billable bool) (*issues_model.TrackedTime, error) {
+	return AddTimeMs(ctx, doer, user, issue, amount*1000, created, billable)
+}
+
+// AddTimeMs adds time in milliseconds spent by user on an issue, as the given doer.
+func AddTimeMs(ctx context.Context, doer, user *user_model.User, issue *issues_model.Issue, amountMs int64, created time.Time, billable bool) (*issues_model.TrackedTime, error) {
+	t, err := issues_model.AddTimeMs(user, issue, amountMs, created, billable)
+	if err != nil {
+		return nil, err
+	}
+
+	notification.NotifyIssueAddTrackedTime(ctx, doer, t)
+
+	return t, nil
+}
+
+// DeleteTime deletes a tracked time, as the given doer.
+func DeleteTime(ctx context.Context, doer *user_model.User, t *issues_model.TrackedTime) error {
+	if err := issues_model.DeleteTime(t); err != nil {
+		return err
+	}
+
+	notification.NotifyIssueDeleteTrackedTime(ctx, doer, t)
+
+	return nil
+}
+
+// DeleteIssueUserTimes deletes all times the doer tracked on an issue.
+func DeleteIssueUserTimes(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) error {
+	times, err := issues_model.GetTrackedTimes(ctx, &issues_model.FindTrackedTimesOptions{
+		IssueID: issue.ID,
+		UserID:  doer.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := issues_model.DeleteIssueUserTimes(issue, doer); err != nil {
+		return err
+	}
+
+	for _, t := range times {
+		t.Deleted = true
+		notification.NotifyIssueDeleteTrackedTime(ctx, doer, t)
+	}
+
+	return nil
+}
+
+// GetUserTrackedTimesToday returns the times the user has tracked during the
+// current day of the server timezone, together with their total in seconds.
+// Times tracked on issues the user can no longer read are left out.
+func GetUserTrackedTimesToday(ctx context.Context, user *user_model.User) (api.TrackedTimeList, int64, error) {
+	now := timeutil.TimeStampNow().AsTimeInLocation(setting.DefaultUILocation)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, setting.DefaultUILocation)
+
+	times, err := issues_model.GetTrackedTimes(ctx, &issues_model.FindTrackedTimesOptions{
+		UserID:            user.ID,
+		CreatedAfterUnix:  dayStart.Unix(),
+		CreatedBeforeUnix: dayStart.AddDate(0, 0, 1).Unix() - 1,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := times.LoadAttributes(); err != nil {
+		return nil, 0, err
+	}
+
+	perms := make(map[int64]access_model.Permission)
+	visible := make(issues_model.TrackedTimeList, 0, len(times))
+	var total int64
+	for _, t := range times {
+		perm, ok := perms[t.Issue.RepoID]
+		if !ok {
+			perm, err = access_model.GetUserRepoPermission(ctx, t.Issue.Repo, user)
+			if err != nil {
+				return nil, 0, err
+			}
+			perms[t.Issue.RepoID] = perm
+		}
+		if !perm.CanReadIssuesOrPulls(t.Issue.IsPull) {
+			continue
+		}
+		visible = append(visible, t)
+		total += t.Time
+	}
+
+	apiTimes, _ := convert.ToTrackedTimeList(ctx, visible)
+	return apiTimes, total, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserTrackedTimesToday(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	defer func(loc *time.Location) {
+		setting.DefaultUILocation = loc
+	}(setting.DefaultUILocation)
+	setting.DefaultUILocation = time.FixedZone("UTC+8", 8*60*60)
+
+	now := time.Date(2022, 11, 20, 10, 0, 0, 0, setting.DefaultUILocation)
+	timeutil.Set(now)
+	defer timeutil.Unset()
+
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	// issue 1 is in the public repo1, issue 4 in the private repo2 of user2
+	issue1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	issue4 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 4})
+
+	addTime := func(user *user_model.User, issue *issues_model.Issue, amount int64, created time.Time) int64 {
+		tt, err := issues_model.AddTime(user, issue, amount, created, false)
+		assert.NoError(t, err)
+		// the created column is filled in by xorm, so move it to the wanted time
+		_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE tracked_time SET created_unix = ? WHERE id = ?", created.Unix(), tt.ID)
+		assert.NoError(t, err)
+		return tt.ID
+	}
+
+	// the same UTC day, but the day before in the server timezone
+	addTime(user2, issue1, 100, time.Date(2022, 11, 19, 23, 30, 0, 0, setting.DefaultUILocation))
+	early := addTime(user2, issue1, 200, time.Date(2022, 11, 20, 0, 30, 0, 0, setting.DefaultUILocation))
+	other := addTime(user2, issue4, 300, now)
+	forbidden := addTime(user4, issue4, 400, now)
+	allowed := addTime(user4, issue1, 500, now)
+
+	times, total, err := GetUserTrackedTimesToday(db.DefaultContext, user2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 500, total)
+	if assert.Len(t, times, 2) {
+		assert.EqualValues(t, early, times[0].ID)
+		assert.EqualValues(t, 1, times[0].Issue.Repo.ID)
+		assert.EqualValues(t, other, times[1].ID)
+		assert.EqualValues(t, 2, times[1].Issue.Repo.ID)
+	}
+
+	times, total, err = GetUserTrackedTimesToday(db.DefaultContext, user4)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 500, total)
+	if assert.Len(t, times, 1) {
+		assert.EqualValues(t, allowed, times[0].ID)
+		assert.NotEqualValues(t, forbidden, times[0].ID)
+	}
+}
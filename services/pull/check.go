@@ -28,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 // prPatchCheckerQueue represents a queue to handle update pull request tests
@@ -154,7 +155,8 @@ func checkAndUpdateStatus(ctx context.Context, pr *issues_model.PullRequest) {
 	}
 
 	if !has {
-		if err := pr.UpdateColsIfNotMerged(ctx, "merge_base", "status", "conflicted_files", "changed_protected_files"); err != nil {
+		if err := pr.UpdateColsIfNotMerged(ctx, "merge_base", "status", "conflicted_files", "changed_protected_files",
+			"diff_stats_commit_id", "num_changed_files", "num_additions", "num_deletions", "base_commit_id", "base_changed_unix"); err != nil {
 			log.Error("Update[%d]: %v", pr.ID, err)
 		}
 	}
@@ -278,6 +280,9 @@ func manuallyMerged(ctx context.Context, pr *issues_model.PullRequest) bool {
 		}
 
 		notification.NotifyMergePullRequest(ctx, merger, pr)
+		if err := issue_service.SyncMilestoneStatus(ctx, merger, pr.Issue); err != nil {
+			log.Error("Unable to sync the status of milestone %d with pull request[%d]: %v", pr.Issue.MilestoneID, pr.ID, err)
+		}
 
 		log.Info("manuallyMerged[%d]: Marked as manually merged into %s/%s by commit id: %s", pr.ID, pr.BaseRepo.Name, pr.BaseBranch, commit.ID.String())
 		return true
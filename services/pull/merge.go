@@ -198,6 +198,10 @@ func Merge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.U
 		notification.NotifyMergePullRequest(hammerCtx, doer, pr)
 	}
 
+	if err := issue_service.SyncMilestoneStatus(hammerCtx, doer, pr.Issue); err != nil {
+		log.Error("Unable to sync the status of milestone %d with pull request[%d]: %v", pr.Issue.MilestoneID, pr.ID, err)
+	}
+
 	// Reset cached commit count
 	cache.Remove(pr.Issue.Repo.GetCommitsCountCacheKey(pr.BaseBranch, true))
 
@@ -878,6 +882,9 @@ func MergedManually(pr *issues_model.PullRequest, doer *user_model.User, baseGit
 	}
 
 	notification.NotifyMergePullRequest(baseGitRepo.Ctx, doer, pr)
+	if err := issue_service.SyncMilestoneStatus(baseGitRepo.Ctx, doer, pr.Issue); err != nil {
+		log.Error("Unable to sync the status of milestone %d with pull request[%d]: %v", pr.Issue.MilestoneID, pr.ID, err)
+	}
 	log.Info("manuallyMerged[%d]: Marked as manually merged into %s/%s by commit id: %s", pr.ID, pr.BaseRepo.Name, pr.BaseBranch, commitID)
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
 	"github.com/gobwas/glob"
@@ -91,6 +92,26 @@ func TestPatch(pr *issues_model.PullRequest) error {
 		return fmt.Errorf("GetBranchCommitID: can't find commit ID for head: %w", err)
 	}
 
+	// store the head of the base branch, so that a base which advanced past the merge base can be reported without running git
+	baseCommitID, err := gitRepo.GetRefCommitID(git.BranchPrefix + "base")
+	if err != nil {
+		return fmt.Errorf("GetRefCommitID: can't find commit ID for base: %w", err)
+	}
+	if baseCommitID != pr.BaseCommitID {
+		// the first check only records the base, it has not changed since the pull request was created
+		if pr.BaseCommitID != "" {
+			pr.BaseChangedUnix = timeutil.TimeStampNow()
+		}
+		pr.BaseCommitID = baseCommitID
+	}
+
+	// store the diff stats of the checked head, so that they can be reported without running git
+	pr.DiffStatsCommitID = pr.HeadCommitID
+	if pr.NumChangedFiles, pr.NumAdditions, pr.NumDeletions, err = gitRepo.GetDiffShortStat(pr.MergeBase, "tracking"); err != nil {
+		log.Error("GetDiffShortStat[%d]: %v", pr.ID, err)
+		pr.DiffStatsCommitID = ""
+	}
+
 	if pr.HeadCommitID == pr.MergeBase {
 		pr.Status = issues_model.PullRequestStatusAncestor
 		return nil
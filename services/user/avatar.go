@@ -0,0 +1,145 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"crypto/md5"
+	"fmt"
+	"image/png"
+	"io"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/avatar"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// AvatarScanner inspects the raw data of an uploaded avatar before it is stored,
+// e.g. to check it for malware or for image formats which are not allowed.
+// An avatar is rejected by returning an error.
+type AvatarScanner interface {
+	ScanAvatar(u *user_model.User, data []byte) error
+}
+
+type noopAvatarScanner struct{}
+
+func (noopAvatarScanner) ScanAvatar(*user_model.User, []byte) error {
+	return nil
+}
+
+var avatarScanner AvatarScanner = noopAvatarScanner{}
+
+// SetAvatarScanner sets the scanner used for uploaded avatars, nil restores the default which accepts everything
+func SetAvatarScanner(scanner AvatarScanner) {
+	if scanner == nil {
+		scanner = noopAvatarScanner{}
+	}
+	avatarScanner = scanner
+}
+
+// ErrAvatarRejected represents a "AvatarRejected" kind of error.
+type ErrAvatarRejected struct {
+	UserID int64
+	Reason string
+}
+
+// IsErrAvatarRejected checks if an error is a ErrAvatarRejected.
+func IsErrAvatarRejected(err error) bool {
+	_, ok := err.(ErrAvatarRejected)
+	return ok
+}
+
+func (err ErrAvatarRejected) Error() string {
+	return fmt.Sprintf("avatar rejected [uid: %d, reason: %s]", err.UserID, err.Reason)
+}
+
+func (err ErrAvatarRejected) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
+// scanAvatar runs the avatar scanner, errors which are not already an ErrAvatarRejected are wrapped as one
+func scanAvatar(u *user_model.User, data []byte) error {
+	if err := avatarScanner.ScanAvatar(u, data); err != nil {
+		if IsErrAvatarRejected(err) {
+			return err
+		}
+		return ErrAvatarRejected{UserID: u.ID, Reason: err.Error()}
+	}
+	return nil
+}
+
+// checkAvatar rejects data which is not a supported raster image and runs the avatar scanner on the rest
+func checkAvatar(u *user_model.User, data []byte) error {
+	if err := avatar.CheckContentType(data); err != nil {
+		return err
+	}
+	return scanAvatar(u, data)
+}
+
+// UploadAvatarIfChanged saves the custom avatar for the user unless it is the same as the current one.
+// The data is only compared to the current avatar once it has been checked and the avatar scanner has accepted it.
+func UploadAvatarIfChanged(u *user_model.User, data []byte) error {
+	if err := checkAvatar(u, data); err != nil {
+		return err
+	}
+	if !u.IsUploadAvatarChanged(data) {
+		return nil
+	}
+	return uploadAvatar(u, data)
+}
+
+// UploadDefaultMemberAvatar saves the avatar the organization shows for its members who have no avatar,
+// the previous one is removed once the new one is in use.
+func UploadDefaultMemberAvatar(org *user_model.User, data []byte) error {
+	if err := checkAvatar(org, data); err != nil {
+		return err
+	}
+	m, err := avatar.Prepare(data)
+	if err != nil {
+		return err
+	}
+
+	oldAvatar, err := user_model.GetUserSetting(org.ID, user_model.SettingsKeyDefaultMemberAvatar)
+	if err != nil {
+		return err
+	}
+	newAvatar := fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%d-member-%x", org.ID, md5.Sum(data)))))
+	if newAvatar == oldAvatar {
+		return nil
+	}
+
+	if err := storage.SaveFrom(storage.Avatars, newAvatar, func(w io.Writer) error {
+		return png.Encode(w, *m)
+	}); err != nil {
+		return fmt.Errorf("Failed to create dir %s: %w", newAvatar, err)
+	}
+	if err := user_model.SetUserSetting(org.ID, user_model.SettingsKeyDefaultMemberAvatar, newAvatar); err != nil {
+		return err
+	}
+
+	if oldAvatar != "" {
+		if err := storage.Avatars.Delete(oldAvatar); err != nil {
+			log.Error("Failed to remove %s: %v", oldAvatar, err)
+		}
+	}
+	return nil
+}
+
+// DeleteDefaultMemberAvatar removes the default member avatar of the organization,
+// its members without an avatar get the global default avatar again.
+func DeleteDefaultMemberAvatar(org *user_model.User) error {
+	memberAvatar, err := user_model.GetUserSetting(org.ID, user_model.SettingsKeyDefaultMemberAvatar)
+	if err != nil || memberAvatar == "" {
+		return err
+	}
+
+	if err := user_model.DeleteUserSetting(org.ID, user_model.SettingsKeyDefaultMemberAvatar); err != nil {
+		return err
+	}
+	if err := storage.Avatars.Delete(memberAvatar); err != nil {
+		return fmt.Errorf("Failed to remove %s: %w", memberAvatar, err)
+	}
+	return nil
+}
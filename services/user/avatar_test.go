@@ -0,0 +1,160 @@
+// Copyright 2022 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/avatar"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rejectingAvatarScanner struct {
+	scanned int
+	err     error
+}
+
+func (s *rejectingAvatarScanner) ScanAvatar(u *user_model.User, data []byte) error {
+	s.scanned++
+	return s.err
+}
+
+func TestUploadAvatar_Scanner(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer SetAvatarScanner(nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+	data := buf.Bytes()
+
+	scanner := &rejectingAvatarScanner{err: ErrAvatarRejected{UserID: 2, Reason: "infected"}}
+	SetAvatarScanner(scanner)
+
+	u := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	err := UploadAvatar(u, data)
+	assert.True(t, IsErrAvatarRejected(err))
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	assert.Equal(t, ErrAvatarRejected{UserID: 2, Reason: "infected"}, err)
+	assert.Equal(t, 1, scanner.scanned)
+
+	err = UploadAvatarIfChanged(u, data)
+	assert.True(t, IsErrAvatarRejected(err))
+	assert.Equal(t, 2, scanner.scanned)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2, UseCustomAvatar: false})
+
+	// other scanner errors are reported as rejections as well
+	scanner.err = errors.New("scanner unavailable")
+	err = UploadAvatar(u, data)
+	assert.Equal(t, ErrAvatarRejected{UserID: 2, Reason: "scanner unavailable"}, err)
+
+	// the default scanner accepts the avatar
+	SetAvatarScanner(nil)
+	assert.NoError(t, UploadAvatarIfChanged(u, data))
+	u = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2, UseCustomAvatar: true})
+	assert.False(t, u.IsUploadAvatarChanged(data))
+}
+
+func TestUploadAvatar_ContentType(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer SetAvatarScanner(nil)
+	scanner := &rejectingAvatarScanner{}
+	SetAvatarScanner(scanner)
+
+	// SVG images are rejected before they are scanned or compared to the current avatar
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)
+	u := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	for _, upload := range []func(*user_model.User, []byte) error{UploadAvatar, UploadAvatarIfChanged} {
+		err := upload(u, svg)
+		assert.True(t, avatar.IsErrAvatarUnsupportedType(err))
+	}
+	err := UploadAvatarWithCrop(u, svg, image.Rect(0, 0, 1, 1))
+	assert.True(t, avatar.IsErrAvatarUnsupportedType(err))
+	assert.Zero(t, scanner.scanned)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2, UseCustomAvatar: false})
+
+	// PNG images are accepted and stored as PNG
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+	assert.NoError(t, UploadAvatarIfChanged(u, buf.Bytes()))
+	assert.Equal(t, 1, scanner.scanned)
+	u = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2, UseCustomAvatar: true})
+	rd, err := storage.Avatars.Open(u.CustomAvatarRelativePath())
+	assert.NoError(t, err)
+	defer rd.Close()
+	_, format, err := image.DecodeConfig(rd)
+	assert.NoError(t, err)
+	assert.Equal(t, "png", format)
+}
+
+func TestUploadAvatarWithCrop(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// left half black, right half white
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 20; y++ {
+			if x < 20 {
+				img.Set(x, y, color.Black)
+			} else {
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	data := buf.Bytes()
+
+	u := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, UploadAvatarWithCrop(u, data, image.Rect(0, 0, 20, 20)))
+	u = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2, UseCustomAvatar: true})
+	blackAvatar := u.Avatar
+
+	// uploading the same region again keeps the avatar, a different region replaces it
+	assert.NoError(t, UploadAvatarWithCrop(u, data, image.Rect(0, 0, 20, 20)))
+	assert.Equal(t, blackAvatar, unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}).Avatar)
+	assert.NoError(t, UploadAvatarWithCrop(u, data, image.Rect(20, 0, 40, 20)))
+	assert.NotEqual(t, blackAvatar, unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}).Avatar)
+
+	// regions which are not within the image are rejected
+	for _, crop := range []image.Rectangle{image.Rect(30, 0, 50, 20), image.Rect(-1, 0, 19, 20), image.Rect(10, 10, 10, 10)} {
+		err := UploadAvatarWithCrop(u, data, crop)
+		assert.True(t, avatar.IsErrAvatarCropOutOfBounds(err), "crop %v", crop)
+	}
+}
+
+func TestUploadDefaultMemberAvatar(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	assert.NoError(t, UploadDefaultMemberAvatar(org, buf.Bytes()))
+
+	memberAvatar, err := user_model.GetUserSetting(org.ID, user_model.SettingsKeyDefaultMemberAvatar)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, memberAvatar)
+	_, err = storage.Avatars.Stat(memberAvatar)
+	assert.NoError(t, err)
+
+	user := &user_model.User{ID: 100, UseCustomAvatar: true}
+	assert.Contains(t, user.AvatarLinkWithSizeInOrg(28, memberAvatar), memberAvatar)
+
+	assert.NoError(t, DeleteDefaultMemberAvatar(org))
+	memberAvatarAfterDelete, err := user_model.GetUserSetting(org.ID, user_model.SettingsKeyDefaultMemberAvatar)
+	assert.NoError(t, err)
+	assert.Empty(t, memberAvatarAfterDelete)
+	_, err = storage.Avatars.Stat(memberAvatar)
+	assert.Error(t, err)
+}
@@ -4,9 +4,11 @@
 package user
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"fmt"
+	"image"
 	"image/png"
 	"io"
 	"time"
@@ -228,12 +230,56 @@ func DeleteInactiveUsers(ctx context.Context, olderThan time.Duration) error {
 }
 
 // UploadAvatar saves custom avatar for user.
+// Data which is not a supported raster image is rejected with an avatar.ErrAvatarUnsupportedType,
+// the rest has to be accepted by the avatar scanner first.
 func UploadAvatar(u *user_model.User, data []byte) error {
-	m, err := avatar.Prepare(data)
+	if err := checkAvatar(u, data); err != nil {
+		return err
+	}
+	return uploadAvatar(u, data)
+}
+
+func uploadAvatar(u *user_model.User, data []byte) error {
+	animated, err := avatar.IsAnimated(data)
+	if err != nil {
+		return err
+	}
+	var m *image.Image
+	if !animated {
+		m, err = avatar.Prepare(data)
+		if err != nil {
+			return err
+		}
+	}
+	// animated avatars are stored as uploaded to keep their frames
+	return saveAvatar(u, data, m)
+}
+
+// UploadAvatarWithCrop saves the given region of the uploaded image as custom avatar for the user,
+// unless the result is the same as the current avatar. The data has to be accepted by the avatar scanner first.
+// A crop region which is not within the image is rejected with an avatar.ErrAvatarCropOutOfBounds.
+func UploadAvatarWithCrop(u *user_model.User, data []byte, crop image.Rectangle) error {
+	if err := checkAvatar(u, data); err != nil {
+		return err
+	}
+	m, err := avatar.PrepareWithCrop(data, crop)
 	if err != nil {
 		return err
 	}
+	// the avatar is identified by the cropped image, so uploading the same region again does not change it
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, *m); err != nil {
+		return fmt.Errorf("Encode: %w", err)
+	}
+	if !u.IsUploadAvatarChanged(buf.Bytes()) {
+		return nil
+	}
+	return saveAvatar(u, buf.Bytes(), nil)
+}
 
+// saveAvatar stores the avatar of the user, identified by data. The prepared image m is encoded as PNG,
+// if it is nil data is stored as it is.
+func saveAvatar(u *user_model.User, data []byte, m *image.Image) error {
 	ctx, committer, err := db.TxContext(db.DefaultContext)
 	if err != nil {
 		return err
@@ -250,7 +296,11 @@ func UploadAvatar(u *user_model.User, data []byte) error {
 		return fmt.Errorf("updateUser: %w", err)
 	}
 
-	if err := storage.SaveFrom(storage.Avatars, u.CustomAvatarRelativePath(), func(w io.Writer) error {
+	if m == nil {
+		if _, err := storage.Avatars.Save(u.CustomAvatarRelativePath(), bytes.NewReader(data), int64(len(data))); err != nil {
+			return fmt.Errorf("Failed to create dir %s: %w", u.CustomAvatarRelativePath(), err)
+		}
+	} else if err := storage.SaveFrom(storage.Avatars, u.CustomAvatarRelativePath(), func(w io.Writer) error {
 		if err := png.Encode(w, *m); err != nil {
 			log.Error("Encode: %v", err)
 		}
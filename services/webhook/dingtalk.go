@@ -121,6 +121,20 @@ func (d *DingtalkPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloade
 	return createDingtalkPayload(issueTitle, text+"\r\n\r\n"+p.Comment.Body, "view issue comment", p.Comment.HTMLURL), nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (d *DingtalkPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	text, issueTitle, _ := getIssueTrackedTimePayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(issueTitle, text, "view issue", p.Issue.HTMLURL), nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (d *DingtalkPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	text, milestoneTitle, _ := getMilestonePayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(milestoneTitle, text, "view milestone", getMilestoneHTMLURL(p)), nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (d *DingtalkPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	text, issueTitle, attachmentText, _ := getPullRequestPayloadInfo(p, noneLinkFormatter, true)
@@ -182,6 +182,20 @@ func (d *DiscordPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloader
 	return d.createPayload(p.Sender, title, p.Comment.Body, p.Comment.HTMLURL, color), nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (d *DiscordPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	title, _, color := getIssueTrackedTimePayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, title, "", p.Issue.HTMLURL, color), nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (d *DiscordPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	title, _, color := getMilestonePayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, title, p.Milestone.Description, getMilestoneHTMLURL(p), color), nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (d *DiscordPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	title, _, text, color := getPullRequestPayloadInfo(p, noneLinkFormatter, false)
@@ -120,6 +120,35 @@ func TestDiscordPayload(t *testing.T) {
 		assert.Equal(t, p.Sender.AvatarURL, pl.(*DiscordPayload).Embeds[0].Author.IconURL)
 	})
 
+	t.Run("IssueTrackedTime", func(t *testing.T) {
+		p := issueTrackedTimeTestPayload()
+
+		d := new(DiscordPayload)
+		pl, err := d.IssueTrackedTime(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &DiscordPayload{}, pl)
+
+		assert.Len(t, pl.(*DiscordPayload).Embeds, 1)
+		assert.Equal(t, "[test/repo] Time tracked on issue #2 crash: 1 hour", pl.(*DiscordPayload).Embeds[0].Title)
+		assert.Equal(t, "http://localhost:3000/test/repo/issues/2", pl.(*DiscordPayload).Embeds[0].URL)
+	})
+
+	t.Run("Milestone", func(t *testing.T) {
+		p := milestoneTestPayload()
+
+		d := new(DiscordPayload)
+		pl, err := d.Milestone(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &DiscordPayload{}, pl)
+
+		assert.Len(t, pl.(*DiscordPayload).Embeds, 1)
+		assert.Equal(t, "[test/repo] Milestone closed: v1.0", pl.(*DiscordPayload).Embeds[0].Title)
+		assert.Equal(t, "first release", pl.(*DiscordPayload).Embeds[0].Description)
+		assert.Equal(t, "http://localhost:3000/test/repo/milestone/1", pl.(*DiscordPayload).Embeds[0].URL)
+	})
+
 	t.Run("IssueComment", func(t *testing.T) {
 		p := issueCommentTestPayload()
 
@@ -109,6 +109,20 @@ func (f *FeishuPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloader,
 	return newFeishuTextPayload(issueTitle + "\r\n" + text + "\r\n\r\n" + p.Comment.Body), nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (f *FeishuPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	text, issueTitle, _ := getIssueTrackedTimePayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(issueTitle + "\r\n" + text), nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (f *FeishuPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	text, milestoneTitle, _ := getMilestonePayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(milestoneTitle + "\r\n" + text), nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (f *FeishuPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	text, issueTitle, attachmentText, _ := getPullRequestPayloadInfo(p, noneLinkFormatter, true)
@@ -223,3 +223,58 @@ func getIssueCommentPayloadInfo(p *api.IssueCommentPayload, linkFormatter linkFo
 
 	return text, issueTitle, color
 }
+
+func getIssueTrackedTimePayloadInfo(p *api.IssueTrackedTimePayload, linkFormatter linkFormatter, withSender bool) (string, string, int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	issueTitle := fmt.Sprintf("#%d %s", p.Index, p.Issue.Title)
+	titleLink := linkFormatter(p.Issue.HTMLURL, issueTitle)
+
+	typ := "issue"
+	if p.Issue.PullRequest != nil {
+		typ = "pull request"
+	}
+
+	var text string
+	color := yellowColor
+
+	switch p.Action {
+	case api.HookIssueTrackedTimeAdded:
+		text = fmt.Sprintf("[%s] Time tracked on %s %s: %s", repoLink, typ, titleLink, util.SecToTime(p.TrackedTime.Time))
+		color = greenColor
+	case api.HookIssueTrackedTimeDeleted:
+		text = fmt.Sprintf("[%s] Tracked time deleted on %s %s: %s", repoLink, typ, titleLink, util.SecToTime(p.TrackedTime.Time))
+		color = redColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, issueTitle, color
+}
+
+func getMilestonePayloadInfo(p *api.MilestonePayload, linkFormatter linkFormatter, withSender bool) (string, string, int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	milestoneTitle := p.Milestone.Title
+	titleLink := linkFormatter(getMilestoneHTMLURL(p), milestoneTitle)
+
+	var text string
+	color := yellowColor
+
+	switch p.Action {
+	case api.HookMilestoneClosed:
+		text = fmt.Sprintf("[%s] Milestone closed: %s", repoLink, titleLink)
+		color = greenColor
+	case api.HookMilestoneReopened:
+		text = fmt.Sprintf("[%s] Milestone reopened: %s", repoLink, titleLink)
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, milestoneTitle, color
+}
+
+// getMilestoneHTMLURL returns the URL of the page of the milestone of the payload
+func getMilestoneHTMLURL(p *api.MilestonePayload) string {
+	return fmt.Sprintf("%s/milestone/%d", p.Repository.HTMLURL, p.Milestone.ID)
+}
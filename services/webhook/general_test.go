@@ -166,6 +166,54 @@ func issueCommentTestPayload() *api.IssueCommentPayload {
 	}
 }
 
+func issueTrackedTimeTestPayload() *api.IssueTrackedTimePayload {
+	return &api.IssueTrackedTimePayload{
+		Action: api.HookIssueTrackedTimeAdded,
+		Index:  2,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		Issue: &api.Issue{
+			ID:      2,
+			Index:   2,
+			URL:     "http://localhost:3000/api/v1/repos/test/repo/issues/2",
+			HTMLURL: "http://localhost:3000/test/repo/issues/2",
+			Title:   "crash",
+			Body:    "this happened",
+		},
+		TrackedTime: &api.TrackedTime{
+			ID:   1,
+			Time: 3600,
+		},
+	}
+}
+
+func milestoneTestPayload() *api.MilestonePayload {
+	return &api.MilestonePayload{
+		Action: api.HookMilestoneClosed,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		Milestone: &api.Milestone{
+			ID:          1,
+			Title:       "v1.0",
+			Description: "first release",
+		},
+	}
+}
+
 func pullRequestCommentTestPayload() *api.IssueCommentPayload {
 	return &api.IssueCommentPayload{
 		Action: api.HookIssueCommentCreated,
@@ -593,3 +641,67 @@ func TestGetIssueCommentPayloadInfo(t *testing.T) {
 		assert.Equal(t, c.color, color, "case %d", i)
 	}
 }
+
+func TestGetIssueTrackedTimePayloadInfo(t *testing.T) {
+	p := issueTrackedTimeTestPayload()
+
+	cases := []struct {
+		action     api.HookIssueTrackedTimeAction
+		text       string
+		issueTitle string
+		color      int
+	}{
+		{
+			api.HookIssueTrackedTimeAdded,
+			"[test/repo] Time tracked on issue #2 crash: 1 hour by user1",
+			"#2 crash",
+			greenColor,
+		},
+		{
+			api.HookIssueTrackedTimeDeleted,
+			"[test/repo] Tracked time deleted on issue #2 crash: 1 hour by user1",
+			"#2 crash",
+			redColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		text, issueTitle, color := getIssueTrackedTimePayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.issueTitle, issueTitle, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
+func TestGetMilestonePayloadInfo(t *testing.T) {
+	p := milestoneTestPayload()
+
+	cases := []struct {
+		action         api.HookMilestoneAction
+		text           string
+		milestoneTitle string
+		color          int
+	}{
+		{
+			api.HookMilestoneClosed,
+			"[test/repo] Milestone closed: v1.0 by user1",
+			"v1.0",
+			greenColor,
+		},
+		{
+			api.HookMilestoneReopened,
+			"[test/repo] Milestone reopened: v1.0 by user1",
+			"v1.0",
+			yellowColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		text, milestoneTitle, color := getMilestonePayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.milestoneTitle, milestoneTitle, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
@@ -165,6 +165,20 @@ func (m *MatrixPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	return getMatrixPayload(text, p.Commits, m.MsgType), nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (m *MatrixPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	text, _, _ := getIssueTrackedTimePayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (m *MatrixPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	text, _, _ := getMilestonePayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayload(text, nil, m.MsgType), nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (m *MatrixPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	text, _, _, _ := getPullRequestPayloadInfo(p, MatrixLinkFormatter, true)
@@ -189,6 +189,36 @@ func (m *MSTeamsPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloader
 	), nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (m *MSTeamsPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	title, _, color := getIssueTrackedTimePayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.Issue.HTMLURL,
+		color,
+		&MSTeamsFact{"Issue #:", fmt.Sprintf("%d", p.Issue.ID)},
+	), nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (m *MSTeamsPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	title, _, color := getMilestonePayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		p.Milestone.Description,
+		getMilestoneHTMLURL(p),
+		color,
+		&MSTeamsFact{"Milestone:", p.Milestone.Title},
+	), nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (m *MSTeamsPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	title, _, attachmentText, color := getPullRequestPayloadInfo(p, noneLinkFormatter, false)
@@ -78,6 +78,16 @@ func (f *PackagistPayload) IssueComment(p *api.IssueCommentPayload) (api.Payload
 	return nil, nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (f *PackagistPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	return nil, nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (f *PackagistPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	return nil, nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (f *PackagistPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	return nil, nil
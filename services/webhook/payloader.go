@@ -16,6 +16,8 @@ type PayloadConvertor interface {
 	Fork(*api.ForkPayload) (api.Payloader, error)
 	Issue(*api.IssuePayload) (api.Payloader, error)
 	IssueComment(*api.IssueCommentPayload) (api.Payloader, error)
+	IssueTrackedTime(*api.IssueTrackedTimePayload) (api.Payloader, error)
+	Milestone(*api.MilestonePayload) (api.Payloader, error)
 	Push(*api.PushPayload) (api.Payloader, error)
 	PullRequest(*api.PullRequestPayload) (api.Payloader, error)
 	Review(*api.PullRequestPayload, webhook_model.HookEventType) (api.Payloader, error)
@@ -40,6 +42,10 @@ func convertPayloader(s PayloadConvertor, p api.Payloader, event webhook_model.H
 			return s.IssueComment(pl)
 		}
 		return s.PullRequest(p.(*api.PullRequestPayload))
+	case webhook_model.HookEventIssueTimeTracked:
+		return s.IssueTrackedTime(p.(*api.IssueTrackedTimePayload))
+	case webhook_model.HookEventMilestone:
+		return s.Milestone(p.(*api.MilestonePayload))
 	case webhook_model.HookEventPush:
 		return s.Push(p.(*api.PushPayload))
 	case webhook_model.HookEventPullRequest, webhook_model.HookEventPullRequestAssign, webhook_model.HookEventPullRequestLabel,
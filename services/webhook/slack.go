@@ -211,6 +211,20 @@ func (s *SlackPayload) Push(p *api.PushPayload) (api.Payloader, error) {
 	}}), nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (s *SlackPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	text, _, _ := getIssueTrackedTimePayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (s *SlackPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	text, _, _ := getMilestonePayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (s *SlackPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	text, issueTitle, attachmentText, color := getPullRequestPayloadInfo(p, SlackLinkFormatter, true)
@@ -95,6 +95,30 @@ func TestSlackPayload(t *testing.T) {
 		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] New comment on issue <http://localhost:3000/test/repo/issues/2|#2 crash> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
 	})
 
+	t.Run("IssueTrackedTime", func(t *testing.T) {
+		p := issueTrackedTimeTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.IssueTrackedTime(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Time tracked on issue <http://localhost:3000/test/repo/issues/2|#2 crash>: 1 hour by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+	})
+
+	t.Run("Milestone", func(t *testing.T) {
+		p := milestoneTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.Milestone(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Milestone closed: <http://localhost:3000/test/repo/milestone/1|v1.0> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+	})
+
 	t.Run("PullRequest", func(t *testing.T) {
 		p := pullRequestTestPayload()
 
@@ -199,6 +223,18 @@ func TestSlackJSONPayload(t *testing.T) {
 	assert.NotEmpty(t, json)
 }
 
+func TestGetSlackPayloadIssueTrackedTime(t *testing.T) {
+	p := issueTrackedTimeTestPayload()
+
+	pl, err := GetSlackPayload(p, webhook_model.HookEventIssueTimeTracked, `{"channel":"#test"}`)
+	require.NoError(t, err)
+	require.NotNil(t, pl)
+	require.IsType(t, &SlackPayload{}, pl)
+
+	assert.Equal(t, "#test", pl.(*SlackPayload).Channel)
+	assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Time tracked on issue <http://localhost:3000/test/repo/issues/2|#2 crash>: 1 hour by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
+}
+
 func TestIsValidSlackChannel(t *testing.T) {
 	tt := []struct {
 		channelName string
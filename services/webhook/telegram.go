@@ -132,6 +132,20 @@ func (t *TelegramPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloade
 	return createTelegramPayload(text + "\n" + p.Comment.Body), nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (t *TelegramPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	text, _, _ := getIssueTrackedTimePayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (t *TelegramPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	text, _, _ := getMilestonePayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (t *TelegramPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	text, _, attachmentText, _ := getPullRequestPayloadInfo(p, htmlLinkFormatter, true)
@@ -190,6 +190,8 @@ func PrepareWebhook(ctx context.Context, w *webhook_model.Webhook, event webhook
 		}
 	}
 
+	p = maskIssuePayload(w, p)
+
 	var payloader api.Payloader
 	var err error
 	webhook, ok := webhooks[w.Type]
@@ -214,6 +216,69 @@ func PrepareWebhook(ctx context.Context, w *webhook_model.Webhook, event webhook
 	return enqueueHookTask(task.ID)
 }
 
+// maskIssuePayload returns a copy of the payload with its issue masked as configured for the webhook.
+// Payloads without an issue and webhooks which send issues as they are get the payload unchanged.
+func maskIssuePayload(w *webhook_model.Webhook, p api.Payloader) api.Payloader {
+	if w.HookEvent == nil || w.HookEvent.IssuePayload == (webhook_model.IssuePayloadSettings{}) {
+		return p
+	}
+	settings := w.HookEvent.IssuePayload
+
+	switch payload := p.(type) {
+	case *api.IssuePayload:
+		masked := *payload
+		masked.Issue = MaskAPIIssue(payload.Issue, settings)
+		return &masked
+	case *api.IssueCommentPayload:
+		masked := *payload
+		masked.Issue = MaskAPIIssue(payload.Issue, settings)
+		return &masked
+	case *api.IssueTrackedTimePayload:
+		masked := *payload
+		masked.Issue = MaskAPIIssue(payload.Issue, settings)
+		return &masked
+	}
+	return p
+}
+
+// MaskAPIIssue returns a copy of the issue which leaves out or truncates the body and the emails
+// of the assignees as configured for a webhook. The issue itself is not changed, as the same
+// issue is part of the payloads for all webhooks of a repository.
+func MaskAPIIssue(issue *api.Issue, settings webhook_model.IssuePayloadSettings) *api.Issue {
+	if issue == nil {
+		return nil
+	}
+	masked := *issue
+
+	switch settings.BodyMode {
+	case webhook_model.IssueBodyOmitted:
+		masked.Body = ""
+	case webhook_model.IssueBodyTruncated:
+		maxLength := settings.BodyMaxLength
+		if maxLength <= 0 {
+			maxLength = webhook_model.DefaultIssueBodyMaxLength
+		}
+		masked.Body, _ = util.SplitStringAtRuneN(masked.Body, maxLength)
+	}
+
+	if settings.StripAssigneeEmails {
+		if issue.Assignee != nil {
+			assignee := *issue.Assignee
+			assignee.Email = ""
+			masked.Assignee = &assignee
+		}
+		if issue.Assignees != nil {
+			masked.Assignees = make([]*api.User, len(issue.Assignees))
+			for i, assignee := range issue.Assignees {
+				stripped := *assignee
+				stripped.Email = ""
+				masked.Assignees[i] = &stripped
+			}
+		}
+	}
+	return &masked
+}
+
 // PrepareWebhooks adds new webhooks to task queue for given payload.
 func PrepareWebhooks(ctx context.Context, source EventSource, event webhook_model.HookEventType, p api.Payloader) error {
 	owner := source.Owner
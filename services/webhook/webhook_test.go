@@ -77,6 +77,46 @@ func TestPrepareWebhooksBranchFilterNoMatch(t *testing.T) {
 	}
 }
 
+func TestMaskIssuePayload(t *testing.T) {
+	p := &api.IssuePayload{
+		Action: api.HookIssueOpened,
+		Issue: &api.Issue{
+			Body:      "this happened yesterday",
+			Assignee:  &api.User{UserName: "user2", Email: "user2@example.com"},
+			Assignees: []*api.User{{UserName: "user2", Email: "user2@example.com"}},
+		},
+	}
+
+	full := &webhook_model.Webhook{HookEvent: &webhook_model.HookEvent{}}
+	assert.Same(t, p, maskIssuePayload(full, p))
+
+	masked := &webhook_model.Webhook{HookEvent: &webhook_model.HookEvent{
+		IssuePayload: webhook_model.IssuePayloadSettings{
+			BodyMode:            webhook_model.IssueBodyTruncated,
+			BodyMaxLength:       8,
+			StripAssigneeEmails: true,
+		},
+	}}
+	maskedPayload := maskIssuePayload(masked, p).(*api.IssuePayload)
+	assert.Equal(t, api.HookIssueOpened, maskedPayload.Action)
+	assert.Equal(t, "this ha…", maskedPayload.Issue.Body)
+	assert.Empty(t, maskedPayload.Issue.Assignee.Email)
+	assert.Empty(t, maskedPayload.Issue.Assignees[0].Email)
+
+	// the payload is shared by all webhooks, so it has to stay as it is for the others
+	assert.Equal(t, "this happened yesterday", p.Issue.Body)
+	assert.Equal(t, "user2@example.com", p.Issue.Assignee.Email)
+	assert.Equal(t, "user2@example.com", p.Issue.Assignees[0].Email)
+
+	omitted := &webhook_model.Webhook{HookEvent: &webhook_model.HookEvent{
+		IssuePayload: webhook_model.IssuePayloadSettings{BodyMode: webhook_model.IssueBodyOmitted},
+	}}
+	assert.Empty(t, maskIssuePayload(omitted, p).(*api.IssuePayload).Issue.Body)
+
+	push := &api.PushPayload{}
+	assert.Same(t, push, maskIssuePayload(masked, push))
+}
+
 // TODO TestHookTask_deliver
 
 // TODO TestDeliverHooks
@@ -125,6 +125,22 @@ func (f *WechatworkPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloa
 	return newWechatworkMarkdownPayload(content), nil
 }
 
+// IssueTrackedTime implements PayloadConvertor IssueTrackedTime method
+func (f *WechatworkPayload) IssueTrackedTime(p *api.IssueTrackedTimePayload) (api.Payloader, error) {
+	text, issueTitle, _ := getIssueTrackedTimePayloadInfo(p, noneLinkFormatter, true)
+	content := fmt.Sprintf(" ><font color=\"info\">%s</font>\n ><font color=\"warning\">%s</font> \n [%s](%s)", text, issueTitle, p.Issue.HTMLURL, p.Issue.HTMLURL)
+
+	return newWechatworkMarkdownPayload(content), nil
+}
+
+// Milestone implements PayloadConvertor Milestone method
+func (f *WechatworkPayload) Milestone(p *api.MilestonePayload) (api.Payloader, error) {
+	text, milestoneTitle, _ := getMilestonePayloadInfo(p, noneLinkFormatter, true)
+	content := fmt.Sprintf(" ><font color=\"info\">%s</font>\n ><font color=\"warning\">%s</font> \n [%s](%s)", text, milestoneTitle, getMilestoneHTMLURL(p), getMilestoneHTMLURL(p))
+
+	return newWechatworkMarkdownPayload(content), nil
+}
+
 // PullRequest implements PayloadConvertor PullRequest method
 func (f *WechatworkPayload) PullRequest(p *api.PullRequestPayload) (api.Payloader, error) {
 	text, issueTitle, attachmentText, _ := getPullRequestPayloadInfo(p, noneLinkFormatter, true)
@@ -14,6 +14,7 @@ import (
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -205,3 +206,30 @@ func TestAPIModifyOrgLabels(t *testing.T) {
 	req = NewRequest(t, "DELETE", singleURLStr)
 	session.MakeRequest(t, req, http.StatusNoContent)
 }
+
+func TestAPILabelsWithIssueCounts(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	// label 1 is on two open issues of repo 1, label 2 on a closed one
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/labels?issue_counts=true")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var apiLabels []*api.Label
+	DecodeJSON(t, resp, &apiLabels)
+	if assert.Len(t, apiLabels, 2) {
+		assert.EqualValues(t, 2, apiLabels[0].OpenIssuesCount)
+		assert.EqualValues(t, 0, apiLabels[0].ClosedIssuesCount)
+		assert.EqualValues(t, 0, apiLabels[1].OpenIssuesCount)
+		assert.EqualValues(t, 1, apiLabels[1].ClosedIssuesCount)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/labels/2?issue_counts=true")
+	resp = MakeRequest(t, req, http.StatusOK)
+	apiLabel := new(api.Label)
+	DecodeJSON(t, resp, apiLabel)
+	assert.EqualValues(t, 1, apiLabel.ClosedIssuesCount)
+
+	// the counts are only included on request
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/labels/2")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.NotContains(t, resp.Body.String(), "closed_issues_count")
+}
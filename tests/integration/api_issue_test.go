@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/setting"
@@ -167,6 +168,90 @@ func TestAPIEditIssue(t *testing.T) {
 	assert.Equal(t, title, issueAfter.Title)
 }
 
+func enableAutoCloseMilestones(t *testing.T, repoID int64) {
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repoID})
+	issuesUnit, err := repo.GetUnit(unit.TypeIssues)
+	assert.NoError(t, err)
+	config := issuesUnit.IssuesConfig()
+	config.AutoCloseMilestones = true
+	_, err = db.GetEngine(db.DefaultContext).ID(issuesUnit.ID).Cols("config").Update(&repo_model.RepoUnit{Config: config})
+	assert.NoError(t, err)
+}
+
+func TestAPIEditIssueSyncsMilestone(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	// milestone 1 of repo 1 only has the open pull request 2
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2, MilestoneID: 1})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	enableAutoCloseMilestones(t, repo.ID)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d?token=%s", owner.Name, repo.Name, issue.Index, token)
+
+	state := "closed"
+	req := NewRequestWithJSON(t, "PATCH", urlStr, api.EditIssueOption{State: &state})
+	session.MakeRequest(t, req, http.StatusCreated)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1, IsClosed: true})
+
+	state = "open"
+	req = NewRequestWithJSON(t, "PATCH", urlStr, api.EditIssueOption{State: &state})
+	session.MakeRequest(t, req, http.StatusCreated)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: 1, IsClosed: false})
+}
+
+func TestAPIEditIssueEstimate(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d?token=%s", owner.Name, repo.Name, issue.Index, token)
+
+	estimate := int64(-1)
+	req := NewRequestWithJSON(t, "PATCH", urlStr, api.EditIssueOption{Estimate: &estimate})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	estimate = 3600
+	req = NewRequestWithJSON(t, "PATCH", urlStr, api.EditIssueOption{Estimate: &estimate})
+	session.MakeRequest(t, req, http.StatusCreated)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issue.ID, Estimate: estimate})
+}
+
+func TestAPIEditIssueDuplicateOf(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	duplicateOf := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d?token=%s", owner.Name, repo.Name, issue.Index, token)
+
+	req := NewRequestWithJSON(t, "PATCH", urlStr, api.EditIssueOption{DuplicateOf: &issue.Index})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	missing := int64(9999)
+	req = NewRequestWithJSON(t, "PATCH", urlStr, api.EditIssueOption{DuplicateOf: &missing})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PATCH", urlStr, api.EditIssueOption{DuplicateOf: &duplicateOf.Index})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.Equal(t, api.StateClosed, apiIssue.State)
+	if assert.NotNil(t, apiIssue.DuplicateOf) {
+		assert.Equal(t, duplicateOf.Index, *apiIssue.DuplicateOf)
+	}
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issue.ID, IsClosed: true, DuplicateOfID: duplicateOf.ID})
+}
+
 func TestAPISearchIssues(t *testing.T) {
 	defer tests.PrepareTestEnv(t)()
 
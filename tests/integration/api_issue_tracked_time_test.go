@@ -13,6 +13,8 @@ import (
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/tests"
 
@@ -121,4 +123,76 @@ func TestAPIAddTrackedTimes(t *testing.T) {
 	assert.EqualValues(t, 33, apiNewTime.Time)
 	assert.EqualValues(t, user2.ID, apiNewTime.UserID)
 	assert.EqualValues(t, 947688818, apiNewTime.Created.Unix())
+
+	// milliseconds take precedence, the seconds are rounded down
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.AddTimeOption{Time: 99, TimeMs: 1500})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiNewTime)
+	assert.EqualValues(t, 1, apiNewTime.Time)
+	assert.EqualValues(t, 1500, apiNewTime.TimeMs)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.AddTimeOption{})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
+func TestAPIIssueTimeTrackedWebhook(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	issue2 := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.NoError(t, issue2.LoadRepo(db.DefaultContext))
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	session := loginUser(t, user2.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/hooks?token=%s", user2.Name, issue2.Repo.Name, token), api.CreateHookOption{
+		Type: "gitea",
+		Config: api.CreateHookOptionConfig{
+			"content_type": "json",
+			"url":          "http://example.com/",
+		},
+		Events: []string{string(webhook.HookEventIssueTimeTracked)},
+		Active: true,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiHook *api.Hook
+	DecodeJSON(t, resp, &apiHook)
+
+	lastPayload := func() *api.IssueTrackedTimePayload {
+		task := new(webhook.HookTask)
+		has, err := db.GetEngine(db.DefaultContext).Where("hook_id = ?", apiHook.ID).Desc("id").Get(task)
+		assert.NoError(t, err)
+		assert.True(t, has)
+		assert.Equal(t, webhook.HookEventIssueTimeTracked, task.EventType)
+
+		payload := new(api.IssueTrackedTimePayload)
+		assert.NoError(t, json.Unmarshal([]byte(task.PayloadContent), payload))
+		return payload
+	}
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/times?token=%s", user2.Name, issue2.Repo.Name, issue2.Index, token), &api.AddTimeOption{
+		Time: 42,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiNewTime api.TrackedTime
+	DecodeJSON(t, resp, &apiNewTime)
+
+	payload := lastPayload()
+	assert.Equal(t, api.HookIssueTrackedTimeAdded, payload.Action)
+	assert.Equal(t, issue2.Index, payload.Index)
+	assert.Equal(t, issue2.ID, payload.Issue.ID)
+	assert.Equal(t, apiNewTime.ID, payload.TrackedTime.ID)
+	assert.EqualValues(t, 42, payload.TrackedTime.Time)
+	assert.False(t, payload.TrackedTime.Deleted)
+	assert.Equal(t, user2.Name, payload.Sender.UserName)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/repos/%s/%s/issues/%d/times/%d?token=%s", user2.Name, issue2.Repo.Name, issue2.Index, apiNewTime.ID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	payload = lastPayload()
+	assert.Equal(t, api.HookIssueTrackedTimeDeleted, payload.Action)
+	assert.Equal(t, issue2.ID, payload.Issue.ID)
+	assert.Equal(t, apiNewTime.ID, payload.TrackedTime.ID)
+	assert.EqualValues(t, 42, payload.TrackedTime.Time)
+	assert.True(t, payload.TrackedTime.Deleted)
 }
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -28,6 +29,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/test"
 	"code.gitea.io/gitea/modules/translation"
+	issue_service "code.gitea.io/gitea/services/issue"
 	"code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 	files_service "code.gitea.io/gitea/services/repository/files"
@@ -88,6 +90,36 @@ func TestPullMerge(t *testing.T) {
 	})
 }
 
+func TestPullMergeClosesMilestone(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		session := loginUser(t, "user1")
+		testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+		testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+
+		resp := testPullCreate(t, session, "user1", "repo1", "master", "This is a pull title")
+
+		elem := strings.Split(test.RedirectURL(resp), "/")
+		assert.EqualValues(t, "pulls", elem[3])
+		index, err := strconv.ParseInt(elem[4], 10, 64)
+		assert.NoError(t, err)
+
+		baseRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user2", Name: "repo1"})
+		enableAutoCloseMilestones(t, baseRepo.ID)
+
+		// the pull request is the only issue of a new milestone
+		milestone := &issues_model.Milestone{RepoID: baseRepo.ID, Name: "merge milestone"}
+		assert.NoError(t, issues_model.NewMilestone(milestone))
+		issue, err := issues_model.GetIssueByIndex(baseRepo.ID, index)
+		assert.NoError(t, err)
+		issue.MilestoneID = milestone.ID
+		assert.NoError(t, issue_service.ChangeMilestoneAssign(issue, unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1}), 0))
+
+		testPullMerge(t, session, elem[1], elem[2], elem[4], repo_model.MergeStyleMerge)
+
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: milestone.ID, IsClosed: true})
+	})
+}
+
 func TestPullRebase(t *testing.T) {
 	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
 		hookTasks, err := webhook.HookTasks(1, 1) // Retrieve previous hook number
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path"
+	"strings"
 	"testing"
 
 	"code.gitea.io/gitea/modules/json"
@@ -14,6 +15,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/tests"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,6 +34,44 @@ func TestRepoCommits(t *testing.T) {
 	assert.NotEmpty(t, commitURL)
 }
 
+func TestRepoCommitsSinceUntil(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+
+	commitSHAs := func(query string) []string {
+		req := NewRequest(t, "GET", "/user2/repo2/commits/branch/master?"+query)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+
+		doc := NewHTMLParser(t, resp.Body)
+		var shas []string
+		doc.doc.Find("#commits-table tbody tr td.sha a").Each(func(i int, s *goquery.Selection) {
+			href, _ := s.Attr("href")
+			shas = append(shas, path.Base(href))
+		})
+		return shas
+	}
+
+	// the repository has commits from 2017-11-26, 2020-12-15 and 2021-06-29
+	assert.Len(t, commitSHAs(""), 3)
+	assert.Len(t, commitSHAs("since=2018-01-01T00:00:00Z"), 2)
+	assert.Len(t, commitSHAs("until=2018-01-01T00:00:00Z"), 1)
+
+	shas := commitSHAs("since=2018-01-01T00:00:00Z&until=2021-01-01T00:00:00Z")
+	if assert.Len(t, shas, 1) {
+		assert.True(t, strings.HasPrefix(shas[0], "205ac76"))
+	}
+
+	// the window combines with the pagination
+	shas = commitSHAs("since=2018-01-01T00:00:00Z&limit=1&page=2")
+	if assert.Len(t, shas, 1) {
+		assert.True(t, strings.HasPrefix(shas[0], "205ac76"))
+	}
+
+	req := NewRequest(t, "GET", "/user2/repo2/commits/branch/master?since=yesterday")
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
 func doTestRepoCommitWithStatus(t *testing.T, state string, classes ...string) {
 	defer tests.PrepareTestEnv(t)()
 
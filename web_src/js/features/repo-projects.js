@@ -94,6 +94,7 @@ export default function initRepoProject() {
       '.content > .form > .field > .project-board-title',
     );
     const projectColorInput = $(this).find('.content > .form > .field  #new_board_color');
+    const projectLabelInput = $(this).find('.content > .form > .field > .project-board-label');
     const boardColumn = $(this).closest('.board-column');
 
     if (boardColumn.css('backgroundColor')) {
@@ -107,7 +108,7 @@ export default function initRepoProject() {
 
         $.ajax({
           url: $(this).data('url'),
-          data: JSON.stringify({title: projectTitleInput.val(), color: projectColorInput.val()}),
+          data: JSON.stringify({title: projectTitleInput.val(), color: projectColorInput.val(), label_id: Number(projectLabelInput.val())}),
           headers: {
             'X-Csrf-Token': csrfToken,
           },
@@ -162,10 +163,11 @@ export default function initRepoProject() {
 
     const boardTitle = $('#new_board');
     const projectColorInput = $('#new_board_color_picker');
+    const projectLabelInput = $('#new_board_label');
 
     $.ajax({
       url: $(this).data('url'),
-      data: JSON.stringify({title: boardTitle.val(), color: projectColorInput.val()}),
+      data: JSON.stringify({title: boardTitle.val(), color: projectColorInput.val(), label_id: Number(projectLabelInput.val())}),
       headers: {
         'X-Csrf-Token': csrfToken,
       },